@@ -1,27 +1,48 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"goviz/pkg/graph"
 	"goviz/pkg/output"
 	"goviz/pkg/parser"
+	"goviz/pkg/remote"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
 )
 
 var (
-	analyzeFormat string
-	analyzeOutput string
-	showConflicts bool
-	showOutdated  bool
+	analyzeFormat  string
+	analyzeOutput  string
+	showConflicts  bool
+	showOutdated   bool
+	analyzeStdin   bool
+	analyzeResolve bool
+
+	analyzeExcludeTest bool
+	analyzeRecursive   bool
+	analyzeMaxDepth    int
+	analyzeRepo        string
+	analyzeBinary      string
+	analyzeCounts      bool
+	analyzeDeprecated  bool
+	analyzeSince       string
+	analyzeOnly        string
+
+	maxIndirectRatio float64
 )
 
 var analyzeCmd = &cobra.Command{
-	Use:   "analyze [path]",
+	Use:   "analyze [path | -] [path...]",
 	Short: "Analyze dependencies for conflicts, security issues, and health",
 	Long: `Perform comprehensive analysis of your Go module dependencies.
 	
@@ -30,9 +51,170 @@ This command analyzes:
 - Security vulnerabilities  
 - License compatibility
 - Outdated packages
-- Dependency health metrics`,
-	Args: cobra.MaximumNArgs(1),
+- Dependency health metrics
+
+Pass "-" as the path (or use --stdin) to read go.mod content from stdin.
+go.sum-dependent analysis is skipped in that mode unless --gosum is given.
+
+Several module directories may be given to analyze them together; the
+report then adds a roll-up summary and cross-module version conflicts.
+With --recursive, every go.mod below the given directories is analyzed
+(vendor and testdata directories are skipped).
+
+With --repo, go.mod and go.sum are fetched from a GitHub or GitLab
+repository (github.com/owner/name@ref) instead of a local directory.
+
+With --binary, the module list is read from the build info embedded in a
+compiled Go binary, to audit deployed artifacts without their source:
+
+  goviz analyze --binary ./bin/server
+
+The build info does not record which modules the main module required
+directly, so every module linked into the binary is reported as indirect,
+the statistics give the direct dependencies as unknown and leave out the
+indirect ratio, and --max-indirect-ratio cannot be used.
+
+With --since <git-ref>, go.mod and go.sum are compared with their content
+at that ref and only added or changed dependencies are analyzed, which
+keeps pull request checks fast on large projects.
+
+With --only <module>, every report section is restricted to that exact
+dependency, and its latest release is looked up, for a deep dive on one
+module:
+
+  goviz analyze --only github.com/spf13/cobra
+
+With --deprecations, the go.mod of the latest version of every dependency
+is fetched and modules whose maintainers deprecated them with a
+"// Deprecated:" comment are listed with the notice.
+
+With --group-by license, org or severity, dependencies are also listed in
+groups with a subtotal for each.
+
+--format table prints one aligned row per dependency instead of the prose
+report; long module paths are shortened to --module-width. On very large
+graphs, --limit and --offset page through the table in --sort order:
+
+  goviz analyze --format table --limit 50 --offset 50
+
+With --limit or --offset, the text report also lists that page of the
+dependencies in --sort order, followed by which of them were shown.
+
+The json, jsonl and yaml reports always list every dependency.
+
+--format prometheus writes gauges in the Prometheus text format, such as
+goviz_dependencies_total, goviz_security_issues{severity="high"},
+goviz_risk_score and the per-dependency goviz_dependency_outdated, for the
+node_exporter textfile collector:
+
+  goviz analyze --format prometheus -o /var/lib/node_exporter/goviz.prom
+
+--counts prints only the direct, indirect and total number of requirements
+in go.mod, as one line of text or compact JSON with --format json. go.sum is
+not read and no conflict, license or security analysis runs, so it is the
+fastest way to feed a dashboard:
+
+  goviz analyze --counts --format json
+
+The report includes the indirect ratio, the number of indirect dependencies
+per direct one. With --resolve, it also lists how many modules every direct
+dependency pulls in along requirement edges, heaviest first.
+--max-indirect-ratio turns the ratio into a bloat budget: it implies
+--resolve and exits with status 1 when the ratio exceeds the limit:
+
+  goviz analyze --max-indirect-ratio 5
+
+--exclude <module> drops a dependency from the graph before any analysis,
+so it is missing from every section, format and statistic; the report notes
+what was excluded. It is repeatable and accepts patterns such as
+"github.com/org/*". Modules required by an excluded module are kept, as
+other dependencies may need them too; exclude them explicitly or with a
+pattern to hide a whole subtree.`,
+	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateSortFlag(); err != nil {
+			return err
+		}
+		if err := validateGroupByFlag(); err != nil {
+			return err
+		}
+		if err := validatePageFlags(); err != nil {
+			return err
+		}
+
+		if maxIndirectRatio < 0 {
+			return usageErrorf("--max-indirect-ratio must not be negative")
+		}
+		if maxIndirectRatio > 0 && (analyzeRecursive || len(args) > 1 || analyzeCounts) {
+			return usageErrorf("--max-indirect-ratio cannot be combined with --counts, --recursive or multiple module paths")
+		}
+
+		if analyzeCounts {
+			if len(args) > 1 || analyzeRecursive || analyzeRepo != "" || analyzeBinary != "" || analyzeSince != "" || analyzeOnly != "" {
+				return usageErrorf("--counts only supports a single local module or stdin")
+			}
+			if analyzeFormat != "text" && analyzeFormat != "console" && analyzeFormat != "json" {
+				return usageErrorf("--counts supports --format text or json")
+			}
+			projectPath := "."
+			if len(args) == 1 {
+				projectPath = args[0]
+			}
+			return printDependencyCounts(projectPath)
+		}
+
+		if analyzeRepo != "" {
+			if len(args) > 0 || analyzeRecursive || analyzeStdin {
+				return usageErrorf("--repo cannot be combined with paths, --recursive or --stdin")
+			}
+			if analyzeExcludeTest {
+				return usageErrorf("--exclude-test requires a local checkout and cannot be used with --repo")
+			}
+		}
+
+		if analyzeBinary != "" {
+			if len(args) > 0 || analyzeRecursive || analyzeStdin || analyzeRepo != "" {
+				return usageErrorf("--binary cannot be combined with paths, --recursive, --stdin or --repo")
+			}
+			if analyzeExcludeTest || analyzeSince != "" {
+				return usageErrorf("--exclude-test and --since require a local checkout and cannot be used with --binary")
+			}
+			if maxIndirectRatio > 0 {
+				return usageErrorf("--max-indirect-ratio cannot be used with --binary, whose build info does not record direct dependencies")
+			}
+		}
+
+		if analyzeSince != "" && (analyzeRepo != "" || analyzeStdin || analyzeRecursive || len(args) > 1 || (len(args) == 1 && args[0] == "-")) {
+			return usageErrorf("--since requires a single local module and cannot be combined with --repo, --stdin or --recursive")
+		}
+
+		if analyzeOnly != "" && (analyzeRecursive || len(args) > 1) {
+			return usageErrorf("--only cannot be combined with --recursive or multiple module paths")
+		}
+
+		if analyzeRecursive {
+			discovered, err := discoverModules(args)
+			if err != nil {
+				return err
+			}
+			args = discovered
+		}
+
+		tmpl, err := parseReportTemplate(cmd)
+		if err != nil {
+			return err
+		}
+
+		if len(args) > 1 {
+			if tmpl != nil {
+				return usageErrorf("--template cannot be combined with multiple module paths")
+			}
+			if modFileOverride != "" {
+				return usageErrorf("--modfile cannot be combined with multiple module paths")
+			}
+			return analyzeModules(cmd, args)
+		}
+
 		var projectPath string
 
 		if len(args) == 0 {
@@ -41,49 +223,435 @@ This command analyzes:
 			projectPath = args[0]
 		}
 
+		enhancedGraph, absPath, err := loadAnalysisGraph(projectPath)
+		if err != nil {
+			return err
+		}
+
+		if analyzeSince != "" && len(enhancedGraph.Changes) == 0 && tmpl == nil && (analyzeFormat == "text" || analyzeFormat == "console") {
+			color.New(color.FgGreen, color.Bold).Printf("✅ No dependency changes since %s\n", analyzeSince)
+			printSummary(fmt.Sprintf("no dependency changes since %s", analyzeSince))
+			return nil
+		}
+
+		format := analyzeFormat
+		if tmpl != nil {
+			format = "template"
+		}
+
+		switch format {
+		case "template":
+			err = output.GenerateTemplate(tmpl, enhancedGraph, analyzeOutput, absPath, reportOptions(cmd))
+		case "json":
+			err = output.GenerateJSON(enhancedGraph, analyzeOutput, absPath, reportOptions(cmd))
+		case "yaml":
+			err = output.GenerateYAML(enhancedGraph, analyzeOutput, absPath, reportOptions(cmd))
+		case "jsonl":
+			err = output.GenerateJSONL(enhancedGraph, analyzeOutput, absPath, reportOptions(cmd))
+		case "text", "console":
+			err = generateAnalysisReport(enhancedGraph)
+		case "table":
+			err = writeDependencyTable(enhancedGraph, analyzeOutput)
+		case "prometheus":
+			err = output.GeneratePrometheus([]*graph.EnhancedDependencyGraph{enhancedGraph}, analyzeOutput)
+		default:
+			return usageErrorf("unsupported format: %s. Supported formats: json, jsonl, yaml, text, console, table, prometheus", analyzeFormat)
+		}
+		if err != nil {
+			return err
+		}
+
+		printSummary(analysisSummary(enhancedGraph)...)
+
+		if ratio := enhancedGraph.IndirectRatio(); maxIndirectRatio > 0 && ratio > maxIndirectRatio {
+			message := fmt.Sprintf("indirect ratio %.2f exceeds --max-indirect-ratio %.2f", ratio, maxIndirectRatio)
+			if footprints := enhancedGraph.DependencyFootprints(); len(footprints) > 0 && footprints[0].Transitive > 0 {
+				message += fmt.Sprintf("; heaviest direct dependency: %s (%d modules)", footprints[0].Module, footprints[0].Transitive)
+			}
+			return &FindingsError{Message: message}
+		}
+		return nil
+	},
+}
+
+// fetchRemoteModule downloads go.mod (and go.sum, if present) of a remote
+// repository into a temporary directory, which the caller must remove. It
+// also returns the resolved repository, used as the project path in reports.
+func fetchRemoteModule(spec string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	files, err := remote.NewClient().Fetch(ctx, spec)
+	if err != nil {
+		return "", "", err
+	}
+
+	dir, err := os.MkdirTemp("", "goviz-remote-")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), files.GoMod, 0644); err != nil {
+		os.RemoveAll(dir)
+		return "", "", fmt.Errorf("failed to write go.mod: %w", err)
+	}
+	if files.GoSum != nil {
+		if err := os.WriteFile(filepath.Join(dir, "go.sum"), files.GoSum, 0644); err != nil {
+			os.RemoveAll(dir)
+			return "", "", fmt.Errorf("failed to write go.sum: %w", err)
+		}
+	}
+
+	return dir, files.Repo.String(), nil
+}
+
+// printDependencyCounts parses only go.mod, skipping the enhanced graph and
+// every analysis pass, and prints its requirement counts.
+func printDependencyCounts(projectPath string) error {
+	var modFile *modfile.File
+	if analyzeStdin || projectPath == "-" {
+		var err error
+		modFile, err = parser.ParseGoModReader(os.Stdin, "stdin")
+		if err != nil {
+			return fmt.Errorf("failed to parse go.mod: %w", err)
+		}
+	} else {
 		absPath, err := filepath.Abs(projectPath)
 		if err != nil {
 			return fmt.Errorf("failed to get absolute path: %w", err)
 		}
+		goModPath, err := resolveGoModPath(absPath)
+		if err != nil {
+			return err
+		}
+		modFile, err = parser.ParseGoMod(goModPath)
+		if err != nil {
+			return fmt.Errorf("failed to parse go.mod: %w", err)
+		}
+	}
+
+	return output.GenerateCounts(graph.BuildDependencyGraph(modFile), analyzeFormat, analyzeOutput)
+}
+
+// readBinaryModule writes the go.mod and go.sum equivalent of a binary's
+// build info into a temporary directory, which the caller must remove.
+func readBinaryModule(filename string) (string, error) {
+	files, err := parser.ReadBinary(filename)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(os.Stderr, "Reading build info of %s (built with %s)...\n", filename, files.GoVersion)
+
+	dir, err := os.MkdirTemp("", "goviz-binary-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
 
-		goModPath := filepath.Join(absPath, "go.mod")
-		if _, err := os.Stat(goModPath); os.IsNotExist(err) {
-			return fmt.Errorf("go.mod file not found in %s", absPath)
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), files.GoMod, 0644); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to write go.mod: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.sum"), files.GoSum, 0644); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to write go.sum: %w", err)
+	}
+
+	return dir, nil
+}
+
+// loadAnalysisGraph parses the module at projectPath ("-" for stdin) and
+// runs the full analysis pipeline on it. It returns the graph together with
+// the absolute project path used in reports.
+func loadAnalysisGraph(projectPath string) (*graph.EnhancedDependencyGraph, string, error) {
+	var absPath, projectDir, goModPath string
+	var modFile *modfile.File
+	var err error
+
+	if analyzeBinary != "" {
+		absPath, err = filepath.Abs(analyzeBinary)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get absolute path: %w", err)
+		}
+		dir, err := readBinaryModule(analyzeBinary)
+		if err != nil {
+			return nil, "", err
 		}
+		defer os.RemoveAll(dir)
 
-		fmt.Printf("Analyzing dependencies from %s...\n", absPath)
-		modFile, err := parser.ParseGoMod(goModPath)
+		projectDir = dir
+		modFile, err = parser.ParseGoMod(filepath.Join(dir, "go.mod"))
 		if err != nil {
-			return fmt.Errorf("failed to parse go.mod: %w", err)
+			return nil, "", fmt.Errorf("failed to parse go.mod: %w", err)
+		}
+	} else if analyzeRepo != "" {
+		fmt.Fprintf(os.Stderr, "Fetching go.mod from %s...\n", analyzeRepo)
+		dir, source, err := fetchRemoteModule(analyzeRepo)
+		if err != nil {
+			return nil, "", err
+		}
+		defer os.RemoveAll(dir)
+
+		absPath = source
+		projectDir = dir
+		modFile, err = parser.ParseGoMod(filepath.Join(dir, "go.mod"))
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse go.mod: %w", err)
+		}
+	} else if analyzeStdin || projectPath == "-" {
+		absPath = "-"
+		fmt.Fprintf(os.Stderr, "Analyzing dependencies from stdin...\n")
+		modFile, err = parser.ParseGoModReader(os.Stdin, "stdin")
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse go.mod: %w", err)
+		}
+	} else {
+		absPath, err = filepath.Abs(projectPath)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get absolute path: %w", err)
+		}
+
+		goModPath, err = resolveGoModPath(absPath)
+		if err != nil {
+			return nil, "", err
 		}
 
-		goSumPath := filepath.Join(absPath, "go.sum")
-		enhancedGraph, err := graph.BuildEnhancedDependencyGraph(modFile, goSumPath)
+		projectDir = absPath
+		progress := os.Stdout
+		if (analyzeFormat != "text" && analyzeFormat != "console") || reportTemplate != "" {
+			progress = os.Stderr
+		}
+		fmt.Fprintf(progress, "Analyzing dependencies from %s...\n", absPath)
+		modFile, err = parser.ParseGoMod(goModPath)
 		if err != nil {
-			return fmt.Errorf("failed to build enhanced dependency graph: %w", err)
+			return nil, "", fmt.Errorf("failed to parse go.mod: %w", err)
 		}
+	}
+
+	goSumPath, err := resolveGoSumPath(projectDir)
+	if err != nil {
+		return nil, "", err
+	}
+
+	enhancedGraph, err := graphCache().Build(modFile, goModPath, goSumPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to build enhanced dependency graph: %w", err)
+	}
+	warnIfGoSumMissing(enhancedGraph)
+	if analyzeBinary != "" {
+		enhancedGraph.DirectUnknown = true
+		enhancedGraph.AddWarning(graph.WarningDirectUnknown, "", "the build info of the binary does not record direct dependencies: every module is reported as indirect")
+	}
+	markPrivateModules(enhancedGraph)
+	if err := excludeModules(enhancedGraph); err != nil {
+		return nil, "", err
+	}
 
-		enhancedGraph.DetectVersionConflicts()
-		if err := enhancedGraph.AnalyzeLicenses(); err != nil {
-			return fmt.Errorf("failed to analyze licenses: %w", err)
+	if analyzeExcludeTest {
+		if err := excludeTestDependencies(enhancedGraph, projectDir); err != nil {
+			return nil, "", err
 		}
-		if err := enhancedGraph.CheckSecurity(); err != nil {
-			return fmt.Errorf("failed to check security: %w", err)
+	}
+
+	if analyzeResolve || maxIndirectRatio > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		if err := enhancedGraph.LoadRequirementGraph(ctx, proxyClient()); err != nil {
+			return nil, "", fmt.Errorf("failed to load requirement graph: %w", err)
 		}
+	}
 
-		switch analyzeFormat {
-		case "json":
-			return output.GenerateJSON(enhancedGraph, analyzeOutput, absPath)
-		case "yaml":
-			return output.GenerateYAML(enhancedGraph, analyzeOutput, absPath)
-		case "text", "console":
-			return generateAnalysisReport(enhancedGraph)
-		default:
-			return fmt.Errorf("unsupported format: %s. Supported formats: json, yaml, text, console", analyzeFormat)
+	if analyzeSince != "" {
+		oldGraph, err := loadGraphAtRef(projectDir, goModPath, goSumPath, analyzeSince)
+		if err != nil {
+			return nil, "", err
 		}
-	},
+		enhancedGraph.RetainChanged(enhancedGraph.ChangesSince(oldGraph))
+	}
+
+	if analyzeOnly != "" {
+		if err := retainOnlyModule(enhancedGraph, analyzeOnly); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if analyzeDeprecated {
+		loadDeprecations(enhancedGraph)
+	}
+
+	enhancedGraph.DetectVersionConflicts()
+	if err := enhancedGraph.AnalyzeLicenses(); err != nil {
+		return nil, "", fmt.Errorf("failed to analyze licenses: %w", err)
+	}
+	provider, err := heuristicProvider()
+	if err != nil {
+		return nil, "", err
+	}
+	if err := enhancedGraph.CheckSecurity(provider); err != nil {
+		return nil, "", fmt.Errorf("failed to check security: %w", err)
+	}
+	resolveRepoURLs(enhancedGraph)
+
+	return enhancedGraph, absPath, nil
 }
 
+// retainOnlyModule restricts the graph to the dependency modulePath for
+// --only and records its latest release in UpdateAvailable when it is newer.
+func retainOnlyModule(depGraph *graph.EnhancedDependencyGraph, modulePath string) error {
+	node, exists := depGraph.EnhancedNodes[modulePath]
+	if !exists || modulePath == depGraph.Root.Name {
+		if analyzeSince != "" {
+			return usageErrorf("%s is not a dependency added or changed since %s", modulePath, analyzeSince)
+		}
+		return usageErrorf("%s is not a dependency of %s", modulePath, depGraph.ModuleName)
+	}
+	depGraph.RetainModule(modulePath)
+
+	if node.Private {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	latest, err := proxyClient().LatestVersion(ctx, modulePath)
+	if err != nil {
+		depGraph.AddWarning(graph.WarningUpdateCheckFailed, modulePath, "could not look up the latest version of %s: %v", modulePath, err)
+		return nil
+	}
+	if semver.Compare(latest, node.Version) > 0 {
+		node.UpdateAvailable = latest
+	}
+	return nil
+}
+
+func discoverModules(roots []string) ([]string, error) {
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+
+	var modules []string
+	for _, root := range roots {
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get absolute path: %w", err)
+		}
+
+		found, err := parser.FindModules(absRoot, analyzeMaxDepth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover modules under %s: %w", absRoot, err)
+		}
+		modules = append(modules, found...)
+	}
+
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("no go.mod files found under %s", strings.Join(roots, ", "))
+	}
+
+	fmt.Fprintf(os.Stderr, "Discovered %d modules\n", len(modules))
+	return modules, nil
+}
+
+func analyzeModules(cmd *cobra.Command, paths []string) error {
+	if analyzeStdin {
+		return usageErrorf("--stdin cannot be combined with multiple module paths")
+	}
+	if goSumOverride != "" {
+		return usageErrorf("--gosum cannot be combined with multiple module paths")
+	}
+
+	var graphs []*graph.EnhancedDependencyGraph
+	var absPaths []string
+	for _, path := range paths {
+		if path == "-" {
+			return usageErrorf("stdin (-) cannot be combined with multiple module paths")
+		}
+		enhancedGraph, absPath, err := loadAnalysisGraph(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		graphs = append(graphs, enhancedGraph)
+		absPaths = append(absPaths, absPath)
+	}
+
+	var err error
+	switch analyzeFormat {
+	case "json":
+		err = output.GenerateMultiModuleJSON(graphs, absPaths, analyzeOutput, reportOptions(cmd))
+	case "yaml":
+		err = output.GenerateMultiModuleYAML(graphs, absPaths, analyzeOutput, reportOptions(cmd))
+	case "jsonl":
+		err = output.GenerateMultiModuleJSONL(graphs, absPaths, analyzeOutput, reportOptions(cmd))
+	case "text", "console":
+		for _, enhancedGraph := range graphs {
+			if err := generateAnalysisReport(enhancedGraph); err != nil {
+				return err
+			}
+			fmt.Println()
+		}
+		err = generateMultiModuleSummary(graphs)
+	case "table":
+		if analyzeOutput != "" {
+			return usageErrorf("--output is not supported with --format table for multiple modules")
+		}
+		for _, enhancedGraph := range graphs {
+			fmt.Printf("%s\n\n", enhancedGraph.ModuleName)
+			if err := writeDependencyTable(enhancedGraph, ""); err != nil {
+				return err
+			}
+			fmt.Println()
+		}
+	case "prometheus":
+		err = output.GeneratePrometheus(graphs, analyzeOutput)
+	default:
+		return usageErrorf("unsupported format: %s. Supported formats: json, jsonl, yaml, text, console, table, prometheus", analyzeFormat)
+	}
+	if err != nil {
+		return err
+	}
+
+	printSummary(analysisSummary(graphs...)...)
+	return nil
+}
+
+func generateMultiModuleSummary(graphs []*graph.EnhancedDependencyGraph) error {
+	red := color.New(color.FgRed, color.Bold)
+	green := color.New(color.FgGreen, color.Bold)
+	blue := color.New(color.FgBlue, color.Bold)
+
+	blue.Printf("📦 Multi-Module Summary (%d modules)\n", len(graphs))
+	blue.Printf("==================================\n\n")
+
+	summary := graph.SummarizeModules(graphs)
+	for _, module := range summary.Modules {
+		fmt.Printf("  • %s: %d dependencies (%d direct, %d indirect), %d security issues, %d conflicts\n",
+			module.Name, module.Total, module.Direct, module.Indirect, module.SecurityIssues, module.Conflicts)
+	}
+	fmt.Println()
+
+	fmt.Printf("  Total Dependencies: %d\n", summary.TotalDependencies)
+	fmt.Printf("  Unique Dependencies: %d\n", summary.UniqueDependencies)
+	fmt.Printf("  Security Issues: %d\n", summary.SecurityIssues)
+	fmt.Println()
+
+	conflicts := graph.CrossModuleConflicts(graphs)
+	if len(conflicts) == 0 {
+		green.Printf("✅ No cross-module version conflicts\n")
+		return nil
+	}
+
+	red.Printf("⚡ Cross-Module Version Conflicts (%d):\n", len(conflicts))
+	for _, conflict := range conflicts {
+		fmt.Printf("  • %s\n", conflict.ModulePath)
+		for _, usage := range conflict.Usages {
+			fmt.Printf("    - %s: %s\n", usage.Module, usage.Version)
+		}
+	}
+
+	return nil
+}
+
+// maxFootprints is the number of direct dependencies listed by weight in
+// the text report; the structured formats list all of them.
+const maxFootprints = 10
+
 func generateAnalysisReport(graph *graph.EnhancedDependencyGraph) error {
 
 	red := color.New(color.FgRed, color.Bold)
@@ -100,18 +668,108 @@ func generateAnalysisReport(graph *graph.EnhancedDependencyGraph) error {
 	}
 	fmt.Println()
 
+	if analyzeOnly != "" {
+		printModuleDetails(graph.EnhancedNodes[analyzeOnly])
+	}
+
+	if len(graph.GodebugSettings) > 0 {
+		blue.Printf("🐞 godebug Settings (%d):\n", len(graph.GodebugSettings))
+		fmt.Printf("  These change runtime defaults of binaries built from this module.\n")
+		for _, setting := range graph.GodebugSettings {
+			if setting.Insecure != "" {
+				red.Printf("  ⚠️  %s=%s", setting.Key, setting.Value)
+			} else {
+				fmt.Printf("  • %s=%s", setting.Key, setting.Value)
+			}
+			if setting.DefinedAtLine > 0 {
+				fmt.Printf(" at go.mod:%d", setting.DefinedAtLine)
+			}
+			if setting.Insecure != "" {
+				fmt.Printf(": %s", setting.Insecure)
+			}
+			fmt.Println()
+		}
+		fmt.Println()
+	}
+
+	if graph.Changes != nil {
+		blue.Printf("🔀 Dependency Changes Since %s (%d):\n", analyzeSince, len(graph.Changes))
+		var downgrades []string
+		for _, change := range graph.Changes {
+			switch {
+			case change.OldVersion == "":
+				green.Printf("  + %s %s\n", change.Path, change.NewVersion)
+			case change.NewVersion == "":
+				red.Printf("  - %s %s\n", change.Path, change.OldVersion)
+			case change.Downgrade:
+				downgrades = append(downgrades, fmt.Sprintf("%s %s (%s)", change.Path, versionChange(change), change.Bump))
+			default:
+				fmt.Printf("  ~ %s %s (%s)\n", change.Path, versionChange(change), change.Bump)
+			}
+		}
+		if bumps := bumpSummary(graph.Changes); bumps != "" {
+			fmt.Printf("  Version changes: %s\n", bumps)
+		}
+		fmt.Printf("  Only added and changed dependencies are analyzed below.\n\n")
+
+		if len(downgrades) > 0 {
+			yellow.Printf("⬇️  Downgrades (%d):\n", len(downgrades))
+			for _, downgrade := range downgrades {
+				fmt.Printf("  ↓ %s\n", downgrade)
+			}
+			fmt.Printf("  Check that these rollbacks are intended.\n\n")
+		}
+	}
+
 	stats := graph.GetStatistics()
 	blue.Printf("📊 Statistics:\n")
 	fmt.Printf("  Total Dependencies: %v\n", stats["total_dependencies"])
-	fmt.Printf("  Direct Dependencies: %v\n", stats["direct_dependencies"])
+	if graph.DirectUnknown {
+		fmt.Printf("  Direct Dependencies: unknown (not recorded in the binary's build info)\n")
+	} else {
+		fmt.Printf("  Direct Dependencies: %v\n", stats["direct_dependencies"])
+	}
 	fmt.Printf("  Indirect Dependencies: %v\n", stats["indirect_dependencies"])
 	fmt.Printf("  Transitive Dependencies: %v\n", stats["transitive_dependencies"])
+	if !graph.DirectUnknown {
+		fmt.Printf("  Indirect Ratio: %.2f per direct dependency\n", graph.IndirectRatio())
+	}
 	fmt.Printf("  Unique Licenses: %v\n", stats["unique_licenses"])
+	if len(graph.Excluded) > 0 {
+		fmt.Printf("  Excluded (--exclude, not counted above): %s\n", strings.Join(graph.Excluded, ", "))
+	}
+	fmt.Println()
+
+	if footprints := graph.DependencyFootprints(); len(footprints) > 0 {
+		blue.Printf("🏋️  Heaviest Direct Dependencies:\n")
+		for i, footprint := range footprints {
+			if i == maxFootprints {
+				fmt.Printf("  ... and %d more\n", len(footprints)-maxFootprints)
+				break
+			}
+			fmt.Printf("  • %s: pulls in %d %s\n", footprint.Module, footprint.Transitive, plural(footprint.Transitive, "module", "modules"))
+		}
+		fmt.Println()
+	}
+
+	risk := graph.RiskScore()
+	riskColor := green
+	if risk.Score >= 40 {
+		riskColor = red
+	} else if risk.Score >= 10 {
+		riskColor = yellow
+	}
+	blue.Printf("🛡️  Dependency Risk Score: ")
+	riskColor.Printf("%d/100 (grade %s)\n", risk.Score, risk.Grade)
+	fmt.Printf("  Points: vulnerabilities %d, stale modules %d, unknown licenses %d, conflicts %d\n",
+		risk.Vulnerabilities, risk.Stale, risk.UnknownLicenses, risk.Conflicts)
 	fmt.Println()
 
 	if len(graph.Conflicts) > 0 {
-		red.Printf("⚡ Version Conflicts (%d):\n", len(graph.Conflicts))
-		for _, conflict := range graph.Conflicts {
+		conflicts := sortedConflicts(graph.Conflicts)
+
+		red.Printf("⚡ Version Conflicts (%d):\n", len(conflicts))
+		for _, conflict := range conflicts {
 			fmt.Printf("  • %s: %s vs %s (%s)\n",
 				conflict.ModulePath,
 				conflict.CurrentVersion,
@@ -123,10 +781,33 @@ func generateAnalysisReport(graph *graph.EnhancedDependencyGraph) error {
 		green.Printf("✅ No version conflicts detected\n\n")
 	}
 
+	if graph.Requirements != nil {
+		selected := graph.SelectedVersions()
+		var names []string
+		for name, version := range selected {
+			if node, exists := graph.AllNodes[name]; exists && node.Version != version {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		if len(names) > 0 {
+			yellow.Printf("🧮 Selected Versions Differing From go.mod (%d):\n", len(names))
+			for _, name := range names {
+				fmt.Printf("  • %s: %s → %s\n", name, graph.AllNodes[name].Version, selected[name])
+			}
+			fmt.Println()
+		} else {
+			green.Printf("✅ go.mod versions match minimal version selection\n\n")
+		}
+	}
+
 	if len(graph.SecurityIssues) > 0 {
-		red.Printf("🚨 Security Issues (%d):\n", len(graph.SecurityIssues))
-		for _, issue := range graph.SecurityIssues {
-			fmt.Printf("  • %s [%s]: %s\n", issue.ID, issue.Severity, issue.Description)
+		issues := sortedSecurityIssues(graph.SecurityIssues)
+
+		red.Printf("🚨 Security Issues (%d):\n", len(issues))
+		for _, issue := range issues {
+			fmt.Printf("  • %s@%s: %s [%s]: %s\n", issue.Module, issue.Version, issue.ID, issue.Severity, issue.Description)
 			if issue.FixedIn != "" {
 				fmt.Printf("    Fixed in: %s\n", issue.FixedIn)
 			}
@@ -136,12 +817,36 @@ func generateAnalysisReport(graph *graph.EnhancedDependencyGraph) error {
 		green.Printf("✅ No known security issues\n\n")
 	}
 
+	var licenses []string
+	for license := range graph.LicensesSummary {
+		licenses = append(licenses, license)
+	}
+	sort.Strings(licenses)
+
 	blue.Printf("📄 License Summary:\n")
-	for license, count := range graph.LicensesSummary {
-		fmt.Printf("  • %s: %d packages\n", license, count)
+	for _, license := range licenses {
+		fmt.Printf("  • %s: %d packages\n", license, graph.LicensesSummary[license])
 	}
 	fmt.Println()
 
+	if paged() {
+		printDependencyPage(graph)
+	}
+
+	printDeprecatedModules(graph.DeprecatedNodes())
+
+	if reportGroupBy != "" {
+		printDependencyGroups(graph)
+	}
+
+	if len(graph.Warnings) > 0 {
+		yellow.Printf("⚠️  Warnings (%d):\n", len(graph.Warnings))
+		for _, warning := range graph.SortedWarnings() {
+			fmt.Printf("  • %s\n", warning.Message)
+		}
+		fmt.Println()
+	}
+
 	yellow.Printf("💡 Recommendations:\n")
 	if len(graph.Conflicts) > 0 {
 		fmt.Printf("  • Review and resolve version conflicts\n")
@@ -152,6 +857,9 @@ func generateAnalysisReport(graph *graph.EnhancedDependencyGraph) error {
 	if graph.LicensesSummary["Unknown"] > 0 {
 		fmt.Printf("  • Review licenses for %d unknown packages\n", graph.LicensesSummary["Unknown"])
 	}
+	if deprecated := len(graph.DeprecatedNodes()); deprecated > 0 {
+		fmt.Printf("  • Move off %d deprecated %s, following the deprecation notices\n", deprecated, plural(deprecated, "module", "modules"))
+	}
 	fmt.Printf("  • Consider running 'go mod tidy' to clean up dependencies\n")
 	fmt.Printf("  • Use 'goviz doctor' for detailed package health analysis\n")
 
@@ -159,8 +867,88 @@ func generateAnalysisReport(graph *graph.EnhancedDependencyGraph) error {
 }
 
 func init() {
-	analyzeCmd.Flags().StringVarP(&analyzeFormat, "format", "f", "text", "Output format (json, yaml, text, console)")
+	analyzeCmd.Flags().StringVarP(&analyzeFormat, "format", "f", "text", "Output format (json, jsonl, yaml, text, console, table, prometheus)")
 	analyzeCmd.Flags().StringVarP(&analyzeOutput, "output", "o", "", "Output file (stdout if not specified)")
 	analyzeCmd.Flags().BoolVar(&showConflicts, "conflicts", false, "Show only version conflicts")
 	analyzeCmd.Flags().BoolVar(&showOutdated, "outdated", false, "Show only outdated packages")
+	analyzeCmd.Flags().BoolVar(&analyzeStdin, "stdin", false, "Read go.mod content from stdin")
+	analyzeCmd.Flags().BoolVar(&analyzeExcludeTest, "exclude-test", false, "Exclude dependencies only needed by tests")
+	analyzeCmd.Flags().BoolVarP(&analyzeRecursive, "recursive", "r", false, "Find and analyze every go.mod under the given directories")
+	analyzeCmd.Flags().IntVar(&analyzeMaxDepth, "max-depth", 0, "Maximum directory depth for --recursive (0 for unlimited)")
+	analyzeCmd.Flags().StringVar(&analyzeSince, "since", "", "Only analyze dependencies added or changed since this git ref (e.g. origin/main)")
+	analyzeCmd.Flags().StringVar(&analyzeOnly, "only", "", "Restrict every report section to this dependency (exact module path)")
+	analyzeCmd.Flags().BoolVar(&analyzeCounts, "counts", false, "Print only the direct, indirect and total requirement counts of go.mod")
+	analyzeCmd.Flags().StringVar(&analyzeBinary, "binary", "", "Analyze the modules recorded in a compiled Go binary's build info")
+	analyzeCmd.Flags().StringVar(&analyzeRepo, "repo", "", "Analyze a remote repository without cloning (e.g. github.com/owner/name@ref)")
+	addIncludeRootFlag(analyzeCmd)
+	addSortFlag(analyzeCmd)
+	addGroupByFlag(analyzeCmd)
+	addRepoURLsFlag(analyzeCmd)
+	addTableFlags(analyzeCmd)
+	addSummaryFlags(analyzeCmd)
+	addTemplateFlag(analyzeCmd)
+	addRulesFlag(analyzeCmd)
+	analyzeCmd.Flags().BoolVar(&analyzeDeprecated, "deprecations", false, "Check the latest go.mod of every dependency for a deprecation notice")
+	analyzeCmd.Flags().Float64Var(&maxIndirectRatio, "max-indirect-ratio", 0, "Exit with status 1 when indirect dependencies per direct one exceed this ratio (implies --resolve; 0 to disable)")
+	analyzeCmd.Flags().BoolVar(&analyzeResolve, "resolve", false, "Load dependency go.mod files and apply minimal version selection")
+	addGoSumFlag(analyzeCmd)
+	addExcludeFlag(analyzeCmd)
+}
+
+// printModuleDetails prints the metadata of the module selected with --only.
+func printModuleDetails(node *graph.EnhancedNode) {
+	if node == nil {
+		return
+	}
+
+	blue := color.New(color.FgBlue, color.Bold)
+	blue.Printf("🔬 Module Details:\n")
+	fmt.Printf("  Path: %s\n", node.Name)
+	fmt.Printf("  Version: %s", node.Version)
+	if node.UpdateAvailable != "" {
+		color.New(color.FgYellow).Printf(" (update available: %s)", node.UpdateAvailable)
+	}
+	fmt.Println()
+	if node.Direct {
+		fmt.Printf("  Requirement: direct\n")
+	} else {
+		fmt.Printf("  Requirement: indirect\n")
+	}
+	if node.DefinedAtLine > 0 {
+		fmt.Printf("  Defined at: go.mod:%d\n", node.DefinedAtLine)
+	}
+	if node.Comment != "" {
+		fmt.Printf("  Comment: %s\n", node.Comment)
+	}
+	fmt.Printf("  License: %s\n", node.License)
+	if node.Hash != "" {
+		fmt.Printf("  Hash: %s\n", node.Hash)
+	}
+	if node.RepoURL != "" {
+		fmt.Printf("  Repository: %s\n", node.RepoURL)
+	}
+	if node.IsPseudoVersion && !node.PseudoVersionTime.IsZero() {
+		fmt.Printf("  Pseudo-version from: %s\n", node.PseudoVersionTime.Format("2006-01-02"))
+	}
+	if node.Private {
+		fmt.Printf("  Private: not sent to %s\n", strings.Join(node.NotScannedBy, ", "))
+	}
+	fmt.Println()
+}
+
+func printDependencyGroups(depGraph *graph.EnhancedDependencyGraph) {
+	groups, err := depGraph.GroupDependencies(reportGroupBy)
+	if err != nil {
+		return
+	}
+
+	blue := color.New(color.FgBlue, color.Bold)
+	blue.Printf("🗂️  Dependencies by %s:\n", reportGroupBy)
+	for _, group := range groups {
+		fmt.Printf("\n  %s (%d):\n", group.Key, group.Count)
+		for _, module := range group.Modules {
+			fmt.Printf("    • %s\n", module)
+		}
+	}
+	fmt.Println()
 }
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"goviz/pkg/output"
+	"goviz/pkg/parser"
+	"goviz/pkg/proxy"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	bomFormat          string
+	bomOutput          string
+	bomWithLicenseText bool
+)
+
+var bomCmd = &cobra.Command{
+	Use:   "bom [path]",
+	Short: "Generate a Software Bill of Materials (SBOM)",
+	Long: `Generate a Software Bill of Materials for your Go module.
+
+Supported formats:
+- cyclonedx: CycloneDX 1.5 JSON
+- spdx:      SPDX 2.3 JSON
+
+The root module is recorded as the primary component. Every dependency
+must have a package URL and version; go.sum hashes are included as
+integrity data and detected licenses are attached to each component.
+Generation fails if any required field cannot be filled.
+
+With --with-license-text, the license file of each dependency is read from
+the module cache (run 'go mod download' first) and embedded in the SBOM.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var projectPath string
+
+		if len(args) == 0 {
+			projectPath = "."
+		} else {
+			projectPath = args[0]
+		}
+
+		absPath, err := filepath.Abs(projectPath)
+		if err != nil {
+			return fmt.Errorf("failed to get absolute path: %w", err)
+		}
+
+		goModPath, err := resolveGoModPath(absPath)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "📦 Building SBOM from %s...\n", absPath)
+		modFile, err := parser.ParseGoMod(goModPath)
+		if err != nil {
+			return fmt.Errorf("failed to parse go.mod: %w", err)
+		}
+
+		goSumPath, err := resolveGoSumPath(absPath)
+		if err != nil {
+			return err
+		}
+
+		enhancedGraph, err := graphCache().Build(modFile, goModPath, goSumPath)
+		if err != nil {
+			return fmt.Errorf("failed to build enhanced dependency graph: %w", err)
+		}
+		warnIfGoSumMissing(enhancedGraph)
+		markPrivateModules(enhancedGraph)
+		if err := excludeModules(enhancedGraph); err != nil {
+			return err
+		}
+
+		if err := enhancedGraph.AnalyzeLicenses(); err != nil {
+			return fmt.Errorf("failed to analyze licenses: %w", err)
+		}
+
+		if bomWithLicenseText {
+			found := enhancedGraph.LoadLicenseTexts(proxy.ModCacheDir())
+			fmt.Fprintf(os.Stderr, "📄 Found license text for %d of %d dependencies in the module cache\n", found, len(enhancedGraph.EnhancedNodes)-1)
+		}
+
+		resolveRepoURLs(enhancedGraph)
+
+		switch bomFormat {
+		case "cyclonedx":
+			return output.GenerateCycloneDX(enhancedGraph, Version, bomOutput)
+		case "spdx":
+			return output.GenerateSPDX(enhancedGraph, Version, bomOutput)
+		default:
+			return usageErrorf("unsupported format: %s. Supported formats: cyclonedx, spdx", bomFormat)
+		}
+	},
+}
+
+func init() {
+	bomCmd.Flags().StringVarP(&bomFormat, "format", "f", "cyclonedx", "SBOM format (cyclonedx, spdx)")
+	bomCmd.Flags().StringVarP(&bomOutput, "output", "o", "", "Output file (stdout if not specified)")
+	bomCmd.Flags().BoolVar(&bomWithLicenseText, "with-license-text", false, "Include full license texts from the module cache")
+	addRepoURLsFlag(bomCmd)
+	addGoSumFlag(bomCmd)
+	addExcludeFlag(bomCmd)
+}
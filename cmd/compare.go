@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"goviz/pkg/graph"
+	"goviz/pkg/output"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+var (
+	compareFormat  string
+	compareOutput  string
+	compareResolve bool
+)
+
+var compareCmd = &cobra.Command{
+	Use:     "compare <module> <old-version> <new-version>",
+	Aliases: []string{"compare-versions"},
+	Short:   "Show how a module's dependencies change between two versions",
+	Long: `Compare the requirements of two versions of a published module.
+
+The go.mod of both versions is fetched from the module proxy and the
+dependencies that upgrading would add, remove or change are listed, so an
+upgrade can be weighed before it is made:
+
+  goviz compare github.com/spf13/cobra v1.7.0 v1.9.1
+  goviz compare golang.org/x/net v0.20.0 latest --resolve
+
+Without --resolve only the requirements listed in each go.mod are compared.
+Modules declaring go 1.17 or later list every dependency their packages
+need, so this is usually complete. With --resolve the requirement graphs of
+both versions are loaded from the proxy and the versions selected by
+minimal version selection are compared instead, which also covers older
+modules.`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		modulePath, oldVersion, newVersion := args[0], args[1], args[2]
+		if err := module.CheckPath(modulePath); err != nil {
+			return usageErrorf("invalid module path: %v", err)
+		}
+		for _, version := range []string{oldVersion, newVersion} {
+			if version != "latest" && !semver.IsValid(version) {
+				return usageErrorf("invalid version: %s", version)
+			}
+		}
+
+		switch compareFormat {
+		case "text", "console", "json", "yaml":
+		default:
+			return usageErrorf("unsupported format: %s. Supported formats: text, json, yaml", compareFormat)
+		}
+
+		progress := os.Stdout
+		if compareFormat != "text" && compareFormat != "console" {
+			progress = os.Stderr
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		client := proxyClient()
+
+		var graphs [2]*graph.EnhancedDependencyGraph
+		for i, version := range []string{oldVersion, newVersion} {
+			if version == "latest" {
+				latest, err := client.LatestVersion(ctx, modulePath)
+				if err != nil {
+					return fmt.Errorf("failed to resolve latest version of %s: %w", modulePath, err)
+				}
+				version = latest
+			}
+
+			fmt.Fprintf(progress, "🔎 Fetching go.mod for %s@%s...\n", modulePath, version)
+			enhancedGraph, err := fetchModuleGraph(ctx, client, modulePath, version)
+			if err != nil {
+				return err
+			}
+			if compareResolve {
+				if err := enhancedGraph.LoadRequirementGraph(ctx, client); err != nil {
+					return fmt.Errorf("failed to load requirement graph: %w", err)
+				}
+				if len(enhancedGraph.UnresolvedModules) > 0 {
+					fmt.Fprintf(os.Stderr, "⚠️  Could not load go.mod of %d modules for %s: %s\n",
+						len(enhancedGraph.UnresolvedModules), version, strings.Join(enhancedGraph.UnresolvedModules, ", "))
+				}
+			}
+			graphs[i] = enhancedGraph
+		}
+
+		oldVersions, newVersions := comparedVersions(graphs[0]), comparedVersions(graphs[1])
+		report := output.NewCompareReport(modulePath,
+			output.ComparedVersion{Version: graphs[0].Root.Version, GoVersion: graphs[0].ModuleGoVersion, Dependencies: len(oldVersions)},
+			output.ComparedVersion{Version: graphs[1].Root.Version, GoVersion: graphs[1].ModuleGoVersion, Dependencies: len(newVersions)},
+			compareResolve, graph.DiffVersions(oldVersions, newVersions),
+			output.ReportOptions{Invocation: invocation(cmd)})
+
+		switch compareFormat {
+		case "json":
+			return output.GenerateCompareJSON(report, compareOutput)
+		case "yaml":
+			return output.GenerateCompareYAML(report, compareOutput)
+		default:
+			generateCompareReport(report)
+			return nil
+		}
+	},
+}
+
+// comparedVersions returns the dependency versions of a module version:
+// the versions selected by minimal version selection once the requirement
+// graph is loaded, otherwise those listed in its go.mod.
+func comparedVersions(depGraph *graph.EnhancedDependencyGraph) map[string]string {
+	if depGraph.Requirements != nil {
+		versions := depGraph.SelectedVersions()
+		delete(versions, depGraph.Root.Name)
+		return versions
+	}
+
+	versions := make(map[string]string, len(depGraph.EnhancedNodes))
+	for name, node := range depGraph.EnhancedNodes {
+		if name != depGraph.Root.Name {
+			versions[name] = node.Version
+		}
+	}
+	return versions
+}
+
+func generateCompareReport(report output.CompareReport) {
+	red := color.New(color.FgRed, color.Bold)
+	green := color.New(color.FgGreen, color.Bold)
+	yellow := color.New(color.FgYellow, color.Bold)
+	blue := color.New(color.FgBlue, color.Bold)
+
+	blue.Printf("🔀 Version Comparison\n")
+	blue.Printf("=====================\n\n")
+
+	fmt.Printf("Module: %s\n", report.Module)
+	fmt.Printf("From: %s (%d dependencies)\n", report.From.Version, report.From.Dependencies)
+	fmt.Printf("To: %s (%d dependencies)\n", report.To.Version, report.To.Dependencies)
+	if !report.Resolved {
+		fmt.Printf("Compared: go.mod requirements (use --resolve for the full module graph)\n")
+	}
+	fmt.Println()
+
+	if report.From.GoVersion != report.To.GoVersion && report.To.GoVersion != "" {
+		yellow.Printf("⚠️  go directive changes from %s to %s\n\n", orNone(report.From.GoVersion), report.To.GoVersion)
+	}
+
+	if len(report.Added)+len(report.Removed)+len(report.Changed)+len(report.Downgraded) == 0 {
+		green.Printf("✅ No dependency changes between %s and %s\n", report.From.Version, report.To.Version)
+		return
+	}
+
+	if len(report.Added) > 0 {
+		red.Printf("➕ Added (%d):\n", len(report.Added))
+		for _, change := range report.Added {
+			fmt.Printf("  + %s %s\n", change.Path, change.NewVersion)
+		}
+		fmt.Println()
+	}
+
+	if len(report.Changed) > 0 {
+		yellow.Printf("🔁 Changed (%d):\n", len(report.Changed))
+		for _, change := range report.Changed {
+			fmt.Printf("  ~ %s %s (%s)\n", change.Path, versionChange(change), change.Bump)
+		}
+		fmt.Println()
+	}
+
+	if len(report.Downgraded) > 0 {
+		yellow.Printf("⬇️  Downgraded (%d):\n", len(report.Downgraded))
+		for _, change := range report.Downgraded {
+			fmt.Printf("  ↓ %s %s (%s)\n", change.Path, versionChange(change), change.Bump)
+		}
+		fmt.Println()
+	}
+
+	if len(report.Removed) > 0 {
+		green.Printf("➖ Removed (%d):\n", len(report.Removed))
+		for _, change := range report.Removed {
+			fmt.Printf("  - %s %s\n", change.Path, change.OldVersion)
+		}
+		fmt.Println()
+	}
+
+	blue.Printf("📊 Upgrading takes on %d new, drops %d, changes %d and downgrades %d dependencies\n",
+		len(report.Added), len(report.Removed), len(report.Changed), len(report.Downgraded))
+	if bumps := bumpSummary(append(report.Changed, report.Downgraded...)); bumps != "" {
+		fmt.Printf("🔢 Version changes: %s\n", bumps)
+	}
+}
+
+// versionChange formats "old → new" with the new version colored from the
+// component that changed on, by the kind of bump: major red, minor yellow,
+// patch green and pseudo-versions magenta.
+func versionChange(change graph.ModuleChange) string {
+	version := change.NewVersion
+	start := 0
+	switch change.Bump {
+	case graph.BumpMajor:
+		start = 1
+	case graph.BumpMinor:
+		start = strings.Index(version, ".") + 1
+	case graph.BumpPatch:
+		if first := strings.Index(version, "."); first >= 0 {
+			start = first + 1 + strings.Index(version[first+1:], ".") + 1
+		}
+	}
+	return fmt.Sprintf("%s → %s%s", change.OldVersion, version[:start], bumpColor(change.Bump).Sprint(version[start:]))
+}
+
+func bumpColor(bump string) *color.Color {
+	switch bump {
+	case graph.BumpMajor:
+		return color.New(color.FgRed, color.Bold)
+	case graph.BumpMinor:
+		return color.New(color.FgYellow, color.Bold)
+	case graph.BumpPatch:
+		return color.New(color.FgGreen, color.Bold)
+	default:
+		return color.New(color.FgMagenta, color.Bold)
+	}
+}
+
+// bumpSummary counts the version changes by bump kind, such as "1 major,
+// 3 minor, 5 patch", leaving out kinds without changes.
+func bumpSummary(changes []graph.ModuleChange) string {
+	counts := make(map[string]int)
+	for _, change := range changes {
+		if change.Bump != "" {
+			counts[change.Bump]++
+		}
+	}
+
+	var parts []string
+	for _, bump := range graph.BumpKinds {
+		if counts[bump] > 0 {
+			parts = append(parts, bumpColor(bump).Sprintf("%d %s", counts[bump], bump))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+func init() {
+	compareCmd.Flags().StringVarP(&compareFormat, "format", "f", "text", "Output format (text, json, yaml)")
+	compareCmd.Flags().StringVarP(&compareOutput, "output", "o", "", "Output file")
+	compareCmd.Flags().BoolVar(&compareResolve, "resolve", false, "Compare the full module graphs selected by MVS instead of go.mod requirements")
+}
@@ -1,23 +1,38 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"text/tabwriter"
 	"time"
 
+	"goviz/pkg/golist"
 	"goviz/pkg/graph"
+	"goviz/pkg/output"
 	"goviz/pkg/parser"
+	"goviz/pkg/proxy"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
 )
 
 var (
 	doctorFormat     string
 	doctorOutput     string
 	showOutdatedPkgs bool
+	doctorOverlaps   string
+	doctorProvenance bool
+
+	doctorFailOnStale  bool
+	doctorMaxStaleDays int
+	doctorAllowStale   []string
 )
 
 var doctorCmd = &cobra.Command{
@@ -30,9 +45,68 @@ This command checks:
 - Available updates
 - Last commit/release dates
 - Community health indicators
-- Update recommendations`,
+- Update recommendations
+
+With --packages, the project sources are inspected with 'go list' to find
+modules that are required in go.mod but none of whose packages are imported.
+Unused direct dependencies are reported with the command that removes them,
+and modules the project imports directly while go.mod lists them as
+indirect (or not at all) are reported as candidates for promotion.
+Use --tags to include files behind custom build constraints.
+
+Direct dependencies at v0.0.0 or a pseudo-version are looked up in the module
+proxy to tell modules that have no tagged release at all from commits pinned
+although releases exist, which can usually be replaced with a release.
+
+Modules whose maintainers deprecated them with a "// Deprecated:" comment
+in the go.mod of their latest version are listed with the notice.
+
+Direct dependencies that are well-known alternatives for the same job, such
+as two logging or two HTTP routing libraries, are reported as overlapping.
+More groups can be added with --overlaps:
+
+  overlaps:
+    - name: metrics
+      modules:
+        - github.com/prometheus/client_golang
+        - github.com/rcrowley/go-metrics
+        - github.com/armon/*
+
+Set "defaults: false" in the file to use only its groups.
+
+With --format json or yaml the report lists each recommended action with the
+module, its current and suggested version, the command that applies it and
+the rationale, so automation can open upgrade pull requests:
+
+  goviz doctor --format json | jq '.recommendations[] | select(.kind == "upgrade") | .command'
+
+The latest release of every dependency is looked up in the module proxy to
+tell maintained modules from stale ones. With --fail-on-stale the command
+exits with status 1 when a dependency has had no release for more than
+--max-stale-days days; dependencies that are known to be finished or
+abandoned can be accepted with --allow-stale:
+
+  goviz doctor --fail-on-stale --max-stale-days 730 --allow-stale github.com/pkg/errors
+
+With --provenance every dependency is looked up in the checksum database
+(GOSUMDB as reported by 'go env', sum.golang.org by default, through the
+module proxy when it mirrors it) and the report gives the coverage, such as "42 of 120
+dependencies have verifiable provenance": versions whose hash is recorded in
+the database's transparency log and matches go.sum. Versions the log does
+not know, or records with a different hash than go.sum, are listed; private
+modules, modules without a zip hash in go.sum and failed lookups are counted
+as unknown.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		switch doctorFormat {
+		case "text", "console", "json", "yaml":
+		default:
+			return usageErrorf("unsupported format: %s. Supported formats: text, json, yaml", doctorFormat)
+		}
+		if doctorMaxStaleDays <= 0 {
+			return usageErrorf("--max-stale-days must be positive")
+		}
+
 		var projectPath string
 
 		if len(args) == 0 {
@@ -46,26 +120,126 @@ This command checks:
 			return fmt.Errorf("failed to get absolute path: %w", err)
 		}
 
-		goModPath := filepath.Join(absPath, "go.mod")
-		if _, err := os.Stat(goModPath); os.IsNotExist(err) {
-			return fmt.Errorf("go.mod file not found in %s", absPath)
+		goModPath, err := resolveGoModPath(absPath)
+		if err != nil {
+			return err
 		}
 
-		fmt.Printf("🩺 Analyzing dependency health...\n")
+		progress := os.Stdout
+		if doctorFormat == "json" || doctorFormat == "yaml" {
+			progress = os.Stderr
+		}
+		fmt.Fprintf(progress, "🩺 Analyzing dependency health...\n")
 		modFile, err := parser.ParseGoMod(goModPath)
 		if err != nil {
 			return fmt.Errorf("failed to parse go.mod: %w", err)
 		}
 
-		goSumPath := filepath.Join(absPath, "go.sum")
-		enhancedGraph, err := graph.BuildEnhancedDependencyGraph(modFile, goSumPath)
+		goSumPath, err := resolveGoSumPath(absPath)
+		if err != nil {
+			return err
+		}
+
+		enhancedGraph, err := graphCache().Build(modFile, goModPath, goSumPath)
 		if err != nil {
 			return fmt.Errorf("failed to build enhanced dependency graph: %w", err)
 		}
+		warnIfGoSumMissing(enhancedGraph)
+		markPrivateModules(enhancedGraph)
+		if err := excludeModules(enhancedGraph); err != nil {
+			return err
+		}
 
 		analyzePackageHealth(enhancedGraph)
 
-		return generateHealthReport(enhancedGraph)
+		if err := loadPackageUsage(enhancedGraph, modFile, absPath); err != nil {
+			return err
+		}
+
+		enhancedGraph.LoadPlatformConstraints(proxy.ModCacheDir(), golist.BuildPlatforms)
+
+		overlapGroups := graph.DefaultOverlaps
+		if doctorOverlaps != "" {
+			overlapGroups, err = graph.LoadOverlaps(doctorOverlaps)
+			if err != nil {
+				return err
+			}
+		}
+		enhancedGraph.DetectOverlaps(overlapGroups)
+
+		trustWarnings, err := checksumTrustWarnings(absPath, enhancedGraph)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not inspect the Go environment: %v\n", err)
+		}
+
+		releases := checkUnreleasedDirect(enhancedGraph, proxyClient())
+		availabilityErrors := checkVersionAvailability(enhancedGraph)
+		goVersionFailures := loadGoVersions(enhancedGraph)
+		loadReleaseDates(enhancedGraph)
+		loadDeprecations(enhancedGraph)
+		if doctorProvenance {
+			loadProvenance(enhancedGraph)
+		}
+
+		enhancedGraph.DetectVersionConflicts()
+		fmt.Fprintf(os.Stderr, "⬆️  Looking up the latest releases of direct dependencies...\n")
+		upgrades := planUpgrades(enhancedGraph, modFile, proxyClient())
+		for _, step := range upgrades {
+			if step.LookupFailed != nil && !errors.Is(step.LookupFailed, proxy.ErrPrivate) {
+				enhancedGraph.AddWarning(graph.WarningUpdateCheckFailed, step.Module, "could not look up the latest release: %v", step.LookupFailed)
+			}
+		}
+
+		wellMaintained, outdated, stale := healthCounts(enhancedGraph)
+
+		switch doctorFormat {
+		case "json", "yaml":
+			report := output.NewDoctorReport(enhancedGraph,
+				output.DoctorHealth{Score: healthScore(wellMaintained, outdated, stale), WellMaintained: wellMaintained, Outdated: outdated, Stale: stale},
+				doctorRecommendations(enhancedGraph, upgrades, releases),
+				output.ReportOptions{Invocation: invocation(cmd)})
+			if doctorFormat == "json" {
+				err = output.GenerateDoctorJSON(report, doctorOutput)
+			} else {
+				err = output.GenerateDoctorYAML(report, doctorOutput)
+			}
+		default:
+			err = generateHealthReport(enhancedGraph, trustWarnings, releases, availabilityErrors, goVersionFailures)
+		}
+		if err != nil {
+			return err
+		}
+
+		summary := []string{
+			fmt.Sprintf("health %.0f/100", healthScore(wellMaintained, outdated, stale)),
+			fmt.Sprintf("%d outdated, %d stale%s", outdated, stale, plural(outdated+stale, " dependency", " dependencies")),
+		}
+		if enhancedGraph.PackagesLoaded {
+			unused := len(enhancedGraph.UnusedNodes())
+			missingDirect := len(enhancedGraph.MissingDirectNodes())
+			summary = append(summary,
+				fmt.Sprintf("%d unused direct%s", unused, plural(unused, " dependency", " dependencies")),
+				fmt.Sprintf("%d imported indirect%s", missingDirect, plural(missingDirect, " dependency", " dependencies")),
+			)
+		}
+		if unavailable := len(enhancedGraph.UnavailableNodes()); unavailable > 0 {
+			summary = append(summary, fmt.Sprintf("%d unavailable%s", unavailable, plural(unavailable, " version", " versions")))
+		}
+		printSummary(summary...)
+
+		if !doctorFailOnStale {
+			return nil
+		}
+		staleNodes := enhancedGraph.StaleNodes(time.Duration(doctorMaxStaleDays)*24*time.Hour, doctorAllowStale)
+		if len(staleNodes) == 0 {
+			return nil
+		}
+		offenders := make([]string, 0, len(staleNodes))
+		for _, node := range staleNodes {
+			offenders = append(offenders, fmt.Sprintf("%s (last release %s)", node.Name, node.LatestRelease.Format("2006-01-02")))
+		}
+		return &FindingsError{Message: fmt.Sprintf("%d %s had no release in %d days: %s",
+			len(staleNodes), plural(len(staleNodes), "dependency has", "dependencies have"), doctorMaxStaleDays, strings.Join(offenders, ", "))}
 	},
 }
 
@@ -101,26 +275,68 @@ func analyzePackageHealth(graph *graph.EnhancedDependencyGraph) {
 			node.LastUpdate = now.AddDate(0, -1, 0)
 			node.IsOutdated = false
 		}
+
+		if node.IsPseudoVersion && !node.PseudoVersionTime.IsZero() {
+			node.LastUpdate = node.PseudoVersionTime
+		}
 	}
 }
 
-func generateHealthReport(graph *graph.EnhancedDependencyGraph) error {
-	green := color.New(color.FgGreen, color.Bold)
-	yellow := color.New(color.FgYellow, color.Bold)
-	red := color.New(color.FgRed, color.Bold)
-	blue := color.New(color.FgBlue, color.Bold)
+// checksumTrustWarnings inspects the effective Go environment for settings
+// that disable checksum database verification. When verification is off, a
+// go.sum hash only proves the module is unchanged since it was first
+// downloaded, not that it matches what everyone else sees.
+func checksumTrustWarnings(dir string, depGraph *graph.EnhancedDependencyGraph) ([]string, error) {
+	env, err := golist.Env(dir, "GOSUMDB", "GOFLAGS", "GONOSUMDB", "GONOSUMCHECK", "GOPRIVATE")
+	if err != nil {
+		return nil, err
+	}
 
-	blue.Printf("🩺 Dependency Health Report\n")
-	blue.Printf("============================\n\n")
+	var warnings []string
 
-	fmt.Printf("Module: %s\n", graph.ModuleName)
-	fmt.Printf("Dependencies analyzed: %d\n\n", len(graph.AllNodes)-1)
+	if env["GOSUMDB"] == "off" {
+		warnings = append(warnings, "GOSUMDB=off: checksum database verification is disabled for all modules")
+	}
+	if value := env["GONOSUMCHECK"]; value != "" && value != "0" {
+		warnings = append(warnings, fmt.Sprintf("GONOSUMCHECK=%s: checksum verification is disabled", value))
+	}
+	for _, flag := range strings.Fields(env["GOFLAGS"]) {
+		if flag == "-insecure" || strings.HasPrefix(flag, "-insecure=") {
+			warnings = append(warnings, fmt.Sprintf("GOFLAGS contains %s: module downloads skip verification", flag))
+		}
+	}
+
+	if env["GOSUMDB"] != "off" {
+		variable, patterns := "GONOSUMDB", env["GONOSUMDB"]
+		if patterns == "" {
+			variable, patterns = "GOPRIVATE", env["GOPRIVATE"]
+		}
+
+		var unverified []string
+		if patterns != "" {
+			for name := range depGraph.EnhancedNodes {
+				if name != depGraph.Root.Name && module.MatchPrefixPatterns(patterns, name) {
+					unverified = append(unverified, name)
+				}
+			}
+		}
+		if len(unverified) > 0 {
+			sort.Strings(unverified)
+			warnings = append(warnings, fmt.Sprintf("%s=%s: %d dependencies are not checked against the checksum database: %s",
+				variable, patterns, len(unverified), strings.Join(unverified, ", ")))
+		}
+	}
+
+	return warnings, nil
+}
 
-	var wellMaintained, outdated, stale int
+// healthCounts classifies the dependencies by the time since their last
+// update: under 90 days, under a year, or longer.
+func healthCounts(depGraph *graph.EnhancedDependencyGraph) (wellMaintained, outdated, stale int) {
 	now := time.Now()
 
-	for name, node := range graph.EnhancedNodes {
-		if name == graph.Root.Name {
+	for name, node := range depGraph.EnhancedNodes {
+		if name == depGraph.Root.Name {
 			continue
 		}
 
@@ -135,14 +351,38 @@ func generateHealthReport(graph *graph.EnhancedDependencyGraph) error {
 		}
 	}
 
+	return wellMaintained, outdated, stale
+}
+
+// healthScore rates well-maintained dependencies fully and outdated ones
+// half, out of 100.
+func healthScore(wellMaintained, outdated, stale int) float64 {
+	total := wellMaintained + outdated + stale
+	return float64(wellMaintained*100+outdated*50) / float64(total*100) * 100
+}
+
+func generateHealthReport(graph *graph.EnhancedDependencyGraph, trustWarnings []string, releases []releaseCheck, availabilityErrors map[string]error, goVersionFailures []string) error {
+	green := color.New(color.FgGreen, color.Bold)
+	yellow := color.New(color.FgYellow, color.Bold)
+	red := color.New(color.FgRed, color.Bold)
+	blue := color.New(color.FgBlue, color.Bold)
+
+	blue.Printf("🩺 Dependency Health Report\n")
+	blue.Printf("============================\n\n")
+
+	fmt.Printf("Module: %s\n", graph.ModuleName)
+	fmt.Printf("Dependencies analyzed: %d\n\n", len(graph.AllNodes)-1)
+
+	wellMaintained, outdated, stale := healthCounts(graph)
+	now := time.Now()
+
 	blue.Printf("📊 Health Overview:\n")
 	green.Printf("  ✅ Well-maintained: %d packages\n", wellMaintained)
 	yellow.Printf("  ⚠️  Outdated: %d packages\n", outdated)
 	red.Printf("  🚨 Stale: %d packages\n", stale)
 	fmt.Println()
 
-	total := wellMaintained + outdated + stale
-	healthScore := float64(wellMaintained*100+outdated*50) / float64(total*100) * 100
+	healthScore := healthScore(wellMaintained, outdated, stale)
 
 	blue.Printf("🎯 Overall Health Score: ")
 	if healthScore >= 80 {
@@ -154,6 +394,227 @@ func generateHealthReport(graph *graph.EnhancedDependencyGraph) error {
 	}
 	fmt.Println()
 
+	unused := graph.UnusedNodes()
+	if len(unused) > 0 {
+		red.Printf("🧹 Unused Direct Dependencies (%d):\n", len(unused))
+		fmt.Printf("  None of the packages of these modules are imported by the project or its tests on %s.\n", strings.Join(golist.BuildPlatforms, ", "))
+		for _, node := range unused {
+			fmt.Printf("  • %s (%s)", node.Name, node.Version)
+			if node.DefinedAtLine > 0 {
+				fmt.Printf(" at go.mod:%d", node.DefinedAtLine)
+			}
+			fmt.Println()
+			fmt.Printf("    go get %s@none\n", node.Name)
+		}
+		fmt.Printf("  Or run 'go mod tidy'. Imports behind custom build tags are only seen with --tags.\n")
+		fmt.Println()
+	}
+
+	missingDirect := graph.MissingDirectNodes()
+	if len(missingDirect) > 0 {
+		yellow.Printf("⬆️  Indirect Dependencies Imported Directly (%d):\n", len(missingDirect))
+		fmt.Printf("  The project imports packages of these modules itself, so 'go mod tidy' would require them directly.\n")
+		for _, node := range missingDirect {
+			fmt.Printf("  • %s (%s)", node.Name, node.Version)
+			if node.DefinedAtLine > 0 {
+				fmt.Printf(" marked // indirect at go.mod:%d", node.DefinedAtLine)
+			} else {
+				fmt.Printf(" not listed in go.mod")
+			}
+			fmt.Println()
+			fmt.Printf("    Imports: %s\n", summarizePackages(node.DirectImports))
+		}
+		fmt.Printf("  Run 'go mod tidy' to update go.mod.\n")
+		fmt.Println()
+	}
+
+	unavailable := graph.UnavailableNodes()
+	if len(unavailable) > 0 {
+		red.Printf("🚫 Unavailable Versions (%d):\n", len(unavailable))
+		fmt.Printf("  The module proxy no longer serves these versions, so builds without a warm module cache will fail.\n")
+		for _, node := range unavailable {
+			fmt.Printf("  • %s (%s)%s\n", node.Name, node.Version, goModLine(node))
+			if node.Direct {
+				fmt.Printf("    go get %s@latest\n", node.Name)
+			}
+		}
+		fmt.Println()
+	}
+	if len(availabilityErrors) > 0 {
+		var names []string
+		for name := range availabilityErrors {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		yellow.Printf("⚠️  Could not verify %d pinned %s against the module proxy: %v\n\n",
+			len(names), plural(len(names), "version", "versions"), availabilityErrors[names[0]])
+	}
+
+	goVersionNodes := graph.GoVersionNodes()
+	effectiveGo := graph.EffectiveGoVersion()
+	if len(goVersionNodes) > 0 {
+		blue.Printf("🐹 Go Version Requirements:\n")
+		fmt.Printf("  Main module: go %s\n", orNone(graph.ModuleGoVersion))
+		fmt.Printf("  Highest dependency requirement: go %s (%s)\n", goVersionNodes[0].GoVersion, goVersionNodes[0].Name)
+		if effectiveGo != graph.ModuleGoVersion {
+			yellow.Printf("  ⚠️  Dependencies require a newer Go than the go directive: the module effectively needs go %s\n", effectiveGo)
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "  MODULE\tGO\n")
+		for _, node := range goVersionNodes {
+			marker := ""
+			if node.NeedsNewerGo(graph.ModuleGoVersion) {
+				marker = "  (above main module)"
+			}
+			fmt.Fprintf(tw, "  %s\t%s%s\n", node.Name, node.GoVersion, marker)
+		}
+		tw.Flush()
+		if len(goVersionFailures) > 0 {
+			fmt.Printf("  Could not read the go.mod of %d %s: %s\n", len(goVersionFailures),
+				plural(len(goVersionFailures), "module", "modules"), summarizePackages(goVersionFailures))
+		}
+		fmt.Println()
+	}
+
+	constrained := graph.PlatformConstrainedNodes()
+	if len(constrained) > 0 {
+		yellow.Printf("🧩 Platform-specific Dependencies (%d):\n", len(constrained))
+		fmt.Printf("  These modules use cgo or do not build on all of %s, which complicates cross-compilation.\n", strings.Join(golist.BuildPlatforms, ", "))
+		for _, node := range constrained {
+			fmt.Printf("  • %s (%s)\n", node.Name, node.Version)
+			if node.RequiresCGO {
+				fmt.Printf("    cgo: %s\n", summarizePackages(node.CGOPackages))
+			}
+			if len(node.UnsupportedOS) > 0 {
+				fmt.Printf("    No Go files for: %s\n", strings.Join(node.UnsupportedOS, ", "))
+			}
+		}
+		fmt.Println()
+	}
+	if platformUnchecked := graph.PlatformUncheckedNodes(); len(platformUnchecked) > 0 {
+		fmt.Printf("  Platform constraints not checked for %d %s not in the module cache; run 'go mod download' to include them.\n\n",
+			len(platformUnchecked), plural(len(platformUnchecked), "module", "modules"))
+	}
+
+	printDeprecatedModules(graph.DeprecatedNodes())
+	printProvenance(graph)
+
+	if len(graph.Overlaps) > 0 {
+		yellow.Printf("🧰 Overlapping Dependencies (%d):\n", len(graph.Overlaps))
+		fmt.Printf("  These direct dependencies do the same job; settling on one reduces the code to build, audit and update.\n")
+		for _, overlap := range graph.Overlaps {
+			fmt.Printf("  • %s: %s\n", overlap.Group, strings.Join(overlap.Modules, ", "))
+		}
+		fmt.Println()
+	}
+
+	var noRelease, releasedUpstream, unchecked []releaseCheck
+	for _, check := range releases {
+		switch {
+		case check.Err != nil:
+			unchecked = append(unchecked, check)
+		case check.Latest == "":
+			noRelease = append(noRelease, check)
+		default:
+			releasedUpstream = append(releasedUpstream, check)
+		}
+	}
+
+	if len(noRelease) > 0 || len(releasedUpstream) > 0 || len(unchecked) > 0 {
+		yellow.Printf("🏷️  Unreleased Direct Dependencies (%d):\n", len(releases))
+		if len(noRelease) > 0 {
+			fmt.Printf("  No tagged release exists upstream; ask the maintainers for one or vendor the code you rely on:\n")
+			for _, check := range noRelease {
+				fmt.Printf("  • %s (%s)%s\n", check.Node.Name, check.Node.Version, goModLine(check.Node))
+			}
+		}
+		if len(releasedUpstream) > 0 {
+			fmt.Printf("  Pinned to a commit although releases exist; prefer a tagged release:\n")
+			for _, check := range releasedUpstream {
+				fmt.Printf("  • %s (%s)%s\n", check.Node.Name, check.Node.Version, goModLine(check.Node))
+				if semver.Compare(check.Latest, check.Node.Version) > 0 {
+					fmt.Printf("    go get %s@%s\n", check.Node.Name, check.Latest)
+				} else {
+					fmt.Printf("    Latest release %s predates this commit; wait for the next release or go back to %s\n", check.Latest, check.Latest)
+				}
+			}
+		}
+		if len(unchecked) > 0 {
+			fmt.Printf("  Could not check releases for:\n")
+			for _, check := range unchecked {
+				if errors.Is(check.Err, proxy.ErrPrivate) {
+					fmt.Printf("  • %s (%s): private, not requested from module proxy\n", check.Node.Name, check.Node.Version)
+				} else {
+					fmt.Printf("  • %s (%s): %v\n", check.Node.Name, check.Node.Version, check.Err)
+				}
+			}
+		}
+		fmt.Println()
+	}
+
+	if pseudoNodes := graph.PseudoVersionNodes(); len(pseudoNodes) > 0 {
+		yellow.Printf("📌 Pseudo-versions (%d):\n", len(pseudoNodes))
+		fmt.Printf("  These dependencies are pinned to a commit rather than a tagged release.\n")
+		for _, node := range pseudoNodes {
+			fmt.Printf("  • %s (%s)", node.Name, node.Version)
+			if node.DefinedAtLine > 0 {
+				fmt.Printf(" at go.mod:%d", node.DefinedAtLine)
+			}
+			fmt.Println()
+			fmt.Printf("    Commit: %s", node.PseudoVersionRev)
+			if !node.PseudoVersionTime.IsZero() {
+				fmt.Printf(" from %s", node.PseudoVersionTime.Format("2006-01-02"))
+			}
+			fmt.Println()
+		}
+		fmt.Println()
+	}
+
+	localReplacements, moduleReplacements := splitReplacements(graph.Replacements)
+
+	if len(localReplacements) > 0 {
+		red.Printf("🚧 Release Blockers (%d):\n", len(localReplacements))
+		fmt.Printf("  These replace directives point at local directories, which do not exist for consumers of this module.\n")
+		for _, replacement := range localReplacements {
+			fmt.Printf("  • go.mod:%d: %s => %s\n", replacement.DefinedAtLine, formatModuleVersion(replacement.Old), replacement.New.Path)
+		}
+		fmt.Println()
+	}
+
+	if len(moduleReplacements) > 0 {
+		blue.Printf("🔀 Module Replacements (%d):\n", len(moduleReplacements))
+		for _, replacement := range moduleReplacements {
+			fmt.Printf("  • go.mod:%d: %s => %s\n", replacement.DefinedAtLine, formatModuleVersion(replacement.Old), formatModuleVersion(replacement.New))
+		}
+		fmt.Println()
+	}
+
+	if graph.PackagesLoaded {
+		notImported := graph.NotImportedNodes()
+		blue.Printf("📦 Package Usage:\n")
+		fmt.Printf("  %d of %d modules provide packages imported by the project or its tests\n",
+			len(graph.EnhancedNodes)-1-len(notImported), len(graph.EnhancedNodes)-1)
+		if indirect := len(notImported) - len(unused); indirect > 0 {
+			fmt.Printf("  Indirect modules not imported (%d), possibly still required for version selection:\n", indirect)
+			for _, node := range notImported {
+				if !node.Unused {
+					fmt.Printf("  • %s (%s)\n", node.Name, node.Version)
+				}
+			}
+		}
+		fmt.Println()
+	}
+
+	if len(trustWarnings) > 0 {
+		red.Printf("🔐 Supply-chain Hygiene:\n")
+		fmt.Printf("  Checksum verification is weakened, so go.sum hashes are not validated against the checksum database.\n")
+		for _, warning := range trustWarnings {
+			fmt.Printf("  ⚠️  %s\n", warning)
+		}
+		fmt.Println()
+	}
+
 	if showOutdatedPkgs || outdated > 0 || stale > 0 {
 		blue.Printf("📋 Package Details:\n")
 
@@ -173,6 +634,9 @@ func generateHealthReport(graph *graph.EnhancedDependencyGraph) error {
 			}
 		}
 
+		sort.Strings(outdatedPackages)
+		sort.Strings(stalePackages)
+
 		if len(outdatedPackages) > 0 {
 			yellow.Printf("\n⚠️  Outdated packages (3-12 months):\n")
 			for _, pkg := range outdatedPackages {
@@ -207,6 +671,54 @@ func generateHealthReport(graph *graph.EnhancedDependencyGraph) error {
 		fmt.Printf("     • Check if packages are still maintained\n")
 	}
 
+	if pseudoCount := len(graph.PseudoVersionNodes()); pseudoCount > 0 {
+		fmt.Printf("  📌 Pin %d pseudo-versioned packages to tagged releases where available\n", pseudoCount)
+	}
+
+	if len(unused) > 0 {
+		fmt.Printf("  🧹 Remove %d unused direct dependencies ('go mod tidy')\n", len(unused))
+	}
+
+	if len(missingDirect) > 0 {
+		fmt.Printf("  ⬆️  Require %d directly imported modules directly ('go mod tidy')\n", len(missingDirect))
+	}
+
+	if effectiveGo != graph.ModuleGoVersion {
+		fmt.Printf("  🐹 Raise the go directive to %s to match what dependencies require ('go get go@%s')\n", effectiveGo, effectiveGo)
+	}
+
+	if len(unavailable) > 0 {
+		fmt.Printf("  🚫 Move %d dependencies off versions the module proxy no longer serves\n", len(unavailable))
+	}
+
+	if cgo := countCGO(constrained); cgo > 0 {
+		fmt.Printf("  🧩 Cross-compiling needs a C toolchain for %d cgo dependencies, or CGO_ENABLED=0 where they provide a fallback\n", cgo)
+	}
+
+	if deprecated := len(graph.DeprecatedNodes()); deprecated > 0 {
+		fmt.Printf("  🪦 Move off %d deprecated %s, following the deprecation notices\n", deprecated, plural(deprecated, "module", "modules"))
+	}
+
+	if mismatch := graph.ProvenanceCoverage().Mismatch; mismatch > 0 {
+		fmt.Printf("  🔏 Check the go.sum entries of %d %s whose hash differs from the checksum database\n", mismatch, plural(mismatch, "module", "modules"))
+	}
+
+	if len(graph.Overlaps) > 0 {
+		fmt.Printf("  🧰 Consolidate %d %s of overlapping dependencies\n", len(graph.Overlaps), plural(len(graph.Overlaps), "group", "groups"))
+	}
+
+	if len(releasedUpstream) > 0 {
+		fmt.Printf("  🏷️  Move %d commit-pinned direct dependencies to tagged releases\n", len(releasedUpstream))
+	}
+
+	if len(localReplacements) > 0 {
+		fmt.Printf("  🚧 Remove %d local replace directives before releasing\n", len(localReplacements))
+	}
+
+	if len(trustWarnings) > 0 {
+		fmt.Printf("  🔐 Re-enable checksum database verification for public modules (GOSUMDB, GONOSUMDB)\n")
+	}
+
 	if outdated > 0 {
 		fmt.Printf("  ⚠️  Update %d outdated packages\n", outdated)
 		fmt.Printf("     • Run 'go get -u' to update to latest versions\n")
@@ -239,4 +751,332 @@ func init() {
 	doctorCmd.Flags().StringVarP(&doctorFormat, "format", "f", "text", "Output format (text, json, yaml)")
 	doctorCmd.Flags().StringVarP(&doctorOutput, "output", "o", "", "Output file")
 	doctorCmd.Flags().BoolVar(&showOutdatedPkgs, "show-outdated", true, "Show detailed outdated package information")
+	doctorCmd.Flags().BoolVar(&doctorFailOnStale, "fail-on-stale", false, "Exit with status 1 if a dependency has had no release for --max-stale-days")
+	doctorCmd.Flags().IntVar(&doctorMaxStaleDays, "max-stale-days", 365, "Days without a release after which a dependency is stale")
+	doctorCmd.Flags().StringSliceVar(&doctorAllowStale, "allow-stale", nil, "Module paths accepted as stale by --fail-on-stale")
+	doctorCmd.Flags().BoolVar(&doctorProvenance, "provenance", false, "Look up every dependency in the checksum database and report provenance coverage")
+	doctorCmd.Flags().StringVar(&doctorOverlaps, "overlaps", "", "YAML file with groups of alternative modules added to the defaults")
+	addPackagesFlag(doctorCmd)
+	addGoSumFlag(doctorCmd)
+	addExcludeFlag(doctorCmd)
+	addSummaryFlags(doctorCmd)
+}
+
+// doctorRecommendations lists the actions behind the text report's update
+// recommendations, ordered by module. A module gets at most one version
+// change: an unused dependency is removed rather than upgraded, and a
+// commit-pinned one is only moved to a release when no upgrade is planned.
+func doctorRecommendations(depGraph *graph.EnhancedDependencyGraph, upgrades []upgradeStep, releases []releaseCheck) []output.Recommendation {
+	var recommendations []output.Recommendation
+	planned := make(map[string]bool)
+
+	for _, node := range depGraph.UnusedNodes() {
+		planned[node.Name] = true
+		recommendations = append(recommendations, output.Recommendation{
+			Kind:           output.RecommendRemove,
+			Module:         node.Name,
+			CurrentVersion: node.Version,
+			Command:        fmt.Sprintf("go get %s@none", node.Name),
+			Rationale:      "direct dependency none of whose packages are imported by the project or its tests",
+		})
+	}
+
+	for _, node := range depGraph.UnavailableNodes() {
+		if !node.Direct || planned[node.Name] {
+			continue
+		}
+		planned[node.Name] = true
+		recommendations = append(recommendations, output.Recommendation{
+			Kind:           output.RecommendUnavailable,
+			Module:         node.Name,
+			CurrentVersion: node.Version,
+			Command:        fmt.Sprintf("go get %s@latest", node.Name),
+			Rationale:      "the module proxy no longer serves this version, so builds without a warm module cache fail",
+		})
+	}
+
+	for _, step := range upgrades {
+		if step.NewerMajor != "" && !planned[step.Module] {
+			path, version, _ := strings.Cut(step.NewerMajor, "@")
+			recommendations = append(recommendations, output.Recommendation{
+				Kind:             output.RecommendMajorUpgrade,
+				Module:           step.Module,
+				CurrentVersion:   step.Current,
+				SuggestedVersion: version,
+				Command:          fmt.Sprintf("go get %s", step.NewerMajor),
+				Rationale:        fmt.Sprintf("new major version; imports must change to %s", path),
+			})
+		}
+		if step.Latest == "" || step.BlockedBy != "" || planned[step.Module] {
+			continue
+		}
+		planned[step.Module] = true
+		recommendations = append(recommendations, output.Recommendation{
+			Kind:             output.RecommendUpgrade,
+			Module:           step.Module,
+			CurrentVersion:   step.Current,
+			SuggestedVersion: step.Latest,
+			Command:          fmt.Sprintf("go get %s@%s", step.Module, step.Latest),
+			Rationale:        fmt.Sprintf("latest release of %s, so the import path is unchanged", semver.Major(step.Latest)),
+		})
+	}
+
+	for _, check := range releases {
+		if check.Err != nil || planned[check.Node.Name] || semver.Compare(check.Latest, check.Node.Version) <= 0 {
+			continue
+		}
+		planned[check.Node.Name] = true
+		recommendations = append(recommendations, output.Recommendation{
+			Kind:             output.RecommendPinRelease,
+			Module:           check.Node.Name,
+			CurrentVersion:   check.Node.Version,
+			SuggestedVersion: check.Latest,
+			Command:          fmt.Sprintf("go get %s@%s", check.Node.Name, check.Latest),
+			Rationale:        "pinned to a commit although tagged releases exist",
+		})
+	}
+
+	for _, node := range depGraph.MissingDirectNodes() {
+		recommendations = append(recommendations, output.Recommendation{
+			Kind:           output.RecommendRequireDirect,
+			Module:         node.Name,
+			CurrentVersion: node.Version,
+			Command:        "go mod tidy",
+			Rationale:      fmt.Sprintf("imported directly by the project (%s) but not required directly in go.mod", summarizePackages(node.DirectImports)),
+		})
+	}
+
+	for _, node := range depGraph.ProvenanceNodes(graph.ProvenanceMismatch) {
+		recommendations = append(recommendations, output.Recommendation{
+			Kind:           output.RecommendVerifyHash,
+			Module:         node.Name,
+			CurrentVersion: node.Version,
+			Command:        fmt.Sprintf("go mod download %s@%s", node.Name, node.Version),
+			Rationale:      fmt.Sprintf("go.sum holds a different hash than %s records; remove its go.sum lines and download it again to restore the published hash", depGraph.ProvenanceDatabase),
+		})
+	}
+
+	if effectiveGo := depGraph.EffectiveGoVersion(); effectiveGo != depGraph.ModuleGoVersion {
+		recommendations = append(recommendations, output.Recommendation{
+			Kind:             output.RecommendGoDirective,
+			CurrentVersion:   depGraph.ModuleGoVersion,
+			SuggestedVersion: effectiveGo,
+			Command:          fmt.Sprintf("go get go@%s", effectiveGo),
+			Rationale:        "dependencies require a newer Go than the go directive",
+		})
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Module < recommendations[j].Module
+	})
+	return recommendations
+}
+
+// splitReplacements separates replace directives that point at local
+// directories from module-to-module replacements.
+func splitReplacements(replacements []graph.Replacement) (local, modules []graph.Replacement) {
+	for _, replacement := range replacements {
+		if replacement.IsLocal() {
+			local = append(local, replacement)
+		} else {
+			modules = append(modules, replacement)
+		}
+	}
+	return local, modules
+}
+
+func formatModuleVersion(mv module.Version) string {
+	if mv.Version == "" {
+		return mv.Path
+	}
+	return mv.Path + "@" + mv.Version
+}
+
+// releaseCheck records whether a direct dependency pinned to v0.0.0 or a
+// pseudo-version has tagged releases upstream. Latest is the highest
+// release of the module's major version, or "" when there is none.
+type releaseCheck struct {
+	Node   *graph.EnhancedNode
+	Latest string
+	Err    error
+}
+
+// checkUnreleasedDirect lists the tagged releases of every direct
+// dependency at v0.0.0 or a pseudo-version, sorted by module path. Replaced
+// modules are skipped, since their version only names the replacement.
+func checkUnreleasedDirect(depGraph *graph.EnhancedDependencyGraph, client *proxy.Client) []releaseCheck {
+	replaced := replacedModules(depGraph)
+
+	var nodes []*graph.EnhancedNode
+	for name, node := range depGraph.EnhancedNodes {
+		if name == depGraph.Root.Name || !node.Direct || replaced[name] {
+			continue
+		}
+		if node.Version == "v0.0.0" || node.IsPseudoVersion {
+			nodes = append(nodes, node)
+		}
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	graph.SortNodes(nodes, "name")
+
+	fmt.Fprintf(os.Stderr, "🏷️  Checking releases of %d unreleased direct dependencies...\n", len(nodes))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	checks := make([]releaseCheck, 0, len(nodes))
+	for _, node := range nodes {
+		check := releaseCheck{Node: node}
+		versions, err := client.List(ctx, node.Name)
+		if err != nil {
+			check.Err = err
+		} else {
+			check.Latest = latestMajorRelease(versions, semver.Major(node.Version))
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkVersionAvailability asks the module proxy whether every pinned
+// version still exists. Private and replaced modules are skipped. It returns
+// the error for each module that could not be checked.
+func checkVersionAvailability(depGraph *graph.EnhancedDependencyGraph) map[string]error {
+	fmt.Fprintf(os.Stderr, "🚫 Checking that %d pinned versions still exist...\n", len(depGraph.EnhancedNodes)-1)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	return depGraph.CheckVersionsAvailable(ctx, versionSource(), replacedModules(depGraph))
+}
+
+// loadGoVersions reads the go directive of every dependency from the module
+// cache or proxy and returns the modules whose go.mod could not be read.
+func loadGoVersions(depGraph *graph.EnhancedDependencyGraph) []string {
+	fmt.Fprintf(os.Stderr, "🐹 Reading the go directives of %d dependencies...\n", len(depGraph.EnhancedNodes)-1)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	return depGraph.LoadGoVersions(ctx, proxyClient())
+}
+
+// loadReleaseDates looks up when the latest version of every dependency was
+// published, replacing the estimated update dates, and records a warning for
+// each module that could not be looked up.
+func loadReleaseDates(depGraph *graph.EnhancedDependencyGraph) {
+	fmt.Fprintf(os.Stderr, "📅 Looking up the latest releases of %d dependencies...\n", len(depGraph.EnhancedNodes)-1)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	failed := depGraph.LoadReleaseDates(ctx, proxyClient())
+	var names []string
+	for name, err := range failed {
+		if errors.Is(err, proxy.ErrPrivate) {
+			continue
+		}
+		names = append(names, name)
+		if err != nil {
+			depGraph.AddWarning(graph.WarningReleaseDateUnknown, name, "could not look up the latest release: %v", err)
+		} else {
+			depGraph.AddWarning(graph.WarningReleaseDateUnknown, name, "the module proxy did not report a release date")
+		}
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		fmt.Fprintf(os.Stderr, "⚠️  Release dates unknown for %d %s, not checked for staleness: %s\n",
+			len(names), plural(len(names), "module", "modules"), summarizePackages(names))
+	}
+}
+
+func loadProvenance(depGraph *graph.EnhancedDependencyGraph) {
+	// Like the go command, GONOSUMDB defaults to GOPRIVATE.
+	env := goEnv("GOSUMDB", "GONOSUMDB", "GOPRIVATE")
+	nosumdb := env["GONOSUMDB"]
+	if nosumdb == "" {
+		nosumdb = env["GOPRIVATE"]
+	}
+	db, err := proxy.NewChecksumDB(proxyClient(), env["GOSUMDB"], nosumdb)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Provenance not checked: %v\n", err)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "🔏 Looking up %d dependencies in %s...\n", len(depGraph.EnhancedNodes)-1, db.Name())
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	failed := depGraph.LoadProvenance(ctx, db)
+	var names []string
+	for name, err := range failed {
+		if errors.Is(err, proxy.ErrPrivate) {
+			continue
+		}
+		names = append(names, name)
+		depGraph.AddWarning(graph.WarningProvenanceUnknown, name, "could not look up in %s: %v", db.Name(), err)
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		fmt.Fprintf(os.Stderr, "⚠️  Provenance unknown for %d %s: %s\n",
+			len(names), plural(len(names), "module", "modules"), summarizePackages(names))
+	}
+}
+
+// printProvenance reports the checksum database coverage loaded by
+// --provenance.
+func printProvenance(depGraph *graph.EnhancedDependencyGraph) {
+	if depGraph.ProvenanceDatabase == "" {
+		return
+	}
+
+	coverage := depGraph.ProvenanceCoverage()
+	color.New(color.FgBlue, color.Bold).Printf("🔏 Provenance:\n")
+	fmt.Printf("  %d of %d %s verifiable provenance in %s\n",
+		coverage.Verified, coverage.Total, plural(coverage.Total, "dependency has", "dependencies have"), depGraph.ProvenanceDatabase)
+	for _, node := range depGraph.ProvenanceNodes(graph.ProvenanceMismatch) {
+		color.New(color.FgRed, color.Bold).Printf("  ✗ %s@%s: go.sum hash differs from the checksum database\n", node.Name, node.Version)
+	}
+	for _, node := range depGraph.ProvenanceNodes(graph.ProvenanceMissing) {
+		fmt.Printf("  • %s@%s is not recorded in the checksum database\n", node.Name, node.Version)
+	}
+	if coverage.Unknown > 0 {
+		fmt.Printf("  %d unknown (private, locally replaced or lookup failed)\n", coverage.Unknown)
+	}
+	fmt.Println()
+}
+
+func replacedModules(depGraph *graph.EnhancedDependencyGraph) map[string]bool {
+	replaced := make(map[string]bool)
+	for _, replacement := range depGraph.Replacements {
+		replaced[replacement.Old.Path] = true
+	}
+	return replaced
+}
+
+// latestMajorRelease returns the highest non-prerelease version of major in
+// versions, which List returns sorted.
+func latestMajorRelease(versions []string, major string) string {
+	for i := len(versions) - 1; i >= 0; i-- {
+		if semver.Major(versions[i]) == major && semver.Prerelease(versions[i]) == "" {
+			return versions[i]
+		}
+	}
+	return ""
+}
+
+// goModLine formats where a dependency is required in go.mod, if known.
+func goModLine(node *graph.EnhancedNode) string {
+	if node.DefinedAtLine == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" at go.mod:%d", node.DefinedAtLine)
+}
+
+func countCGO(nodes []*graph.EnhancedNode) int {
+	count := 0
+	for _, node := range nodes {
+		if node.RequiresCGO {
+			count++
+		}
+	}
+	return count
 }
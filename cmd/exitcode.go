@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Exit codes returned by goviz. CI pipelines can rely on these to tell
+// "goviz found problems" apart from "goviz could not run".
+const (
+	ExitOK       = 0
+	ExitFindings = 1
+	ExitUsage    = 2
+	ExitError    = 3
+)
+
+// FindingsError reports that analysis succeeded but its findings exceed the
+// configured threshold.
+type FindingsError struct {
+	Message string
+}
+
+func (e *FindingsError) Error() string {
+	return e.Message
+}
+
+// UsageError reports invalid flags, arguments or flag combinations.
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+func usageErrorf(format string, args ...any) error {
+	return &UsageError{Err: fmt.Errorf(format, args...)}
+}
+
+// cobraUsagePrefixes match argument and flag validation errors that cobra
+// returns as plain errors.
+var cobraUsagePrefixes = []string{
+	"unknown command",
+	"unknown flag",
+	"unknown shorthand flag",
+	"accepts ",
+	"requires at least",
+	"requires at most",
+	"if any flags in the group",
+	"required flag",
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var findingsErr *FindingsError
+	if errors.As(err, &findingsErr) {
+		return ExitFindings
+	}
+
+	var usageErr *UsageError
+	if errors.As(err, &usageErr) {
+		return ExitUsage
+	}
+
+	for _, prefix := range cobraUsagePrefixes {
+		if strings.HasPrefix(err.Error(), prefix) {
+			return ExitUsage
+		}
+	}
+
+	return ExitError
+}
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"goviz/pkg/graph"
 	"goviz/pkg/output"
@@ -15,15 +19,100 @@ import (
 var (
 	format     string
 	outputFile string
+
+	highlightPath string
+	pathOnly      bool
+	resolveTree   bool
+
+	formats   []string
+	outputDir string
+
+	asciiTree      bool
+	maxTreeDepth   int
+	collapseChains bool
+
+	rankDir   string
+	dotTheme  string
+	nodeShape string
+
+	embedMetadata bool
 )
 
+// artifactNames are the files written to --output-dir for each format.
+// The terminal-only tree and ascii formats cannot be written there.
+var artifactNames = map[string]string{
+	"dot":       "depgraph.dot",
+	"png":       "depgraph.png",
+	"svg":       "depgraph.svg",
+	"json":      "depgraph.json",
+	"jsonl":     "depgraph.jsonl",
+	"yaml":      "depgraph.yaml",
+	"tree-json": "depgraph.tree.json",
+}
+
 var generateCmd = &cobra.Command{
 	Use:   "generate [path]",
 	Short: "Generate dependency graph from go.mod file",
 	Long: `Generate a dependency graph from a go.mod file.
 	
 If no path is provided, the current directory will be used.
-The tool will look for go.mod file in the specified directory.`,
+The tool will look for go.mod file in the specified directory.
+
+With --highlight-path <module>, the dot, png and svg formats highlight every
+requirement path from the main module to that module and dim the rest of the
+graph; --path-only leaves out everything else. The go.mod files of the
+dependencies are loaded from the module proxy to find the paths.
+
+--rankdir sets the layout direction of the dot, png and svg formats: TB
+(top to bottom, the default), LR, BT or RL. Wide graphs are often easier
+to read left to right:
+
+  goviz generate --format svg --rankdir LR
+
+--theme picks their colors: light (the default), dark for dark-mode slides,
+or mono for grayscale printing. Modules with security issues stay red or
+orange in every theme. --node-shape replaces the default box shape.
+
+With --embed-metadata the svg format also carries the analysis data, so the
+picture can drive an interactive web page: the report of the json format
+inside <metadata id="goviz-report">, and data-module, data-version,
+data-direct, data-license, data-security-issues, data-max-severity and
+data-outdated attributes on the <g class="node"> element of every module.
+
+The tree-json format writes the dependency tree as nested nodes with
+children arrays. Without --resolve every dependency is a child of the main
+module, as in the ASCII tree; with --resolve the go.mod files of the
+dependencies are loaded from the module proxy and each module lists the
+modules it requires. A module reached along several paths is expanded only
+once and marked "deduped" elsewhere.
+
+With --output-dir, the graph is built once and every format named in
+--formats (or the single --format) is written to the directory, which is
+created if needed:
+
+  goviz generate --formats dot,svg,json --output-dir ./artifacts
+
+writes depgraph.dot, depgraph.svg and depgraph.json (tree-json is written to
+depgraph.tree.json).
+
+The tree format draws branches with box-drawing characters unless the
+locale names a charset other than UTF-8 or, on Windows, the console is not
+Windows Terminal. --ascii forces plain ASCII connectors such as |-- and
+--ascii=false forces box-drawing characters. Branches deeper than
+--max-depth levels (20 by default, 0 for no limit) are cut off with a
+warning, so an unexpectedly deep graph cannot flood the terminal.
+
+--collapse-chains loads the requirement edges from the module proxy and
+draws the tree along them, putting every run of modules that each require
+a single module on one line, such as A → B → C → D, so the tree only
+branches where a module has several requirements. Modules already drawn
+elsewhere in the tree are marked (*) instead of being expanded again.
+
+--exclude <module> removes a dependency from the graph in every format, for
+example to keep internal tooling out of a published diagram. It is
+repeatable and accepts patterns such as "github.com/org/*". Modules the
+excluded one requires are kept, since other dependencies may need them;
+exclude them as well to hide a whole subtree.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var projectPath string
@@ -34,65 +123,241 @@ The tool will look for go.mod file in the specified directory.`,
 			projectPath = args[0]
 		}
 
+		if maxTreeDepth < 0 {
+			return usageErrorf("--max-depth must not be negative")
+		}
+
+		selected := []string{format}
+		if len(formats) > 0 {
+			if cmd.Flags().Changed("format") {
+				return usageErrorf("--format and --formats cannot be combined")
+			}
+			if outputDir == "" {
+				return usageErrorf("--formats requires --output-dir")
+			}
+			selected = uniqueFormats(formats)
+		}
+		if outputDir != "" {
+			if outputFile != "" {
+				return usageErrorf("--output cannot be combined with --output-dir")
+			}
+			for _, name := range selected {
+				if _, ok := artifactNames[name]; !ok {
+					return usageErrorf("format %s cannot be written to --output-dir (supported: dot, png, svg, json, jsonl, yaml, tree-json)", name)
+				}
+			}
+		}
+
+		if pathOnly && highlightPath == "" {
+			return usageErrorf("--path-only requires --highlight-path")
+		}
+		if highlightPath != "" && !slices.ContainsFunc(selected, isGraphFormat) {
+			return usageErrorf("--highlight-path is only supported with the dot, png and svg formats")
+		}
+		rankDir = strings.ToUpper(rankDir)
+		if !slices.Contains(output.RankDirs, rankDir) {
+			return usageErrorf("unsupported --rankdir: %s (supported: %s)", rankDir, strings.Join(output.RankDirs, ", "))
+		}
+		if _, ok := output.DOTThemes[dotTheme]; !ok {
+			return usageErrorf("unsupported --theme: %s (supported: light, dark, mono)", dotTheme)
+		}
+		if !slices.Contains(output.NodeShapes, nodeShape) {
+			return usageErrorf("unsupported --node-shape: %s (supported: %s)", nodeShape, strings.Join(output.NodeShapes, ", "))
+		}
+		for _, name := range []string{"rankdir", "theme", "node-shape"} {
+			if cmd.Flags().Changed(name) && !slices.ContainsFunc(selected, isGraphFormat) {
+				return usageErrorf("--%s is only supported with the dot, png and svg formats", name)
+			}
+		}
+		if embedMetadata && !slices.Contains(selected, "svg") {
+			return usageErrorf("--embed-metadata is only supported with the svg format")
+		}
+		if collapseChains && !slices.Contains(selected, "tree") && !slices.Contains(selected, "ascii") {
+			return usageErrorf("--collapse-chains is only supported with the tree format")
+		}
+		if resolveTree && !slices.Contains(selected, "tree-json") {
+			return usageErrorf("--resolve is only supported with the tree-json format")
+		}
+
 		absPath, err := filepath.Abs(projectPath)
 		if err != nil {
 			return fmt.Errorf("failed to get absolute path: %w", err)
 		}
 
-		goModPath := filepath.Join(absPath, "go.mod")
-		if _, err := os.Stat(goModPath); os.IsNotExist(err) {
-			return fmt.Errorf("go.mod file not found in %s", absPath)
+		goModPath, err := resolveGoModPath(absPath)
+		if err != nil {
+			return err
 		}
 
-		fmt.Printf("Parsing go.mod from %s...\n", absPath)
+		progress := os.Stdout
+		switch format {
+		case "json", "jsonl", "yaml", "tree-json":
+			if outputDir == "" {
+				progress = os.Stderr
+			}
+		}
+		fmt.Fprintf(progress, "Parsing go.mod from %s...\n", absPath)
 		modFile, err := parser.ParseGoMod(goModPath)
 		if err != nil {
 			return fmt.Errorf("failed to parse go.mod: %w", err)
 		}
 
-		goSumPath := filepath.Join(absPath, "go.sum")
-		enhancedGraph, err := graph.BuildEnhancedDependencyGraph(modFile, goSumPath)
+		goSumPath, err := resolveGoSumPath(absPath)
+		if err != nil {
+			return err
+		}
+
+		enhancedGraph, err := graphCache().Build(modFile, goModPath, goSumPath)
 		if err != nil {
 			return fmt.Errorf("failed to build enhanced dependency graph: %w", err)
 		}
+		warnIfGoSumMissing(enhancedGraph)
+		markPrivateModules(enhancedGraph)
+		if err := excludeModules(enhancedGraph); err != nil {
+			return err
+		}
 
 		enhancedGraph.DetectVersionConflicts()
 		if err := enhancedGraph.AnalyzeLicenses(); err != nil {
 			return fmt.Errorf("failed to analyze licenses: %w", err)
 		}
-		if err := enhancedGraph.CheckSecurity(); err != nil {
+		provider, err := heuristicProvider()
+		if err != nil {
+			return err
+		}
+		if err := enhancedGraph.CheckSecurity(provider); err != nil {
 			return fmt.Errorf("failed to check security: %w", err)
 		}
+		resolveRepoURLs(enhancedGraph)
 
-		switch format {
-		case "dot":
-			if outputFile == "" {
-				outputFile = "depgraph.dot"
+		if highlightPath != "" || resolveTree || collapseChains {
+			fmt.Fprintf(progress, "Loading requirement graph from the module proxy...\n")
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+			if err := enhancedGraph.LoadRequirementGraph(ctx, proxyClient()); err != nil {
+				return fmt.Errorf("failed to load requirement graph: %w", err)
 			}
-			return output.GenerateEnhancedDOT(enhancedGraph, outputFile)
-		case "png":
-			if outputFile == "" {
-				outputFile = "depgraph.png"
+		}
+
+		dotOptions := output.DOTOptions{RankDir: rankDir, Theme: dotTheme, NodeShape: nodeShape}
+		if highlightPath != "" {
+			paths, err := enhancedGraph.PathsTo(highlightPath)
+			if err != nil {
+				return err
 			}
-			return output.GeneratePNG(enhancedGraph, outputFile)
-		case "svg":
-			if outputFile == "" {
-				outputFile = "depgraph.svg"
+			dotOptions.Highlight, dotOptions.PathOnly = paths, pathOnly
+		}
+
+		if outputDir == "" {
+			return writeGraphFormat(cmd, enhancedGraph, format, outputFile, absPath, dotOptions)
+		}
+
+		// Fail before writing anything rather than leave a partial set.
+		if slices.Contains(selected, "png") || slices.Contains(selected, "svg") {
+			if err := output.CheckGraphvizInstalled(); err != nil {
+				return err
 			}
-			return output.GenerateSVG(enhancedGraph, outputFile)
-		case "json":
-			return output.GenerateJSON(enhancedGraph, outputFile, absPath)
-		case "yaml":
-			return output.GenerateYAML(enhancedGraph, outputFile, absPath)
-		case "tree", "ascii":
-			return output.GenerateASCIITree(enhancedGraph.DependencyGraph)
-		default:
-			return fmt.Errorf("unsupported format: %s. Supported formats: dot, png, svg, json, yaml, tree, ascii", format)
 		}
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		for _, name := range selected {
+			if err := writeGraphFormat(cmd, enhancedGraph, name, filepath.Join(outputDir, artifactNames[name]), absPath, dotOptions); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("📁 %d%s written to %s\n", len(selected), plural(len(selected), " artifact", " artifacts"), outputDir)
+		return nil
 	},
 }
 
+// writeGraphFormat writes the graph in one output format. The dot, png and
+// svg formats default to a depgraph file in the working directory; the
+// others print to stdout without an output file.
+func writeGraphFormat(cmd *cobra.Command, enhancedGraph *graph.EnhancedDependencyGraph, format, outputFile, absPath string, dotOptions output.DOTOptions) error {
+	switch format {
+	case "dot":
+		if outputFile == "" {
+			outputFile = "depgraph.dot"
+		}
+		return output.GenerateEnhancedDOT(enhancedGraph, outputFile, dotOptions)
+	case "png":
+		if outputFile == "" {
+			outputFile = "depgraph.png"
+		}
+		return output.GeneratePNG(enhancedGraph, outputFile, dotOptions)
+	case "svg":
+		if outputFile == "" {
+			outputFile = "depgraph.svg"
+		}
+		if err := output.GenerateSVG(enhancedGraph, outputFile, dotOptions); err != nil {
+			return err
+		}
+		if embedMetadata {
+			return output.EmbedSVGMetadata(outputFile, enhancedGraph, absPath, reportOptions(cmd))
+		}
+		return nil
+	case "json":
+		return output.GenerateJSON(enhancedGraph, outputFile, absPath, reportOptions(cmd))
+	case "yaml":
+		return output.GenerateYAML(enhancedGraph, outputFile, absPath, reportOptions(cmd))
+	case "jsonl":
+		return output.GenerateJSONL(enhancedGraph, outputFile, absPath, reportOptions(cmd))
+	case "tree-json":
+		return output.GenerateTreeJSON(enhancedGraph, outputFile, absPath, reportOptions(cmd))
+	case "tree", "ascii":
+		style := output.DetectTreeStyle()
+		if cmd.Flags().Changed("ascii") {
+			style = output.UnicodeTree
+			if asciiTree {
+				style = output.ASCIITree
+			}
+		}
+		if collapseChains {
+			return output.GenerateCollapsedTree(enhancedGraph, style, maxTreeDepth)
+		}
+		return output.GenerateASCIITree(enhancedGraph.DependencyGraph, style, maxTreeDepth)
+	default:
+		return usageErrorf("unsupported format: %s. Supported formats: dot, png, svg, json, jsonl, yaml, tree, tree-json, ascii", format)
+	}
+}
+
+// isGraphFormat reports whether a format draws the graph and so supports
+// --highlight-path.
+func isGraphFormat(format string) bool {
+	return format == "dot" || format == "png" || format == "svg"
+}
+
+// uniqueFormats normalizes the --formats list, dropping repeats.
+func uniqueFormats(names []string) []string {
+	var unique []string
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" && !slices.Contains(unique, name) {
+			unique = append(unique, name)
+		}
+	}
+	return unique
+}
+
 func init() {
-	generateCmd.Flags().StringVarP(&format, "format", "f", "tree", "Output format (dot, png, svg, json, yaml, tree, ascii)")
+	generateCmd.Flags().StringVarP(&format, "format", "f", "tree", "Output format (dot, png, svg, json, jsonl, yaml, tree, tree-json, ascii)")
 	generateCmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file")
+	generateCmd.Flags().BoolVar(&asciiTree, "ascii", false, "Draw the tree with plain ASCII connectors (default: detected from the locale and terminal)")
+	generateCmd.Flags().IntVar(&maxTreeDepth, "max-depth", output.DefaultMaxTreeDepth, "Stop drawing the tree below this depth (0 for no limit)")
+	generateCmd.Flags().BoolVar(&collapseChains, "collapse-chains", false, "Draw the tree along requirement edges with single-child chains on one line")
+	generateCmd.Flags().StringSliceVar(&formats, "formats", nil, "Formats to write to --output-dir in one pass (e.g. dot,svg,json)")
+	generateCmd.Flags().StringVar(&outputDir, "output-dir", "", "Directory to write depgraph.<format> artifacts to")
+	generateCmd.Flags().StringVar(&highlightPath, "highlight-path", "", "Highlight the requirement paths to this module (dot, png, svg)")
+	generateCmd.Flags().BoolVar(&pathOnly, "path-only", false, "With --highlight-path, only draw the modules on those paths")
+	generateCmd.Flags().StringVar(&rankDir, "rankdir", "TB", "Graph layout direction for dot, png and svg (TB, LR, BT, RL)")
+	generateCmd.Flags().StringVar(&dotTheme, "theme", "light", "Graph colors for dot, png and svg (light, dark, mono)")
+	generateCmd.Flags().StringVar(&nodeShape, "node-shape", "box", "Graphviz node shape for dot, png and svg (e.g. box, ellipse, note)")
+	generateCmd.Flags().BoolVar(&embedMetadata, "embed-metadata", false, "Embed the analysis data as <metadata> and data-* node attributes in svg output")
+	generateCmd.Flags().BoolVar(&resolveTree, "resolve", false, "Load requirement edges from the module proxy for tree-json")
+	addRulesFlag(generateCmd)
+	addIncludeRootFlag(generateCmd)
+	addRepoURLsFlag(generateCmd)
+	addGoSumFlag(generateCmd)
+	addExcludeFlag(generateCmd)
 }
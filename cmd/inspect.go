@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"goviz/pkg/graph"
+	"goviz/pkg/output"
+	"goviz/pkg/parser"
+	"goviz/pkg/proxy"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/module"
+)
+
+var (
+	inspectFormat    string
+	inspectOutput    string
+	inspectProviders []string
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <module>[@version]",
+	Short: "Vet a published module before adding it as a dependency",
+	Long: `Inspect a published module version without a local project.
+
+The module's go.mod is fetched from the module proxy (GOPROXY) and its
+dependencies are run through the same license and security analysis as
+'goviz analyze'. Without a version, or with @latest, the latest release is
+inspected:
+
+  goviz inspect github.com/spf13/cobra@v1.8.0
+  goviz inspect golang.org/x/mod --provider osv
+
+Published modules have no go.sum in the proxy, so only the requirements
+listed in the module's go.mod are analyzed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		modulePath, version, _ := strings.Cut(args[0], "@")
+		if err := module.CheckPath(modulePath); err != nil {
+			return usageErrorf("invalid module path: %v", err)
+		}
+
+		providers, err := vulnProviders(inspectProviders)
+		if err != nil {
+			return err
+		}
+
+		progress := os.Stdout
+		if inspectFormat != "text" && inspectFormat != "console" {
+			progress = os.Stderr
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		client := proxyClient()
+
+		if version == "" || version == "latest" {
+			version, err = client.LatestVersion(ctx, modulePath)
+			if err != nil {
+				return fmt.Errorf("failed to resolve latest version of %s: %w", modulePath, err)
+			}
+		}
+
+		fmt.Fprintf(progress, "🔎 Fetching go.mod for %s@%s...\n", modulePath, version)
+		enhancedGraph, err := fetchModuleGraph(ctx, client, modulePath, version)
+		if err != nil {
+			return err
+		}
+		markPrivateModules(enhancedGraph)
+
+		enhancedGraph.DetectVersionConflicts()
+		if err := enhancedGraph.AnalyzeLicenses(); err != nil {
+			return fmt.Errorf("failed to analyze licenses: %w", err)
+		}
+		if err := enhancedGraph.CheckSecurity(providers...); err != nil {
+			return fmt.Errorf("failed to check security: %w", err)
+		}
+		if err := checkTargetSecurity(ctx, enhancedGraph, providers); err != nil {
+			return fmt.Errorf("failed to check security: %w", err)
+		}
+
+		spec := modulePath + "@" + version
+		opts := output.ReportOptions{IncludeRoot: true, Invocation: invocation(cmd)}
+		switch inspectFormat {
+		case "json":
+			return output.GenerateJSON(enhancedGraph, inspectOutput, spec, opts)
+		case "yaml":
+			return output.GenerateYAML(enhancedGraph, inspectOutput, spec, opts)
+		case "text", "console":
+			return generateInspectReport(enhancedGraph)
+		default:
+			return usageErrorf("unsupported format: %s. Supported formats: text, json, yaml", inspectFormat)
+		}
+	},
+}
+
+// fetchModuleGraph builds the dependency graph of a published module version
+// from its go.mod in the module proxy.
+func fetchModuleGraph(ctx context.Context, client *proxy.Client, modulePath, version string) (*graph.EnhancedDependencyGraph, error) {
+	data, err := client.GoMod(ctx, modulePath, version)
+	if errors.Is(err, proxy.ErrNotFound) {
+		return nil, fmt.Errorf("%s@%s not found in module proxy", modulePath, version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch go.mod: %w", err)
+	}
+
+	modFile, err := parser.ParseGoModReader(bytes.NewReader(data), modulePath+"@"+version+"/go.mod")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod: %w", err)
+	}
+
+	enhancedGraph, err := graph.BuildEnhancedDependencyGraph(modFile, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to build enhanced dependency graph: %w", err)
+	}
+	enhancedGraph.Root.Version = version
+	return enhancedGraph, nil
+}
+
+// checkTargetSecurity queries the providers for the inspected module itself,
+// which CheckSecurity skips as the main module of the graph. Remote
+// providers are skipped when the module is private.
+func checkTargetSecurity(ctx context.Context, depGraph *graph.EnhancedDependencyGraph, providers []graph.VulnProvider) error {
+	root := depGraph.EnhancedNodes[depGraph.Root.Name]
+	seen := make(map[string]bool)
+
+	for _, provider := range providers {
+		if remote, ok := provider.(graph.RemoteProvider); ok && remote.Remote() && root.Private {
+			root.NotScannedBy = append(root.NotScannedBy, provider.Name())
+			continue
+		}
+		issues, err := provider.Query(ctx, root.Name, root.Version)
+		if err != nil {
+			return fmt.Errorf("%s provider failed for %s@%s: %w", provider.Name(), root.Name, root.Version, err)
+		}
+		for _, issue := range issues {
+			if seen[issue.ID] {
+				continue
+			}
+			seen[issue.ID] = true
+			issue.Module, issue.Version = root.Name, root.Version
+			issue.Provider = provider.Name()
+			issue.Severity, issue.CVSSScore = graph.NormalizeSeverity(issue.Severity, issue.CVSSScore)
+			root.SecurityIssues = append(root.SecurityIssues, issue)
+			depGraph.SecurityIssues = append(depGraph.SecurityIssues, issue)
+		}
+	}
+
+	return nil
+}
+
+func generateInspectReport(depGraph *graph.EnhancedDependencyGraph) error {
+	red := color.New(color.FgRed, color.Bold)
+	green := color.New(color.FgGreen, color.Bold)
+	yellow := color.New(color.FgYellow, color.Bold)
+	blue := color.New(color.FgBlue, color.Bold)
+
+	root := depGraph.EnhancedNodes[depGraph.Root.Name]
+
+	blue.Printf("🔎 Module Inspection Report\n")
+	blue.Printf("==========================\n\n")
+
+	fmt.Printf("Module: %s@%s\n", root.Name, root.Version)
+	if depGraph.ModuleGoVersion != "" {
+		fmt.Printf("Go Version: %s\n", depGraph.ModuleGoVersion)
+	}
+	fmt.Printf("License: %s\n", root.License)
+	fmt.Println()
+
+	if len(root.SecurityIssues) > 0 {
+		red.Printf("🚨 Known Issues in %s (%d):\n", root.Name, len(root.SecurityIssues))
+		for _, issue := range sortedSecurityIssues(root.SecurityIssues) {
+			fmt.Printf("  • %s [%s]: %s\n", issue.ID, issue.Severity, issue.Description)
+			if issue.FixedIn != "" {
+				fmt.Printf("    Fixed in: %s\n", issue.FixedIn)
+			}
+		}
+		fmt.Println()
+	} else {
+		green.Printf("✅ No known security issues in %s\n\n", root.Name)
+	}
+
+	direct, indirect := depGraph.GetDependencyCount()
+	blue.Printf("📦 Direct Dependencies (%d):\n", direct)
+	if direct == 0 {
+		fmt.Printf("  (none)\n")
+	}
+	for _, node := range depGraph.SortedNodes("name") {
+		if !node.Direct {
+			continue
+		}
+		fmt.Printf("  • %s %s [%s]", node.Name, node.Version, node.License)
+		if len(node.SecurityIssues) > 0 {
+			red.Printf(" %d issues", len(node.SecurityIssues))
+		}
+		fmt.Println()
+	}
+	if indirect > 0 {
+		fmt.Printf("  + %d indirect dependencies\n", indirect)
+	}
+	fmt.Println()
+
+	var depIssues []graph.SecurityIssue
+	for _, issue := range depGraph.SecurityIssues {
+		if issue.Module != root.Name {
+			depIssues = append(depIssues, issue)
+		}
+	}
+	if len(depIssues) > 0 {
+		red.Printf("🚨 Security Issues in Dependencies (%d):\n", len(depIssues))
+		for _, issue := range sortedSecurityIssues(depIssues) {
+			fmt.Printf("  • %s@%s: %s [%s]: %s\n", issue.Module, issue.Version, issue.ID, issue.Severity, issue.Description)
+		}
+		fmt.Println()
+	}
+
+	var licenses []string
+	for license := range depGraph.LicensesSummary {
+		licenses = append(licenses, license)
+	}
+	sort.Strings(licenses)
+
+	blue.Printf("📄 License Summary:\n")
+	for _, license := range licenses {
+		fmt.Printf("  • %s: %d packages\n", license, depGraph.LicensesSummary[license])
+	}
+	fmt.Println()
+
+	if len(depGraph.SecurityIssues) > 0 || depGraph.LicensesSummary["Unknown"] > 0 {
+		yellow.Printf("💡 Review the issues above before adding %s as a dependency\n", root.Name)
+	} else {
+		green.Printf("✅ %s@%s looks safe to add\n", root.Name, root.Version)
+	}
+
+	return nil
+}
+
+func init() {
+	inspectCmd.Flags().StringVarP(&inspectFormat, "format", "f", "text", "Output format (text, json, yaml)")
+	inspectCmd.Flags().StringVarP(&inspectOutput, "output", "o", "", "Output file (stdout if not specified)")
+	inspectCmd.Flags().StringSliceVar(&inspectProviders, "provider", []string{"heuristic"}, "Vulnerability providers to query (heuristic, osv)")
+	addRulesFlag(inspectCmd)
+}
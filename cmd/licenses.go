@@ -5,9 +5,12 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"goviz/pkg/graph"
+	"goviz/pkg/output"
 	"goviz/pkg/parser"
+	"goviz/pkg/proxy"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -17,6 +20,12 @@ var (
 	licensesFormat string
 	licensesOutput string
 	checkCompat    bool
+
+	failOnUnknownLicense bool
+	allowUnknown         []string
+
+	noticeFile     string
+	projectLicense string
 )
 
 var licensesCmd = &cobra.Command{
@@ -27,8 +36,42 @@ var licensesCmd = &cobra.Command{
 This command:
 - Identifies licenses for all dependencies
 - Checks for license compatibility issues
+- Summarizes the obligations they carry (attribution, source disclosure,
+  patent grants)
 - Provides compliance reports
-- Flags potentially problematic licenses`,
+- Flags potentially problematic licenses
+
+With --fail-on-unknown-license, the command exits with status 1 when a
+dependency's license cannot be determined. Accepted exceptions can be
+listed with --allow-unknown (repeatable or comma-separated module paths).
+
+The compatibility check looks up every pair of licenses in the graph in a
+compatibility matrix (e.g. GPL-2.0-only with Apache-2.0) and lists the
+incompatible pairs with the modules using them. Pass --project-license with
+the SPDX ID of your own license, or "Proprietary" for closed source, to check
+dependencies against it as well.
+
+The json and yaml formats list every distinct license with its SPDX ID, full
+name, OSI approval and reference URL from an embedded copy of the SPDX
+License List, so the inventory can be used by compliance tools without
+network access.
+
+--format gitlab-codequality and --format azure-devops report unknown
+licenses and license conflicts as GitLab Code Quality issues or Azure
+Pipelines logging commands on the go.mod lines of the modules, as the
+security command does for vulnerabilities.
+
+With --generate-notice, an attribution file listing every dependency with
+its version, license, copyright notices and license text is written for
+distributing binaries. License files are read from the module cache, so run
+'go mod download' first:
+
+  goviz licenses --generate-notice NOTICE.txt
+
+On very large graphs, --limit and --offset page through the dependencies
+of the detailed license breakdown, or of --format table in --sort order:
+
+  goviz licenses --limit 50 --offset 50`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var projectPath string
@@ -44,31 +87,127 @@ This command:
 			return fmt.Errorf("failed to get absolute path: %w", err)
 		}
 
-		goModPath := filepath.Join(absPath, "go.mod")
-		if _, err := os.Stat(goModPath); os.IsNotExist(err) {
-			return fmt.Errorf("go.mod file not found in %s", absPath)
+		goModPath, err := resolveGoModPath(absPath)
+		if err != nil {
+			return err
+		}
+
+		switch licensesFormat {
+		case "text", "table", "json", "yaml", "gitlab-codequality", "azure-devops":
+		default:
+			return usageErrorf("unsupported format: %s. Supported formats: text, table, json, yaml, gitlab-codequality, azure-devops", licensesFormat)
+		}
+		if err := validatePageFlags(); err != nil {
+			return err
 		}
 
-		fmt.Printf("📄 Analyzing dependency licenses...\n")
+		tmpl, err := parseReportTemplate(cmd)
+		if err != nil {
+			return err
+		}
+
+		progress := os.Stdout
+		if tmpl != nil || (licensesFormat != "text" && licensesFormat != "table") {
+			progress = os.Stderr
+		}
+		fmt.Fprintf(progress, "📄 Analyzing dependency licenses...\n")
 		modFile, err := parser.ParseGoMod(goModPath)
 		if err != nil {
 			return fmt.Errorf("failed to parse go.mod: %w", err)
 		}
 
-		goSumPath := filepath.Join(absPath, "go.sum")
-		enhancedGraph, err := graph.BuildEnhancedDependencyGraph(modFile, goSumPath)
+		goSumPath, err := resolveGoSumPath(absPath)
+		if err != nil {
+			return err
+		}
+
+		enhancedGraph, err := graphCache().Build(modFile, goModPath, goSumPath)
 		if err != nil {
 			return fmt.Errorf("failed to build enhanced dependency graph: %w", err)
 		}
+		warnIfGoSumMissing(enhancedGraph)
+		markPrivateModules(enhancedGraph)
+		if err := excludeModules(enhancedGraph); err != nil {
+			return err
+		}
 
 		if err := enhancedGraph.AnalyzeLicenses(); err != nil {
 			return fmt.Errorf("failed to analyze licenses: %w", err)
 		}
+		enhancedGraph.CheckLicenseCompatibility(projectLicense)
+
+		if noticeFile != "" {
+			found := enhancedGraph.LoadLicenseTexts(proxy.ModCacheDir())
+			if err := output.GenerateNotice(enhancedGraph, noticeFile); err != nil {
+				return err
+			}
+			total := len(enhancedGraph.EnhancedNodes) - 1
+			fmt.Fprintf(progress, "📜 NOTICE written to %s (license text for %d of %d dependencies)\n", noticeFile, found, total)
+			if found < total {
+				fmt.Fprintf(os.Stderr, "⚠️  %d license %s missing from the module cache; run 'go mod download' and regenerate\n",
+					total-found, plural(total-found, "text is", "texts are"))
+			}
+		}
+
+		switch {
+		case tmpl != nil:
+			err = output.GenerateTemplate(tmpl, enhancedGraph, licensesOutput, absPath, reportOptions(cmd))
+		case licensesFormat == "table":
+			err = writeDependencyTable(enhancedGraph, licensesOutput)
+		case licensesFormat == "json":
+			err = output.GenerateLicenseJSON(enhancedGraph, licensesOutput, absPath, reportOptions(cmd))
+		case licensesFormat == "yaml":
+			err = output.GenerateLicenseYAML(enhancedGraph, licensesOutput, absPath, reportOptions(cmd))
+		case licensesFormat == "gitlab-codequality":
+			err = output.GenerateGitLabCodeQuality(output.LicenseAnnotations(enhancedGraph, sarifURI(goModPath)), licensesOutput)
+		case licensesFormat == "azure-devops":
+			err = output.GenerateAzureDevOps(output.LicenseAnnotations(enhancedGraph, sarifURI(goModPath)), licensesOutput)
+		default:
+			err = generateLicenseReport(enhancedGraph)
+		}
+		if err != nil {
+			return err
+		}
 
-		return generateLicenseReport(enhancedGraph)
+		licenses := len(enhancedGraph.LicensesSummary)
+		printSummary(
+			fmt.Sprintf("%d%s", licenses, plural(licenses, " license", " licenses")),
+			unknownLicenseSummary(len(unknownLicenseModules(enhancedGraph, nil))),
+		)
+
+		if !failOnUnknownLicense {
+			return nil
+		}
+
+		unknown := unknownLicenseModules(enhancedGraph, allowUnknown)
+		if len(unknown) == 0 {
+			return nil
+		}
+		return &FindingsError{Message: fmt.Sprintf("%d dependencies have unknown licenses: %s", len(unknown), strings.Join(unknown, ", "))}
 	},
 }
 
+// unknownLicenseModules returns the dependencies without a detected license,
+// sorted by module path, except those in allowed.
+func unknownLicenseModules(depGraph *graph.EnhancedDependencyGraph, allowed []string) []string {
+	accepted := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		accepted[name] = true
+	}
+
+	var unknown []string
+	for name, node := range depGraph.EnhancedNodes {
+		if name == depGraph.Root.Name || accepted[name] {
+			continue
+		}
+		if node.License == "" || node.License == "Unknown" {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
 func generateLicenseReport(graph *graph.EnhancedDependencyGraph) error {
 	green := color.New(color.FgGreen, color.Bold)
 	yellow := color.New(color.FgYellow, color.Bold)
@@ -92,7 +231,10 @@ func generateLicenseReport(graph *graph.EnhancedDependencyGraph) error {
 		licenseCounts = append(licenseCounts, licenseCount{license, count})
 	}
 	sort.Slice(licenseCounts, func(i, j int) bool {
-		return licenseCounts[i].count > licenseCounts[j].count
+		if licenseCounts[i].count != licenseCounts[j].count {
+			return licenseCounts[i].count > licenseCounts[j].count
+		}
+		return licenseCounts[i].license < licenseCounts[j].license
 	})
 
 	for _, lc := range licenseCounts {
@@ -127,13 +269,24 @@ func generateLicenseReport(graph *graph.EnhancedDependencyGraph) error {
 			fmt.Printf("     • Risk of license compliance issues\n")
 		}
 
-		if !hasGPL && !hasUnknown {
+		for _, conflict := range graph.LicenseConflicts {
+			red.Printf("  ❌ %s is incompatible with %s\n", conflict.Licenses[0], conflict.Licenses[1])
+			fmt.Printf("     • %s\n", conflict.Reason)
+			for i, license := range conflict.Licenses {
+				fmt.Printf("     • %s: %s\n", license, strings.Join(conflict.Modules[i], ", "))
+			}
+		}
+
+		if !hasGPL && !hasUnknown && len(graph.LicenseConflicts) == 0 {
 			green.Printf("  ✅ No obvious license compatibility issues\n")
 		}
 
 		fmt.Println()
 	}
 
+	printObligations(graph.ObligationsSummary())
+	printOrgLicenses(graph.LicensesByOrg())
+
 	blue.Printf("📋 Detailed License Breakdown:\n")
 
 	licensePackages := make(map[string][]string)
@@ -154,10 +307,20 @@ func generateLicenseReport(graph *graph.EnhancedDependencyGraph) error {
 	}
 	sort.Strings(licenses)
 
+	total := len(graph.EnhancedNodes) - 1
+	start, end := output.Page(total, tableOffset, tableLimit)
+	index := 0
 	for _, license := range licenses {
 		packages := licensePackages[license]
 		sort.Strings(packages)
 
+		// The page runs across the license groups in the order listed.
+		first, last := max(start-index, 0), min(end-index, len(packages))
+		index += len(packages)
+		if first >= last {
+			continue
+		}
+
 		var colorFunc *color.Color
 		switch license {
 		case "Unknown":
@@ -168,11 +331,14 @@ func generateLicenseReport(graph *graph.EnhancedDependencyGraph) error {
 			colorFunc = green
 		}
 
-		colorFunc.Printf("\n%s (%d packages):\n", license, len(packages))
-		for _, pkg := range packages {
+		colorFunc.Printf("\n%s (%d packages)%s:\n", license, len(packages), licenseChoiceNote(license))
+		for _, pkg := range packages[first:last] {
 			fmt.Printf("  • %s\n", pkg)
 		}
 	}
+	if footer := output.PageFooter(start, end, total, tableOffset); footer != "" {
+		fmt.Printf("\n%s\n", footer)
+	}
 
 	fmt.Println()
 	yellow.Printf("💡 Recommendations:\n")
@@ -194,8 +360,85 @@ func generateLicenseReport(graph *graph.EnhancedDependencyGraph) error {
 	return nil
 }
 
+// printObligations turns the license IDs into what distributing the
+// project entails.
+func printObligations(summary graph.ObligationSummary) {
+	blue := color.New(color.FgBlue, color.Bold)
+	yellow := color.New(color.FgYellow, color.Bold)
+	red := color.New(color.FgRed, color.Bold)
+
+	blue.Printf("⚖️  License Obligations:\n")
+	if summary.Attribution > 0 {
+		fmt.Printf("  • %d %s attribution notices in distribution\n", summary.Attribution, plural(summary.Attribution, "package requires", "packages require"))
+	}
+	if summary.SourceDisclosure > 0 {
+		yellow.Printf("  • %d %s source disclosure when distributed\n", summary.SourceDisclosure, plural(summary.SourceDisclosure, "package requires", "packages require"))
+	}
+	if summary.NetworkDisclosure > 0 {
+		red.Printf("  • %d %s source disclosure to network users\n", summary.NetworkDisclosure, plural(summary.NetworkDisclosure, "package requires", "packages require"))
+	}
+	if summary.PatentGrant > 0 {
+		fmt.Printf("  • %d %s an express patent license\n", summary.PatentGrant, plural(summary.PatentGrant, "package grants", "packages grant"))
+	}
+	if len(summary.Unclassified) > 0 {
+		red.Printf("  • %d %s unknown obligations; review manually\n", len(summary.Unclassified), plural(len(summary.Unclassified), "package has", "packages have"))
+	}
+	fmt.Println()
+}
+
+// printOrgLicenses shows the license mix of each organization, flagging
+// those whose modules use more than one license.
+func printOrgLicenses(orgs []graph.OrgLicenses) {
+	blue := color.New(color.FgBlue, color.Bold)
+	yellow := color.New(color.FgYellow, color.Bold)
+
+	blue.Printf("🏢 Licenses by Organization:\n")
+	for _, org := range orgs {
+		var licenses []string
+		for license := range org.Licenses {
+			licenses = append(licenses, license)
+		}
+		sort.Slice(licenses, func(i, j int) bool {
+			if org.Licenses[licenses[i]] != org.Licenses[licenses[j]] {
+				return org.Licenses[licenses[i]] > org.Licenses[licenses[j]]
+			}
+			return licenses[i] < licenses[j]
+		})
+
+		if !org.Mixed {
+			fmt.Printf("  • %s (%d): %s\n", org.Org, org.Modules, licenses[0])
+			continue
+		}
+		parts := make([]string, len(licenses))
+		for i, license := range licenses {
+			parts[i] = fmt.Sprintf("%s %d", license, org.Licenses[license])
+		}
+		yellow.Printf("  ⚠️  %s (%d): mixed - %s\n", org.Org, org.Modules, strings.Join(parts, ", "))
+	}
+	fmt.Println()
+}
+
+// licenseChoiceNote names the alternative counted in the summary for
+// expressions that offer a choice of licenses.
+func licenseChoiceNote(license string) string {
+	options, err := graph.ParseLicenseExpression(license)
+	if err != nil || len(options) < 2 {
+		return ""
+	}
+	return fmt.Sprintf(" - using %s", strings.Join(graph.PreferredLicenses(license), " AND "))
+}
+
 func init() {
-	licensesCmd.Flags().StringVarP(&licensesFormat, "format", "f", "text", "Output format (text, json, yaml)")
+	licensesCmd.Flags().StringVarP(&licensesFormat, "format", "f", "text", "Output format (text, table, json, yaml, gitlab-codequality, azure-devops)")
 	licensesCmd.Flags().StringVarP(&licensesOutput, "output", "o", "", "Output file")
 	licensesCmd.Flags().BoolVar(&checkCompat, "check-compatibility", true, "Check license compatibility")
+	licensesCmd.Flags().BoolVar(&failOnUnknownLicense, "fail-on-unknown-license", false, "Exit with status 1 if any dependency has an unknown license")
+	licensesCmd.Flags().StringSliceVar(&allowUnknown, "allow-unknown", nil, "Module paths accepted with an unknown license by --fail-on-unknown-license")
+	licensesCmd.Flags().StringVar(&projectLicense, "project-license", "", "SPDX ID of the project's own license (or Proprietary) to check dependencies against")
+	licensesCmd.Flags().StringVar(&noticeFile, "generate-notice", "", "Write an attribution NOTICE file with the license texts of all dependencies")
+	addTableFlags(licensesCmd)
+	addGoSumFlag(licensesCmd)
+	addExcludeFlag(licensesCmd)
+	addSummaryFlags(licensesCmd)
+	addTemplateFlag(licensesCmd)
 }
@@ -0,0 +1,549 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"goviz/pkg/golist"
+	"goviz/pkg/graph"
+	"goviz/pkg/output"
+	"goviz/pkg/proxy"
+	"goviz/pkg/remote"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/mod/modfile"
+)
+
+var (
+	goSumOverride string
+	includeRoot   bool
+	reportSort    string
+	reportGroupBy string
+
+	modFileOverride string
+
+	withRepoURLs bool
+
+	tableModuleWidth int
+	tableLimit       int
+	tableOffset      int
+
+	packageUsage bool
+	buildTags    []string
+
+	rulesFile          string
+	popularModulesFile string
+
+	excludePatterns []string
+
+	reportTemplate string
+
+	noGraphCache  bool
+	graphCacheTTL time.Duration
+)
+
+// graphCache returns the on-disk cache for built dependency graphs, or nil
+// when caching is disabled.
+func graphCache() *graph.GraphCache {
+	if noGraphCache || graphCacheTTL <= 0 {
+		return nil
+	}
+	return graph.NewGraphCache(graphCacheTTL)
+}
+
+func addGoSumFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&goSumOverride, "gosum", "", "Path to go.sum (defaults to go.sum next to go.mod)")
+}
+
+func addExcludeFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&excludePatterns, "exclude", nil, "Drop this module (path or pattern such as github.com/org/*) from the graph and every report; repeatable")
+}
+
+// excludeModules applies --exclude to the graph. The modules required by an
+// excluded module stay, since other dependencies may need them as well.
+func excludeModules(depGraph *graph.EnhancedDependencyGraph) error {
+	if len(excludePatterns) == 0 {
+		return nil
+	}
+
+	excluded, unmatched, err := depGraph.ExcludeModules(excludePatterns)
+	if err != nil {
+		return usageErrorf("--exclude: %v", err)
+	}
+	for _, pattern := range unmatched {
+		fmt.Fprintf(os.Stderr, "⚠️  --exclude %s matches no dependency\n", pattern)
+	}
+	if len(excluded) > 0 {
+		fmt.Fprintf(os.Stderr, "🙈 %d %s excluded from the analysis (--exclude): %s\n", len(excluded), plural(len(excluded), "module", "modules"), strings.Join(excluded, ", "))
+	}
+	return nil
+}
+
+func addIncludeRootFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&includeRoot, "include-root", false, "List the main module itself in JSON/YAML reports")
+}
+
+func addSortFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&reportSort, "sort", "risk", "Order dependencies and issues by name, version, severity or risk (severity, then outdated)")
+}
+
+func addRepoURLsFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&withRepoURLs, "repo-urls", false, "Resolve source repository URLs (contacts vanity import path hosts)")
+}
+
+// resolveRepoURLs records the source repository of every module when
+// --repo-urls is set.
+func resolveRepoURLs(depGraph *graph.EnhancedDependencyGraph) {
+	if !withRepoURLs {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	resolved := depGraph.ResolveRepoURLs(ctx, remote.NewClient())
+	fmt.Fprintf(os.Stderr, "🔗 Resolved repository URLs for %d of %d modules\n", resolved, len(depGraph.EnhancedNodes))
+}
+
+// loadDeprecations records the upstream deprecation notice of every module
+// and a warning for each module that could not be checked.
+func loadDeprecations(depGraph *graph.EnhancedDependencyGraph) {
+	fmt.Fprintf(os.Stderr, "🪦 Checking %d dependencies for deprecation notices...\n", len(depGraph.EnhancedNodes)-1)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	failed := depGraph.LoadDeprecations(ctx, proxyClient())
+	var names []string
+	for name, err := range failed {
+		if errors.Is(err, proxy.ErrPrivate) {
+			continue
+		}
+		names = append(names, name)
+		depGraph.AddWarning(graph.WarningDeprecationUnknown, name, "could not check for a deprecation notice: %v", err)
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		fmt.Fprintf(os.Stderr, "⚠️  Could not check %d %s for deprecation: %s\n",
+			len(names), plural(len(names), "module", "modules"), summarizePackages(names))
+	}
+}
+
+// printDeprecatedModules lists deprecated dependencies with the notice
+// their maintainers published.
+func printDeprecatedModules(nodes []*graph.EnhancedNode) {
+	if len(nodes) == 0 {
+		return
+	}
+	color.New(color.FgRed, color.Bold).Printf("🪦 Deprecated Modules (%d):\n", len(nodes))
+	for _, node := range nodes {
+		fmt.Printf("  • %s (%s): %s\n", node.Name, node.Version, node.Deprecated)
+	}
+	fmt.Println()
+}
+
+func addTableFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&tableModuleWidth, "module-width", 50, "Truncate module paths in table output to this many characters (0 to disable)")
+	cmd.Flags().IntVar(&tableLimit, "limit", 0, "Show at most this many dependencies in table and text output (0 for all)")
+	cmd.Flags().IntVar(&tableOffset, "offset", 0, "Skip this many dependencies in table and text output")
+}
+
+// validatePageFlags rejects negative --limit and --offset values.
+// Structured formats ignore the flags and stay complete.
+func validatePageFlags() error {
+	if tableLimit < 0 || tableOffset < 0 {
+		return usageErrorf("--limit and --offset must not be negative")
+	}
+	return nil
+}
+
+// paged reports whether --limit or --offset asks for a page of the
+// dependencies.
+func paged() bool {
+	return tableLimit > 0 || tableOffset > 0
+}
+
+// printDependencyPage lists the page of dependencies selected by --limit
+// and --offset in --sort order, with a footer telling which were shown.
+func printDependencyPage(depGraph *graph.EnhancedDependencyGraph) {
+	nodes := depGraph.SortedNodes(reportSort)
+	start, end := output.Page(len(nodes), tableOffset, tableLimit)
+
+	color.New(color.FgBlue, color.Bold).Printf("📦 Dependencies by %s:\n", reportSort)
+	for _, node := range nodes[start:end] {
+		fmt.Printf("  • %s %s", node.Name, node.Version)
+		if node.Direct {
+			fmt.Printf(" (direct)")
+		}
+		fmt.Println()
+	}
+	if footer := output.PageFooter(start, end, len(nodes), tableOffset); footer != "" {
+		fmt.Printf("  %s\n", footer)
+	}
+	fmt.Println()
+}
+
+// writeDependencyTable prints the dependencies as an aligned table to
+// stdout, or without colors to outputFile.
+func writeDependencyTable(depGraph *graph.EnhancedDependencyGraph, outputFile string) error {
+	opts := output.TableOptions{Sort: reportSort, ModuleWidth: tableModuleWidth, Offset: tableOffset, Limit: tableLimit}
+	if outputFile == "" {
+		return output.WriteTable(os.Stdout, depGraph.SortedNodes(reportSort), opts)
+	}
+
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create table file: %w", err)
+	}
+	opts.NoColor = true
+	if err := output.WriteTable(file, depGraph.SortedNodes(reportSort), opts); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write table file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write table file: %w", err)
+	}
+
+	fmt.Printf("Table report generated: %s\n", outputFile)
+	return nil
+}
+
+func addRulesFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&rulesFile, "rules", "", "YAML file with heuristic security rules added to the defaults")
+	cmd.Flags().StringVar(&popularModulesFile, "popular-modules", "", "YAML file with popular modules added to the defaults checked for typo-squats")
+}
+
+// heuristicProvider returns the heuristic provider with the --rules and
+// --popular-modules files applied, if given.
+func heuristicProvider() (graph.HeuristicProvider, error) {
+	var provider graph.HeuristicProvider
+	if rulesFile != "" {
+		rules, err := graph.LoadRules(rulesFile)
+		if err != nil {
+			return graph.HeuristicProvider{}, err
+		}
+		provider.Rules = rules
+	}
+	if popularModulesFile != "" {
+		popular, err := graph.LoadPopularModules(popularModulesFile)
+		if err != nil {
+			return graph.HeuristicProvider{}, err
+		}
+		provider.PopularModules = popular
+	}
+	return provider, nil
+}
+
+func addTemplateFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&reportTemplate, "template", "", "Render the report data through a Go text/template file instead of --format")
+}
+
+// parseReportTemplate parses the --template file, or returns nil when none
+// was given.
+func parseReportTemplate(cmd *cobra.Command) (*template.Template, error) {
+	if reportTemplate == "" {
+		return nil, nil
+	}
+	if cmd.Flags().Changed("format") {
+		return nil, usageErrorf("--template cannot be combined with --format")
+	}
+	return output.ParseTemplate(reportTemplate)
+}
+
+func addGroupByFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&reportGroupBy, "group-by", "", "Group dependencies by license, org or severity")
+}
+
+func validateGroupByFlag() error {
+	if reportGroupBy == "" {
+		return nil
+	}
+	for _, key := range graph.GroupByKeys {
+		if reportGroupBy == key {
+			return nil
+		}
+	}
+	return usageErrorf("unsupported --group-by: %s (supported: %s)", reportGroupBy, strings.Join(graph.GroupByKeys, ", "))
+}
+
+func validateSortFlag() error {
+	if !graph.ValidSortKey(reportSort) {
+		return usageErrorf("unsupported --sort: %s (supported: %s)", reportSort, strings.Join(graph.SortKeys, ", "))
+	}
+	return nil
+}
+
+// sortedSecurityIssues returns a copy of issues in --sort order.
+func sortedSecurityIssues(issues []graph.SecurityIssue) []graph.SecurityIssue {
+	sorted := append([]graph.SecurityIssue(nil), issues...)
+	graph.SortSecurityIssues(sorted, reportSort)
+	return sorted
+}
+
+// sortedConflicts returns a copy of conflicts ordered by module path.
+func sortedConflicts(conflicts []graph.VersionConflict) []graph.VersionConflict {
+	sorted := append([]graph.VersionConflict(nil), conflicts...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ModulePath < sorted[j].ModulePath
+	})
+	return sorted
+}
+
+func reportOptions(cmd *cobra.Command) output.ReportOptions {
+	return output.ReportOptions{
+		IncludeRoot: includeRoot,
+		Invocation:  invocation(cmd),
+		Sort:        reportSort,
+		GroupBy:     reportGroupBy,
+	}
+}
+
+// sensitiveFlagWords mark flags whose values must never be written to a
+// report.
+var sensitiveFlagWords = []string{"token", "secret", "password", "credential", "auth"}
+
+// invocation records the command path, positional arguments and the
+// resolved value of every flag, defaults included.
+func invocation(cmd *cobra.Command) *output.Invocation {
+	inv := &output.Invocation{
+		Command: cmd.CommandPath(),
+		Args:    cmd.Flags().Args(),
+		Flags:   make(map[string]string),
+	}
+
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Name == "help" {
+			return
+		}
+		for _, word := range sensitiveFlagWords {
+			if strings.Contains(flag.Name, word) {
+				return
+			}
+		}
+		inv.Flags[flag.Name] = flag.Value.String()
+	})
+
+	return inv
+}
+
+// resolveGoSumPath returns the go.sum path for a project. An explicit
+// --gosum override must exist; the default location may be absent.
+func resolveGoSumPath(absPath string) (string, error) {
+	if goSumOverride == "" {
+		if absPath == "" {
+			return "", nil
+		}
+		if modFileOverride != "" {
+			goModPath, err := filepath.Abs(modFileOverride)
+			if err != nil {
+				return "", fmt.Errorf("failed to get absolute path: %w", err)
+			}
+			return goSumPathFor(goModPath), nil
+		}
+		return filepath.Join(absPath, "go.sum"), nil
+	}
+
+	goSumPath, err := filepath.Abs(goSumOverride)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	info, err := os.Stat(goSumPath)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("go.sum file not found: %s", goSumPath)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to access go.sum file: %w", err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("go.sum path is a directory: %s", goSumPath)
+	}
+
+	return goSumPath, nil
+}
+
+// resolveGoModPath returns the go.mod of the project in absPath, or the
+// file given with --modfile.
+func resolveGoModPath(absPath string) (string, error) {
+	if modFileOverride == "" {
+		goModPath := filepath.Join(absPath, "go.mod")
+		if _, err := os.Stat(goModPath); os.IsNotExist(err) {
+			return "", fmt.Errorf("go.mod file not found in %s", absPath)
+		}
+		return goModPath, nil
+	}
+
+	goModPath, err := filepath.Abs(modFileOverride)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	info, err := os.Stat(goModPath)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("go.mod file not found: %s", goModPath)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to access go.mod file: %w", err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("go.mod path is a directory: %s", goModPath)
+	}
+
+	return goModPath, nil
+}
+
+// goCommandModFile returns the --modfile to pass on to the go commands run
+// for --packages and --exclude-test. Like 'go -modfile', only a file ending
+// in .mod is accepted; otherwise the go commands read the project go.mod.
+func goCommandModFile() string {
+	if filepath.Ext(modFileOverride) != ".mod" {
+		return ""
+	}
+	goModPath, err := filepath.Abs(modFileOverride)
+	if err != nil {
+		return modFileOverride
+	}
+	return goModPath
+}
+
+// goSumPathFor derives the go.sum of an alternate go.mod from its name, as
+// the go command does: deps.mod uses deps.sum, and names containing go.mod
+// (go.mod.orig) use go.sum in its place (go.sum.orig).
+func goSumPathFor(goModPath string) string {
+	dir, base := filepath.Split(goModPath)
+	switch {
+	case filepath.Ext(base) == ".mod":
+		return filepath.Join(dir, strings.TrimSuffix(base, ".mod")+".sum")
+	case strings.Contains(base, "go.mod"):
+		return filepath.Join(dir, strings.Replace(base, "go.mod", "go.sum", 1))
+	default:
+		return goModPath + ".sum"
+	}
+}
+
+// goEnv returns the effective values of Go environment variables, including
+// settings from the go env file. Without a go toolchain the process
+// environment is used.
+func goEnv(keys ...string) map[string]string {
+	env, err := golist.Env(".", keys...)
+	if err == nil {
+		return env
+	}
+
+	env = make(map[string]string, len(keys))
+	for _, key := range keys {
+		env[key] = os.Getenv(key)
+	}
+	return env
+}
+
+// privateModulePatterns returns the GOPRIVATE and GONOSUMDB patterns, which
+// mark modules whose paths must not be sent to public services.
+func privateModulePatterns() string {
+	env := goEnv("GOPRIVATE", "GONOSUMDB")
+
+	var patterns []string
+	for _, key := range []string{"GOPRIVATE", "GONOSUMDB"} {
+		if value := strings.Trim(env[key], ", "); value != "" {
+			patterns = append(patterns, value)
+		}
+	}
+	return strings.Join(patterns, ",")
+}
+
+// markPrivateModules flags the dependencies matched by GOPRIVATE or
+// GONOSUMDB so that remote lookups skip them.
+func markPrivateModules(depGraph *graph.EnhancedDependencyGraph) {
+	if private := depGraph.MarkPrivate(privateModulePatterns()); private > 0 {
+		fmt.Fprintf(os.Stderr, "🔒 %d private modules (GOPRIVATE/GONOSUMDB) will not be sent to public services\n", private)
+	}
+}
+
+// versionSource returns the module proxy client used to check that pinned
+// versions still exist, backed by the on-disk cache unless caching is
+// disabled.
+func versionSource() graph.VersionSource {
+	client := proxyClient()
+	if noGraphCache || graphCacheTTL <= 0 {
+		return client
+	}
+	if cache := proxy.NewAvailabilityCache(client, graphCacheTTL); cache != nil {
+		return cache
+	}
+	return client
+}
+
+// proxyClient returns a module proxy client that, like the go command,
+// does not request modules matching GONOPROXY (which defaults to GOPRIVATE)
+// from the proxy.
+func proxyClient() *proxy.Client {
+	client := proxy.NewClient()
+	client.Private = goEnv("GONOPROXY")["GONOPROXY"]
+	return client
+}
+
+func warnIfGoSumMissing(depGraph *graph.EnhancedDependencyGraph) {
+	if !depGraph.GoSumMissing {
+		return
+	}
+	depGraph.AddWarning(graph.WarningGoSumMissing, "", "go.sum not found: hashes and transitive dependency detection will be incomplete")
+	fmt.Fprintf(os.Stderr, "⚠️  go.sum not found: hashes and transitive dependency detection will be incomplete\n")
+}
+
+func addPackagesFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&packageUsage, "packages", false, "Determine which packages of each module are imported (runs go list)")
+	cmd.Flags().StringSliceVar(&buildTags, "tags", nil, "Build tags to consider with --packages (e.g. integration,e2e)")
+}
+
+// loadPackageUsage marks the dependencies whose packages the project
+// imports when --packages is set. Like --exclude-test it needs the project
+// sources and the go toolchain. Modules providing tools declared in go.mod
+// count as imported.
+func loadPackageUsage(depGraph *graph.EnhancedDependencyGraph, modFile *modfile.File, projectDir string) error {
+	if !packageUsage {
+		return nil
+	}
+
+	packages, err := golist.Packages(projectDir, goCommandModFile(), buildTags, len(modFile.Tool) > 0)
+	if err != nil {
+		return fmt.Errorf("failed to determine imported packages: %w", err)
+	}
+	imports, err := golist.Imports(projectDir, goCommandModFile(), buildTags, len(modFile.Tool) > 0)
+	if err != nil {
+		return fmt.Errorf("failed to determine imported packages: %w", err)
+	}
+
+	imported := depGraph.MarkImported(packages)
+	depGraph.MarkDirectImports(imports)
+	fmt.Fprintf(os.Stderr, "📦 %d of %d modules provide imported packages\n", imported, len(depGraph.EnhancedNodes)-1)
+	return nil
+}
+
+// excludeTestDependencies marks and prunes modules that are only needed by
+// test code, using the go toolchain to list the build dependencies of the
+// project. It needs the project sources, so it is unavailable for stdin.
+func excludeTestDependencies(depGraph *graph.EnhancedDependencyGraph, projectDir string) error {
+	if projectDir == "" {
+		return usageErrorf("--exclude-test requires a project directory")
+	}
+
+	testOnly, err := golist.TestOnlyModules(projectDir, goCommandModFile())
+	if err != nil {
+		return fmt.Errorf("failed to determine build dependencies: %w", err)
+	}
+
+	depGraph.MarkTestOnly(testOnly)
+	removed := depGraph.PruneTestOnly()
+	if len(removed) > 0 {
+		fmt.Fprintf(os.Stderr, "Excluded %d test-only dependencies\n", len(removed))
+	}
+	return nil
+}
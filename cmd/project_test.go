@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goviz/pkg/graph"
+	"goviz/pkg/parser"
+)
+
+func TestTextReportPaging(t *testing.T) {
+	modFile, err := parser.ParseGoModReader(strings.NewReader(`module example.com/app
+
+go 1.24
+
+require (
+	example.com/a v1.0.0
+	example.com/b v1.0.0
+	example.com/c v1.0.0
+	example.com/d v1.0.0
+	example.com/e v1.0.0
+)
+`), "go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	depGraph, err := graph.BuildEnhancedDependencyGraph(modFile, filepath.Join(t.TempDir(), "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, license := range map[string]string{"example.com/a": "MIT", "example.com/b": "Apache-2.0", "example.com/c": "MIT", "example.com/d": "Apache-2.0"} {
+		depGraph.EnhancedNodes[name].License = license
+	}
+
+	limit, offset, sortKey := tableLimit, tableOffset, reportSort
+	defer func() {
+		tableLimit, tableOffset, reportSort = limit, offset, sortKey
+	}()
+	tableLimit, tableOffset, reportSort = 2, 1, "name"
+
+	out := ansiEscape.ReplaceAllString(captureStdout(t, func() { printDependencyPage(depGraph) }), "")
+	want := "  • example.com/b v1.0.0 (direct)\n  • example.com/c v1.0.0 (direct)\n  Showing 2-3 of 5 dependencies (next page: --offset 3)\n"
+	if !strings.Contains(out, want) {
+		t.Errorf("dependency page lacks %q:\n%s", want, out)
+	}
+	if strings.Contains(out, "example.com/a") || strings.Contains(out, "example.com/d") {
+		t.Errorf("dependency page lists modules outside the page:\n%s", out)
+	}
+
+	// The license breakdown lists Apache-2.0 (b, d), MIT (a, c) and
+	// Unknown (e), so the page spans the first two groups.
+	out = ansiEscape.ReplaceAllString(captureStdout(t, func() {
+		if err := generateLicenseReport(depGraph); err != nil {
+			t.Error(err)
+		}
+	}), "")
+	for _, want := range []string{
+		"Apache-2.0 (2 packages):\n  • example.com/d\n",
+		"MIT (2 packages):\n  • example.com/a\n",
+		"Showing 2-3 of 5 dependencies (next page: --offset 3)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("license report lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Unknown (") {
+		t.Errorf("license report lists a group outside the page:\n%s", out)
+	}
+
+	tableLimit, tableOffset = 0, 0
+	out = captureStdout(t, func() { printDependencyPage(depGraph) })
+	if strings.Contains(out, "Showing") {
+		t.Errorf("unpaged dependency list has a page footer:\n%s", out)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 
+	"goviz/pkg/graph"
+
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
@@ -13,6 +16,8 @@ var (
 	BuildTime = "unknown"
 )
 
+var noColor bool
+
 var rootCmd = &cobra.Command{
 	Use:     "goviz",
 	Version: Version,
@@ -25,22 +30,67 @@ Features:
 • Multiple output formats (JSON, YAML for CI/CD)
 • License compliance checking
 • Dependency health assessment
-• Security framework integration`,
+• Security framework integration
+• SBOM generation (CycloneDX, SPDX)
+
+Exit codes:
+  0  success
+  1  findings over threshold (e.g. high-severity vulnerabilities)
+  2  usage error (invalid flags, arguments or formats)
+  3  I/O or parse error`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	code := exitCode(err)
+
+	switch code {
+	case ExitOK:
+		return
+	case ExitFindings:
+		fmt.Fprintf(os.Stderr, "goviz: %v\n", err)
+	case ExitUsage:
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Run 'goviz --help' for usage.\n")
+	default:
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
 	}
+
+	os.Exit(code)
 }
 
 func init() {
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &UsageError{Err: err}
+	})
+
+	cobra.OnInitialize(func() {
+		if noColor {
+			color.NoColor = true
+		}
+	})
+
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	rootCmd.PersistentFlags().StringVar(&modFileOverride, "modfile", "", "Read this go.mod file instead of the one in the project directory (like 'go -modfile')")
+	rootCmd.PersistentFlags().BoolVar(&noGraphCache, "no-cache", false, "Rebuild the dependency graph and re-query the module proxy instead of using the on-disk cache")
+	rootCmd.PersistentFlags().DurationVar(&graphCacheTTL, "cache-ttl", graph.DefaultGraphCacheTTL, "How long cached dependency graphs and module proxy lookups are reused")
+
 	rootCmd.AddCommand(generateCmd)
 	rootCmd.AddCommand(analyzeCmd)
 	rootCmd.AddCommand(licensesCmd)
 	rootCmd.AddCommand(doctorCmd)
 	rootCmd.AddCommand(securityCmd)
+	rootCmd.AddCommand(bomCmd)
+	rootCmd.AddCommand(updateCmd)
+	rootCmd.AddCommand(inspectCmd)
+	rootCmd.AddCommand(compareCmd)
+	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(tuiCmd)
+	rootCmd.AddCommand(tidyCheckCmd)
+	rootCmd.AddCommand(schemaCmd)
+	rootCmd.AddCommand(versionCmd)
 }
 
 func SetVersionInfo(version, commit, buildTime string) {
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"strings"
+
+	"goviz/pkg/output"
+
+	"github.com/spf13/cobra"
+)
+
+var schemaOutput string
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [report]",
+	Short: "Print the JSON Schema of a structured report",
+	Long: `Print the JSON Schema (draft 2020-12) of the json reports, for validating
+reports in CI or generating client types:
+
+  goviz schema > goviz-report.schema.json
+  goviz schema security -o security.schema.json
+
+The report defaults to analyze; multi is the report of analyze with several
+modules, tree the tree-json format. The schema is derived from the report
+types, so it matches the goviz it comes from, and its metadata.schema_version
+is pinned to the schema version written in every report.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		report := "analyze"
+		if len(args) > 0 {
+			report = args[0]
+		}
+		if _, ok := output.ReportSchemas[report]; !ok {
+			return usageErrorf("unknown report: %s. Supported reports: %s", report, strings.Join(output.ReportSchemaNames(), ", "))
+		}
+		return output.GenerateSchema(report, schemaOutput)
+	},
+}
+
+func init() {
+	schemaCmd.Flags().StringVarP(&schemaOutput, "output", "o", "", "Output file")
+}
@@ -1,11 +1,19 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"goviz/pkg/graph"
+	"goviz/pkg/osv"
+	"goviz/pkg/output"
 	"goviz/pkg/parser"
 
 	"github.com/fatih/color"
@@ -16,6 +24,16 @@ var (
 	securitySeverity string
 	securityFormat   string
 	securityOutput   string
+
+	securityExcludeTest   bool
+	securityBaseline      string
+	securityWriteBaseline string
+	securityProviders     []string
+	securityFailOn        string
+	securityMinSeverity   string
+	securityHeuristics    string
+	securityCVE           string
+	securityExitZero      bool
 )
 
 var securityCmd = &cobra.Command{
@@ -25,9 +43,66 @@ var securityCmd = &cobra.Command{
 	
 This command:
 - Scans all dependencies for known CVEs
+- Queries one or more advisory providers (heuristic, osv) and merges results
 - Reports vulnerability severity levels
 - Suggests fixes and updates
-- Provides actionable security recommendations`,
+- Provides actionable security recommendations
+
+With --packages, the project sources are inspected with 'go list' and
+issues in modules whose packages are actually imported are listed first.
+
+The heuristic provider matches built-in rules. --rules adds rules from a
+YAML file (set "defaults: false" to drop the built-in ones):
+
+  rules:
+    - id: ORG-0001
+      module: github.com/example/*
+      version: ">=v1.2.0, <v1.4.0"
+      severity: HIGH
+      message: Known data race in the connection pool
+      fixed_in: v1.4.0
+
+A rule may also use module_contains, version_contains or an exact list of
+versions; all conditions given must match.
+
+The heuristic provider also flags possible typo-squats: module paths within
+one or two edits of a popular module, such as github.com/sirupsem/logrus,
+are reported as HIGH TYPOSQUAT findings for manual review. --popular-modules
+adds modules to compare against from a YAML file (again, "defaults: false"
+drops the built-in list):
+
+  modules:
+    - github.com/example/widgets
+
+Heuristic findings, including those of --rules, are informational by
+default: they are reported but do not fail the scan. --heuristics=error
+lets them fail it like advisory findings, and --heuristics=off skips the
+heuristic provider, so that only advisory data gates CI:
+
+  goviz security --provider osv,heuristic --heuristics=warn
+  goviz security --provider osv --heuristics=off
+
+--cve answers whether the project is affected by a single advisory without
+running a full scan. The advisory (GO-, GHSA- or CVE- ID) is fetched from
+OSV and matched against the dependency versions locally, so no module paths
+are sent. The command prints the affected modules, if any, and exits with
+status 0 when the project is not affected and 1 when it is:
+
+  goviz security --cve GHSA-qppj-fm5r-hxr3
+
+Besides sarif for GitHub code scanning, findings can be written in the
+native annotation format of other CI systems, each placed on the require
+line of the module in go.mod with a fingerprint for deduplication:
+--format gitlab-codequality writes a GitLab Code Quality report (publish it
+as the codequality artifact of the job), and --format azure-devops prints
+Azure Pipelines ##vso[task.logissue] logging commands.
+
+In every format the command exits with status 1 when an issue at or above
+--fail-on (HIGH by default) is found, or with --baseline a new one. With
+--exit-zero the findings are only reported and the command exits with
+status 0, e.g. to upload annotations in one step and gate in another:
+
+  goviz security --format sarif -o goviz.sarif --exit-zero`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var projectPath string
@@ -43,32 +118,217 @@ This command:
 			return fmt.Errorf("failed to get absolute path: %w", err)
 		}
 
-		goModPath := filepath.Join(absPath, "go.mod")
-		if _, err := os.Stat(goModPath); os.IsNotExist(err) {
-			return fmt.Errorf("go.mod file not found in %s", absPath)
+		goModPath, err := resolveGoModPath(absPath)
+		if err != nil {
+			return err
+		}
+
+		if err := validateSortFlag(); err != nil {
+			return err
 		}
 
-		fmt.Printf("🔒 Scanning dependencies for security vulnerabilities...\n")
+		switch securityHeuristics {
+		case "warn", "error", "off":
+		default:
+			return usageErrorf("unsupported --heuristics: %s (supported: warn, error, off)", securityHeuristics)
+		}
+		if securityCVE != "" && (securityFormat != "text" || reportTemplate != "" || securityBaseline != "" || securityWriteBaseline != "") {
+			return usageErrorf("--cve cannot be combined with --format, --template, --baseline or --write-baseline")
+		}
+
+		providers, err := vulnProviders(securityProviders)
+		if err != nil {
+			return err
+		}
+		if securityHeuristics == "off" {
+			providers = slices.DeleteFunc(providers, func(provider graph.VulnProvider) bool {
+				return provider.Name() == (graph.HeuristicProvider{}).Name()
+			})
+			if len(providers) == 0 {
+				return usageErrorf("--heuristics=off leaves no vulnerability provider; add --provider osv")
+			}
+		}
+
+		failOn := strings.ToUpper(securityFailOn)
+		if graph.SeverityRank(failOn) == 0 {
+			return usageErrorf("unsupported --fail-on severity: %s (supported: CRITICAL, HIGH, MEDIUM, LOW)", securityFailOn)
+		}
+
+		minSeverity := strings.ToUpper(securityMinSeverity)
+		if minSeverity != "" && graph.SeverityRank(minSeverity) == 0 {
+			return usageErrorf("unsupported --min-severity: %s (supported: CRITICAL, HIGH, MEDIUM, LOW)", securityMinSeverity)
+		}
+
+		tmpl, err := parseReportTemplate(cmd)
+		if err != nil {
+			return err
+		}
+
+		progress := os.Stdout
+		if securityFormat != "text" || tmpl != nil || securityCVE != "" {
+			progress = os.Stderr
+		}
+		fmt.Fprintf(progress, "🔒 Scanning dependencies for security vulnerabilities...\n")
 		modFile, err := parser.ParseGoMod(goModPath)
 		if err != nil {
 			return fmt.Errorf("failed to parse go.mod: %w", err)
 		}
 
-		goSumPath := filepath.Join(absPath, "go.sum")
-		enhancedGraph, err := graph.BuildEnhancedDependencyGraph(modFile, goSumPath)
+		goSumPath, err := resolveGoSumPath(absPath)
+		if err != nil {
+			return err
+		}
+
+		enhancedGraph, err := graphCache().Build(modFile, goModPath, goSumPath)
 		if err != nil {
 			return fmt.Errorf("failed to build enhanced dependency graph: %w", err)
 		}
+		warnIfGoSumMissing(enhancedGraph)
+		markPrivateModules(enhancedGraph)
+		if err := excludeModules(enhancedGraph); err != nil {
+			return err
+		}
 
-		if err := enhancedGraph.CheckSecurity(); err != nil {
+		if securityExcludeTest {
+			if err := excludeTestDependencies(enhancedGraph, absPath); err != nil {
+				return err
+			}
+		}
+
+		if err := loadPackageUsage(enhancedGraph, modFile, absPath); err != nil {
+			return err
+		}
+
+		if securityCVE != "" {
+			return checkAdvisory(enhancedGraph, securityCVE)
+		}
+
+		if err := enhancedGraph.CheckSecurity(providers...); err != nil {
 			return fmt.Errorf("failed to check security: %w", err)
 		}
 
-		return generateSecurityReport(enhancedGraph)
+		findings := output.SecurityFindings(enhancedGraph)
+
+		var known map[string]bool
+		if securityBaseline != "" {
+			known, err = output.LoadSecurityBaseline(securityBaseline)
+			if err != nil {
+				return err
+			}
+		}
+
+		if securityWriteBaseline != "" {
+			if err := output.GenerateSecurityJSON(enhancedGraph, securityWriteBaseline, absPath, reportOptions(cmd)); err != nil {
+				return fmt.Errorf("failed to write baseline: %w", err)
+			}
+			known = make(map[string]bool, len(findings))
+			for _, finding := range findings {
+				known[finding.Key()] = true
+			}
+		}
+
+		blocking := blockingFindings(findings, known, failOn)
+
+		summary := []string{vulnerabilitySummary(enhancedGraph.SecurityIssues)}
+		if len(blocking) > 0 {
+			scope := ""
+			if securityBaseline != "" {
+				scope = " new"
+			}
+			summary = append(summary, fmt.Sprintf("%d%s at or above %s", len(blocking), scope, strings.ToLower(failOn)))
+		}
+		if notScanned := len(enhancedGraph.NotScannedNodes()); notScanned > 0 {
+			summary = append(summary, fmt.Sprintf("%d private%s not scanned", notScanned, plural(notScanned, " module", " modules")))
+		}
+
+		// --min-severity only hides findings; the baseline and --fail-on
+		// above still see all of them.
+		var hidden int
+		if minSeverity != "" {
+			hidden = enhancedGraph.FilterSecurityIssues(minSeverity)
+			findings = output.SecurityFindings(enhancedGraph)
+		}
+
+		format := securityFormat
+		if tmpl != nil {
+			format = "template"
+		}
+
+		switch format {
+		case "template":
+			err = output.GenerateTemplate(tmpl, enhancedGraph, securityOutput, absPath, reportOptions(cmd))
+		case "text":
+			reportBlocking := blocking
+			if securityExitZero {
+				reportBlocking = nil
+			}
+			err = generateSecurityReport(enhancedGraph, findings, reportBlocking, known, failOn)
+			if hidden > 0 {
+				fmt.Printf("\n%d issues below %s hidden (--min-severity)\n", hidden, minSeverity)
+			}
+		case "json":
+			err = output.GenerateSecurityJSON(enhancedGraph, securityOutput, absPath, reportOptions(cmd))
+		case "yaml":
+			err = output.GenerateSecurityYAML(enhancedGraph, securityOutput, absPath, reportOptions(cmd))
+		case "sarif":
+			err = output.GenerateSARIF(enhancedGraph, securityOutput, sarifURI(goModPath))
+		case "gitlab-codequality":
+			err = output.GenerateGitLabCodeQuality(output.SecurityAnnotations(enhancedGraph, sarifURI(goModPath)), securityOutput)
+		case "azure-devops":
+			err = output.GenerateAzureDevOps(output.SecurityAnnotations(enhancedGraph, sarifURI(goModPath)), securityOutput)
+		default:
+			return usageErrorf("unsupported format: %s. Supported formats: text, json, yaml, sarif, gitlab-codequality, azure-devops", securityFormat)
+		}
+		if err != nil {
+			return err
+		}
+
+		printSummary(summary...)
+
+		if len(blocking) > 0 && !securityExitZero {
+			return &FindingsError{Message: fmt.Sprintf("%d security issues at or above %s found", len(blocking), failOn)}
+		}
+
+		return nil
 	},
 }
 
-func generateSecurityReport(depGraph *graph.EnhancedDependencyGraph) error {
+// blockingFindings returns the findings at or above the failOn severity
+// that are not part of the baseline. A nil baseline accepts nothing.
+// Heuristic findings only block with --heuristics=error.
+func blockingFindings(findings []output.SecurityFinding, known map[string]bool, failOn string) []output.SecurityFinding {
+	var blocking []output.SecurityFinding
+	for _, finding := range findings {
+		if graph.SeverityRank(finding.Severity) < graph.SeverityRank(failOn) {
+			continue
+		}
+		if finding.Provider == (graph.HeuristicProvider{}).Name() && securityHeuristics != "error" {
+			continue
+		}
+		if known[finding.Key()] {
+			continue
+		}
+		blocking = append(blocking, finding)
+	}
+	return blocking
+}
+
+// sarifURI returns the go.mod path relative to the working directory, which
+// is the repository root in CI, using forward slashes as SARIF and the
+// other annotation formats expect.
+func sarifURI(goModPath string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "go.mod"
+	}
+	rel, err := filepath.Rel(cwd, goModPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "go.mod"
+	}
+	return filepath.ToSlash(rel)
+}
+
+func generateSecurityReport(depGraph *graph.EnhancedDependencyGraph, findings, blocking []output.SecurityFinding, known map[string]bool, failOn string) error {
 	red := color.New(color.FgRed, color.Bold)
 	green := color.New(color.FgGreen, color.Bold)
 	yellow := color.New(color.FgYellow, color.Bold)
@@ -80,6 +340,14 @@ func generateSecurityReport(depGraph *graph.EnhancedDependencyGraph) error {
 	fmt.Printf("Module: %s\n", depGraph.ModuleName)
 	fmt.Printf("Scanned: %d dependencies\n\n", len(depGraph.AllNodes)-1)
 
+	if notScanned := depGraph.NotScannedNodes(); len(notScanned) > 0 {
+		yellow.Printf("🔒 %d private modules not scanned (GOPRIVATE/GONOSUMDB):\n", len(notScanned))
+		for _, node := range notScanned {
+			fmt.Printf("  • %s@%s: private, not scanned by %s\n", node.Name, node.Version, strings.Join(node.NotScannedBy, ", "))
+		}
+		fmt.Println()
+	}
+
 	if len(depGraph.SecurityIssues) == 0 {
 		green.Printf("✅ No known security vulnerabilities found!\n\n")
 
@@ -95,13 +363,14 @@ func generateSecurityReport(depGraph *graph.EnhancedDependencyGraph) error {
 	severityCount := make(map[string]int)
 	severityIssues := make(map[string][]any)
 
-	for _, issue := range depGraph.SecurityIssues {
+	for _, issue := range sortedSecurityIssues(depGraph.SecurityIssues) {
 		severityCount[issue.Severity]++
 		severityIssues[issue.Severity] = append(severityIssues[issue.Severity], any(issue))
 	}
 
 	red.Printf("🚨 Found %d security issues:\n", len(depGraph.SecurityIssues))
-	for severity, count := range severityCount {
+	for _, severity := range severityCountOrder(severityCount) {
+		count := severityCount[severity]
 		var colorFunc func(a ...any) string
 		switch severity {
 		case "CRITICAL":
@@ -115,16 +384,32 @@ func generateSecurityReport(depGraph *graph.EnhancedDependencyGraph) error {
 		default:
 			colorFunc = fmt.Sprint
 		}
-		fmt.Printf("  • %s: %d\n", colorFunc("%s", severity), count)
+		fmt.Printf("  • %s: %d\n", colorFunc(severity), count)
 	}
 	fmt.Println()
 
-	severityOrder := []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"}
-	for _, severity := range severityOrder {
+	if depGraph.PackagesLoaded {
+		imported := 0
+		for _, issue := range depGraph.SecurityIssues {
+			if node, exists := depGraph.EnhancedNodes[issue.Module]; exists && node.Imported {
+				imported++
+			}
+		}
+		blue.Printf("🎯 %d of %d issues affect modules whose packages the project imports\n\n", imported, len(depGraph.SecurityIssues))
+	}
+
+	for _, severity := range graph.SeverityLevels {
 		issues := severityIssues[severity]
 		if len(issues) == 0 {
 			continue
 		}
+		if depGraph.PackagesLoaded {
+			// Imported modules first; the sort is stable, so --sort still
+			// orders issues within each half.
+			sort.SliceStable(issues, func(i, j int) bool {
+				return issueImported(depGraph, issues[i].(graph.SecurityIssue)) && !issueImported(depGraph, issues[j].(graph.SecurityIssue))
+			})
+		}
 
 		var colorFunc *color.Color
 		switch severity {
@@ -139,7 +424,21 @@ func generateSecurityReport(depGraph *graph.EnhancedDependencyGraph) error {
 		for i, issueInterface := range issues {
 			issue := issueInterface.(graph.SecurityIssue)
 			fmt.Printf("  %d. %s\n", i+1, issue.ID)
+			fmt.Printf("     Module: %s@%s\n", issue.Module, issue.Version)
+			if depGraph.PackagesLoaded {
+				if node, exists := depGraph.EnhancedNodes[issue.Module]; exists && node.Imported {
+					fmt.Printf("     Imported: %s\n", summarizePackages(node.ImportedPackages))
+				} else {
+					fmt.Printf("     Imported: no (module is required but none of its packages are used)\n")
+				}
+			}
 			fmt.Printf("     Description: %s\n", issue.Description)
+			if issue.Heuristic() && securityHeuristics != "error" {
+				fmt.Printf("     Source: heuristic (informational, does not fail the scan)\n")
+			}
+			if issue.CVSSScore > 0 {
+				fmt.Printf("     CVSS: %.1f\n", issue.CVSSScore)
+			}
 			if issue.FixedIn != "" {
 				fmt.Printf("     Fixed in: %s\n", issue.FixedIn)
 			} else {
@@ -164,17 +463,143 @@ func generateSecurityReport(depGraph *graph.EnhancedDependencyGraph) error {
 	fmt.Printf("  • Review and test updates in development environment\n")
 	fmt.Printf("  • Set up automated security scanning in CI/CD\n")
 
-	if severityCount["CRITICAL"] > 0 || severityCount["HIGH"] > 0 {
+	if known != nil {
+		newCount := 0
+		for _, finding := range findings {
+			if !known[finding.Key()] {
+				newCount++
+			}
+		}
 		fmt.Printf("\n")
-		red.Printf("❌ Security scan failed due to high-severity vulnerabilities\n")
-		os.Exit(1)
+		blue.Printf("📋 Baseline: %d known, %d new issues\n", len(findings)-newCount, newCount)
+		for _, finding := range findings {
+			if !known[finding.Key()] {
+				fmt.Printf("  + %s %s [%s]\n", finding.Module, finding.ID, finding.Severity)
+			}
+		}
+	}
+
+	if len(blocking) > 0 {
+		fmt.Printf("\n")
+		if known != nil {
+			red.Printf("❌ Security scan failed due to new vulnerabilities at or above %s\n", failOn)
+		} else {
+			red.Printf("❌ Security scan failed due to vulnerabilities at or above %s\n", failOn)
+		}
 	}
 
 	return nil
 }
 
+// summarizePackages lists the first few import paths of packages.
+func summarizePackages(packages []string) string {
+	const shown = 3
+	if len(packages) <= shown {
+		return strings.Join(packages, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(packages[:shown], ", "), len(packages)-shown)
+}
+
+func issueImported(depGraph *graph.EnhancedDependencyGraph, issue graph.SecurityIssue) bool {
+	node, exists := depGraph.EnhancedNodes[issue.Module]
+	return exists && node.Imported
+}
+
+// severityCountOrder lists the canonical severities present in counts from
+// most to least severe, followed by any other labels alphabetically.
+func severityCountOrder(counts map[string]int) []string {
+	var order, others []string
+	for _, severity := range graph.SeverityLevels {
+		if counts[severity] > 0 {
+			order = append(order, severity)
+		}
+	}
+	for severity := range counts {
+		if graph.SeverityRank(severity) == 0 {
+			others = append(others, severity)
+		}
+	}
+	sort.Strings(others)
+	return append(order, others...)
+}
+
+// checkAdvisory reports whether any dependency is affected by the OSV
+// advisory id, printing one line per affected module. Being affected is
+// returned as a FindingsError.
+func checkAdvisory(depGraph *graph.EnhancedDependencyGraph, id string) error {
+	versions := make(map[string]string, len(depGraph.EnhancedNodes))
+	for name, node := range depGraph.EnhancedNodes {
+		if name != depGraph.Root.Name {
+			versions[name] = node.Version
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	issues, err := osv.NewClient().AffectedBy(ctx, id, versions)
+	if errors.Is(err, osv.ErrAdvisoryNotFound) {
+		return usageErrorf("%v", err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up %s: %w", id, err)
+	}
+
+	if len(issues) == 0 {
+		fmt.Printf("not affected by %s\n", id)
+		return nil
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Module < issues[j].Module
+	})
+	for _, issue := range issues {
+		fmt.Printf("%s@%s", issue.Module, issue.Version)
+		if issue.FixedIn != "" {
+			fmt.Printf(" (fixed in %s)", issue.FixedIn)
+		}
+		fmt.Println()
+	}
+	return &FindingsError{Message: fmt.Sprintf("%d %s affected by %s", len(issues), plural(len(issues), "module", "modules"), id)}
+}
+
+func vulnProviders(names []string) ([]graph.VulnProvider, error) {
+	var providers []graph.VulnProvider
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "heuristic":
+			provider, err := heuristicProvider()
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, provider)
+		case "osv":
+			providers = append(providers, osv.NewClient())
+		default:
+			return nil, usageErrorf("unsupported vulnerability provider: %s (supported: heuristic, osv)", name)
+		}
+	}
+	return providers, nil
+}
+
 func init() {
 	securityCmd.Flags().StringVarP(&securitySeverity, "severity", "s", "", "Filter by severity (CRITICAL, HIGH, MEDIUM, LOW)")
-	securityCmd.Flags().StringVarP(&securityFormat, "format", "f", "text", "Output format (text, json, yaml)")
+	securityCmd.Flags().StringVarP(&securityFormat, "format", "f", "text", "Output format (text, json, yaml, sarif, gitlab-codequality, azure-devops)")
 	securityCmd.Flags().StringVarP(&securityOutput, "output", "o", "", "Output file")
+	securityCmd.Flags().BoolVar(&securityExcludeTest, "exclude-test", false, "Only scan dependencies shipped in non-test builds")
+	securityCmd.Flags().StringVar(&securityBaseline, "baseline", "", "JSON security report of accepted issues; fail only on new ones")
+	securityCmd.Flags().StringVar(&securityWriteBaseline, "write-baseline", "", "Write the current findings to a baseline file")
+	securityCmd.Flags().StringVar(&securityMinSeverity, "min-severity", "", "Only report issues at or above this severity (CRITICAL, HIGH, MEDIUM, LOW)")
+	securityCmd.Flags().StringVar(&securityHeuristics, "heuristics", "warn", "How heuristic findings count toward --fail-on (warn, error, off)")
+	securityCmd.Flags().StringVar(&securityCVE, "cve", "", "Only check whether the project is affected by this advisory ID (via OSV)")
+	securityCmd.Flags().StringVar(&securityFailOn, "fail-on", "HIGH", "Fail when an issue at or above this severity is found (CRITICAL, HIGH, MEDIUM, LOW)")
+	securityCmd.Flags().BoolVar(&securityExitZero, "exit-zero", false, "Report findings without failing: exit with status 0 even when --fail-on is reached")
+	addPackagesFlag(securityCmd)
+	addRulesFlag(securityCmd)
+	securityCmd.Flags().StringSliceVar(&securityProviders, "provider", []string{"heuristic"}, "Vulnerability providers to query (heuristic, osv)")
+	addSortFlag(securityCmd)
+	addGoSumFlag(securityCmd)
+	addExcludeFlag(securityCmd)
+	addSummaryFlags(securityCmd)
+	addTemplateFlag(securityCmd)
 }
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"goviz/pkg/graph"
+	"goviz/pkg/parser"
+
+	"github.com/fatih/color"
+)
+
+// captureStdout returns what fn writes to standard output, including the
+// colored output of fatih/color.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, colorOutput := os.Stdout, color.Output
+	os.Stdout, color.Output = w, w
+	defer func() {
+		os.Stdout, color.Output = stdout, colorOutput
+	}()
+
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		done <- buf.String()
+	}()
+
+	fn()
+	w.Close()
+	return <-done
+}
+
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestGenerateSecurityReportSeverityLabels(t *testing.T) {
+	modFile, err := parser.ParseGoModReader(strings.NewReader(`module example.com/app
+
+go 1.24
+
+require (
+	example.com/a v1.0.0
+	example.com/b v1.0.0
+	example.com/c v1.0.0
+)
+`), "go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, noColor := range []bool{true, false} {
+		depGraph, err := graph.BuildEnhancedDependencyGraph(modFile, filepath.Join(t.TempDir(), "go.sum"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		depGraph.SecurityIssues = []graph.SecurityIssue{
+			{Module: "example.com/a", Version: "v1.0.0", ID: "GO-2024-0001", Severity: "HIGH", Description: "a"},
+			{Module: "example.com/b", Version: "v1.0.0", ID: "GO-2024-0002", Severity: "HIGH", Description: "b"},
+			{Module: "example.com/c", Version: "v1.0.0", ID: "GO-2024-0003", Severity: "LOW", Description: "c"},
+		}
+
+		saved := color.NoColor
+		color.NoColor = noColor
+		out := captureStdout(t, func() {
+			if err := generateSecurityReport(depGraph, nil, nil, nil, ""); err != nil {
+				t.Error(err)
+			}
+		})
+		color.NoColor = saved
+
+		if strings.Contains(out, "%s") || strings.Contains(out, "%!") {
+			t.Errorf("noColor=%v: report contains an unformatted verb:\n%s", noColor, out)
+		}
+		plain := ansiEscape.ReplaceAllString(out, "")
+		for _, want := range []string{"  • HIGH: 2\n", "  • LOW: 1\n"} {
+			if !strings.Contains(plain, want) {
+				t.Errorf("noColor=%v: report lacks %q:\n%s", noColor, want, out)
+			}
+		}
+	}
+}
+
+func TestSecurityFailOnAllFormats(t *testing.T) {
+	dir := t.TempDir()
+	goMod := "module example.com/app\n\ngo 1.24\n\nrequire github.com/gorilla/websocket v1.4.2\n"
+	goSum := "github.com/gorilla/websocket v1.4.2 h1:+/TMaTYc4QFitKJxsQ7Yye35DkWvkdLcvGKqM+x0Ufc=\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.sum"), []byte(goSum), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []string{"text", "json", "yaml", "sarif", "gitlab-codequality", "azure-devops"} {
+		for _, exitZero := range []bool{false, true} {
+			args := []string{"security", dir, "--no-cache", "--no-summary", "--heuristics", "error",
+				"--format", format, "--output", filepath.Join(dir, "report"), "--exit-zero=" + strconv.FormatBool(exitZero)}
+
+			var err error
+			captureStdout(t, func() {
+				rootCmd.SetArgs(args)
+				err = rootCmd.Execute()
+			})
+
+			if exitZero {
+				if err != nil {
+					t.Errorf("%s with --exit-zero: got %v, want success", format, err)
+				}
+				continue
+			}
+			if code := exitCode(err); code != ExitFindings {
+				t.Errorf("%s: exit code %d (%v), want %d for a HIGH issue with --fail-on HIGH", format, code, err, ExitFindings)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"goviz/pkg/graph"
+	"goviz/pkg/parser"
+)
+
+var errNotInRef = errors.New("file does not exist at ref")
+
+// loadGraphAtRef builds the dependency graph of the project as of a git
+// ref, reading go.mod and go.sum with 'git show'. A go.sum missing at the
+// ref is treated like a missing go.sum in the working tree.
+func loadGraphAtRef(projectDir, goModPath, goSumPath, ref string) (*graph.EnhancedDependencyGraph, error) {
+	goMod, err := gitShow(projectDir, ref, goModPath)
+	if errors.Is(err, errNotInRef) {
+		return nil, fmt.Errorf("go.mod does not exist at %s", ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	modFile, err := parser.ParseGoModReader(bytes.NewReader(goMod), ref+":go.mod")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse go.mod at %s: %w", ref, err)
+	}
+
+	dir, err := os.MkdirTemp("", "goviz-since-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	oldGoSumPath := filepath.Join(dir, "go.sum")
+	goSum, err := gitShow(projectDir, ref, goSumPath)
+	switch {
+	case errors.Is(err, errNotInRef):
+	case err != nil:
+		return nil, err
+	default:
+		if err := os.WriteFile(oldGoSumPath, goSum, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write go.sum: %w", err)
+		}
+	}
+
+	oldGraph, err := graph.BuildEnhancedDependencyGraph(modFile, oldGoSumPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build dependency graph at %s: %w", ref, err)
+	}
+	return oldGraph, nil
+}
+
+// gitShow returns the content of file, a path in the working tree of the
+// repository containing dir, as of ref.
+func gitShow(dir, ref, file string) ([]byte, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git not found on PATH")
+	}
+
+	rel, err := filepath.Rel(dir, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate %s: %w", file, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", "show", ref+":./"+filepath.ToSlash(rel))
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if strings.Contains(msg, "does not exist in") || strings.Contains(msg, "exists on disk, but not in") {
+			return nil, errNotInRef
+		}
+		return nil, fmt.Errorf("git show %s:%s failed: %w\n%s", ref, filepath.ToSlash(rel), err, msg)
+	}
+	return stdout.Bytes(), nil
+}
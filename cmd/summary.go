@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"goviz/pkg/graph"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	showSummary bool
+	noSummary   bool
+)
+
+func addSummaryFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&showSummary, "summary", true, "Print a one-line result summary to stderr when done")
+	cmd.Flags().BoolVar(&noSummary, "no-summary", false, "Do not print the one-line result summary")
+}
+
+// printSummary writes the parts as a single "goviz: ..." line to stderr, so
+// CI logs can be grepped for the result without parsing the report.
+func printSummary(parts ...string) {
+	if !showSummary || noSummary {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "goviz: %s\n", strings.Join(parts, "; "))
+}
+
+// vulnerabilitySummary counts issues by severity, most severe first, e.g.
+// "3 high, 1 medium vulnerabilities".
+func vulnerabilitySummary(issues []graph.SecurityIssue) string {
+	if len(issues) == 0 {
+		return "no vulnerabilities"
+	}
+
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		counts[issue.Severity]++
+	}
+
+	var parts []string
+	for _, severity := range severityCountOrder(counts) {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[severity], strings.ToLower(severity)))
+	}
+	return strings.Join(parts, ", ") + plural(len(issues), " vulnerability", " vulnerabilities")
+}
+
+// unknownLicenseSummary reports how many dependencies have no detected
+// license.
+func unknownLicenseSummary(unknown int) string {
+	if unknown == 0 {
+		return "no unknown licenses"
+	}
+	return fmt.Sprintf("%d unknown%s", unknown, plural(unknown, " license", " licenses"))
+}
+
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// analysisSummary summarizes the findings of one or more analyzed modules.
+// The risk score is only given for a single module.
+func analysisSummary(graphs ...*graph.EnhancedDependencyGraph) []string {
+	var (
+		issues    []graph.SecurityIssue
+		unknown   int
+		conflicts int
+	)
+	for _, depGraph := range graphs {
+		issues = append(issues, depGraph.SecurityIssues...)
+		unknown += len(unknownLicenseModules(depGraph, nil))
+		conflicts += len(depGraph.Conflicts)
+	}
+
+	var parts []string
+	if len(graphs) > 1 {
+		parts = append(parts, fmt.Sprintf("%d modules", len(graphs)))
+	} else if analyzeSince != "" {
+		changes := len(graphs[0].Changes)
+		parts = append(parts, fmt.Sprintf("%d changed%s since %s", changes, plural(changes, " dependency", " dependencies"), analyzeSince))
+		downgrades := 0
+		for _, change := range graphs[0].Changes {
+			if change.Downgrade {
+				downgrades++
+			}
+		}
+		if downgrades > 0 {
+			parts = append(parts, fmt.Sprintf("%d downgraded", downgrades))
+		}
+	}
+
+	parts = append(parts,
+		vulnerabilitySummary(issues),
+		unknownLicenseSummary(unknown),
+		fmt.Sprintf("%d version%s", conflicts, plural(conflicts, " conflict", " conflicts")),
+	)
+
+	if len(graphs) == 1 {
+		risk := graphs[0].RiskScore()
+		parts = append(parts, fmt.Sprintf("risk %d/100 (%s)", risk.Score, risk.Grade))
+	}
+	return parts
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"goviz/pkg/golist"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var tidyCheckCmd = &cobra.Command{
+	Use:   "tidy-check [path]",
+	Short: "Check that go.mod and go.sum are tidy",
+	Long: `Check whether 'go mod tidy' would change go.mod or go.sum, the CI gate
+for committed module files that are out of date.
+
+The go toolchain (Go 1.23 or later) runs 'go mod tidy -diff', which
+computes the tidy files without writing them, and the lines it would add
+or remove are shown per file. The command exits with status 1 when the
+files are not tidy:
+
+  goviz tidy-check
+  goviz tidy-check ./services/api`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath := "."
+		if len(args) > 0 {
+			projectPath = args[0]
+		}
+
+		absPath, err := filepath.Abs(projectPath)
+		if err != nil {
+			return fmt.Errorf("failed to get absolute path: %w", err)
+		}
+		if _, err := resolveGoModPath(absPath); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "🧹 Running 'go mod tidy -diff'...\n")
+		diff, err := golist.TidyDiff(absPath)
+		if err != nil {
+			return fmt.Errorf("failed to check tidiness: %w", err)
+		}
+
+		if diff == "" {
+			color.New(color.FgGreen, color.Bold).Printf("✅ go.mod and go.sum are tidy\n")
+			return nil
+		}
+
+		files := printTidyDiff(diff)
+		return &FindingsError{Message: fmt.Sprintf("%s not tidy; run 'go mod tidy'", strings.Join(files, " and "))}
+	},
+}
+
+// printTidyDiff prints the added and removed lines of a 'go mod tidy -diff'
+// diff under the file they belong to and returns the files that change.
+func printTidyDiff(diff string) []string {
+	red := color.New(color.FgRed)
+	green := color.New(color.FgGreen)
+	blue := color.New(color.FgBlue, color.Bold)
+
+	var files []string
+	added, removed := 0, 0
+	flush := func() {
+		if len(files) > 0 {
+			fmt.Printf("  %d %s added, %d removed\n\n", added, plural(added, "line", "lines"), removed)
+		}
+		added, removed = 0, 0
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff "):
+			flush()
+			fields := strings.Fields(line)
+			file := filepath.Base(fields[len(fields)-1])
+			files = append(files, file)
+			blue.Printf("📄 %s\n", file)
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "@@"):
+		case strings.HasPrefix(line, "+"):
+			added++
+			green.Printf("  %s\n", line)
+		case strings.HasPrefix(line, "-"):
+			removed++
+			red.Printf("  %s\n", line)
+		}
+	}
+	flush()
+	return files
+}
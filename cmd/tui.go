@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"goviz/pkg/parser"
+	"goviz/pkg/tui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	tuiResolve   bool
+	tuiProviders []string
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui [path]",
+	Short: "Explore the dependency graph interactively",
+	Long: `Browse your Go module dependencies in an interactive terminal UI.
+
+The left pane shows a collapsible dependency tree, the right pane the
+version, license and security issues of the selected module.
+
+Keys:
+  ↑/↓ or j/k     move
+  enter, ←/→     expand or collapse
+  /              filter modules by path (esc clears)
+  q              quit
+
+With --resolve, the go.mod of every dependency is loaded from the module
+cache or proxy so the tree shows which module requires which; otherwise
+all dependencies are listed under the main module.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var projectPath string
+
+		if len(args) == 0 {
+			projectPath = "."
+		} else {
+			projectPath = args[0]
+		}
+
+		absPath, err := filepath.Abs(projectPath)
+		if err != nil {
+			return fmt.Errorf("failed to get absolute path: %w", err)
+		}
+
+		goModPath, err := resolveGoModPath(absPath)
+		if err != nil {
+			return err
+		}
+
+		providers, err := vulnProviders(tuiProviders)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Loading dependencies from %s...\n", absPath)
+		modFile, err := parser.ParseGoMod(goModPath)
+		if err != nil {
+			return fmt.Errorf("failed to parse go.mod: %w", err)
+		}
+
+		goSumPath, err := resolveGoSumPath(absPath)
+		if err != nil {
+			return err
+		}
+
+		enhancedGraph, err := graphCache().Build(modFile, goModPath, goSumPath)
+		if err != nil {
+			return fmt.Errorf("failed to build enhanced dependency graph: %w", err)
+		}
+		warnIfGoSumMissing(enhancedGraph)
+		markPrivateModules(enhancedGraph)
+		if err := excludeModules(enhancedGraph); err != nil {
+			return err
+		}
+
+		if tuiResolve {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+			if err := enhancedGraph.LoadRequirementGraph(ctx, proxyClient()); err != nil {
+				return fmt.Errorf("failed to load requirement graph: %w", err)
+			}
+		}
+
+		enhancedGraph.DetectVersionConflicts()
+		if err := enhancedGraph.AnalyzeLicenses(); err != nil {
+			return fmt.Errorf("failed to analyze licenses: %w", err)
+		}
+		if err := enhancedGraph.CheckSecurity(providers...); err != nil {
+			return fmt.Errorf("failed to check security: %w", err)
+		}
+
+		return tui.Run(enhancedGraph)
+	},
+}
+
+func init() {
+	tuiCmd.Flags().BoolVar(&tuiResolve, "resolve", false, "Load dependency go.mod files to show requirement edges")
+	tuiCmd.Flags().StringSliceVar(&tuiProviders, "provider", []string{"heuristic"}, "Vulnerability providers to query (heuristic, osv)")
+	addRulesFlag(tuiCmd)
+	addGoSumFlag(tuiCmd)
+	addExcludeFlag(tuiCmd)
+}
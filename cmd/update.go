@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"goviz/pkg/graph"
+	"goviz/pkg/parser"
+	"goviz/pkg/proxy"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+var (
+	updateDryRun bool
+	updateWrite  bool
+	updateScript string
+)
+
+type upgradeStep struct {
+	Module       string
+	Current      string
+	Latest       string
+	BlockedBy    string
+	NewerMajor   string
+	LookupFailed error
+}
+
+var updateCmd = &cobra.Command{
+	Use:   "update [path]",
+	Short: "Suggest upgrades for outdated direct dependencies",
+	Long: `Compute an upgrade plan for direct dependencies using the Go module proxy.
+
+For each outdated direct dependency the current version and the latest
+compatible version (same major version, so the import path is unchanged)
+are shown, followed by a shell script of 'go get' commands.
+
+The command is read-only by default (--dry-run). Use --write to update
+go.mod in place; run 'go mod tidy' afterwards to refresh go.sum.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var projectPath string
+
+		if len(args) == 0 {
+			projectPath = "."
+		} else {
+			projectPath = args[0]
+		}
+
+		absPath, err := filepath.Abs(projectPath)
+		if err != nil {
+			return fmt.Errorf("failed to get absolute path: %w", err)
+		}
+
+		goModPath, err := resolveGoModPath(absPath)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("⬆️  Checking for dependency updates...\n")
+		modFile, err := parser.ParseGoMod(goModPath)
+		if err != nil {
+			return fmt.Errorf("failed to parse go.mod: %w", err)
+		}
+
+		goSumPath, err := resolveGoSumPath(absPath)
+		if err != nil {
+			return err
+		}
+
+		enhancedGraph, err := graphCache().Build(modFile, goModPath, goSumPath)
+		if err != nil {
+			return fmt.Errorf("failed to build enhanced dependency graph: %w", err)
+		}
+		warnIfGoSumMissing(enhancedGraph)
+		markPrivateModules(enhancedGraph)
+		if err := excludeModules(enhancedGraph); err != nil {
+			return err
+		}
+
+		enhancedGraph.DetectVersionConflicts()
+
+		steps := planUpgrades(enhancedGraph, modFile, proxyClient())
+		script := upgradeScript(steps)
+
+		generateUpdateReport(steps, script)
+
+		if updateScript != "" {
+			if err := os.WriteFile(updateScript, []byte(script), 0755); err != nil {
+				return fmt.Errorf("failed to write upgrade script: %w", err)
+			}
+			fmt.Printf("\nUpgrade script written: %s\n", updateScript)
+		}
+
+		if updateWrite {
+			return applyUpgrades(modFile, goModPath, steps)
+		}
+
+		return nil
+	},
+}
+
+func planUpgrades(depGraph *graph.EnhancedDependencyGraph, modFile *modfile.File, client *proxy.Client) []upgradeStep {
+	excluded := make(map[string]bool)
+	for _, exclude := range modFile.Exclude {
+		excluded[exclude.Mod.Path+"@"+exclude.Mod.Version] = true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	var steps []upgradeStep
+	for _, dep := range depGraph.GetDirectDependencies() {
+		step := upgradeStep{Module: dep.Name, Current: dep.Version}
+
+		versions, err := client.List(ctx, dep.Name)
+		if err != nil {
+			step.LookupFailed = err
+			steps = append(steps, step)
+			continue
+		}
+
+		var allowed []string
+		for _, v := range versions {
+			if !excluded[dep.Name+"@"+v] {
+				allowed = append(allowed, v)
+			}
+		}
+		step.Latest = proxy.LatestCompatible(allowed, dep.Version)
+
+		if node, exists := depGraph.EnhancedNodes[dep.Name]; exists && step.Latest != "" {
+			node.UpdateAvailable = step.Latest
+			if len(node.Conflicts) > 0 {
+				step.BlockedBy = fmt.Sprintf("version conflict with %s", node.Conflicts[0].ConflictVersion)
+			}
+		}
+
+		step.NewerMajor = newerMajorVersion(ctx, client, dep.Name)
+		steps = append(steps, step)
+	}
+
+	sort.Slice(steps, func(i, j int) bool {
+		return steps[i].Module < steps[j].Module
+	})
+
+	return steps
+}
+
+// newerMajorVersion reports the latest release of the next major version of
+// a module, which requires an import path change and is never applied.
+func newerMajorVersion(ctx context.Context, client *proxy.Client, modulePath string) string {
+	prefix, pathMajor, ok := module.SplitPathVersion(modulePath)
+	if !ok || strings.HasPrefix(pathMajor, ".") {
+		return ""
+	}
+
+	next := 2
+	if pathMajor != "" {
+		var current int
+		if _, err := fmt.Sscanf(pathMajor, "/v%d", &current); err != nil {
+			return ""
+		}
+		next = current + 1
+	}
+
+	nextPath := fmt.Sprintf("%s/v%d", prefix, next)
+	info, err := client.Latest(ctx, nextPath)
+	if err != nil || semver.Prerelease(info.Version) != "" {
+		return ""
+	}
+	return nextPath + "@" + info.Version
+}
+
+func upgradeScript(steps []upgradeStep) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("# Generated by goviz update\n")
+	b.WriteString("set -e\n\n")
+
+	for _, step := range steps {
+		if step.Latest == "" || step.BlockedBy != "" {
+			continue
+		}
+		fmt.Fprintf(&b, "go get %s@%s\n", step.Module, step.Latest)
+	}
+	b.WriteString("go mod tidy\n")
+
+	return b.String()
+}
+
+func generateUpdateReport(steps []upgradeStep, script string) {
+	green := color.New(color.FgGreen, color.Bold)
+	yellow := color.New(color.FgYellow, color.Bold)
+	red := color.New(color.FgRed, color.Bold)
+	blue := color.New(color.FgBlue, color.Bold)
+
+	blue.Printf("⬆️  Upgrade Plan\n")
+	blue.Printf("===============\n\n")
+
+	var upgrades, blocked, failed, majors []upgradeStep
+	for _, step := range steps {
+		switch {
+		case step.LookupFailed != nil:
+			failed = append(failed, step)
+		case step.BlockedBy != "":
+			blocked = append(blocked, step)
+		case step.Latest != "":
+			upgrades = append(upgrades, step)
+		}
+		if step.NewerMajor != "" {
+			majors = append(majors, step)
+		}
+	}
+
+	if len(upgrades) == 0 {
+		green.Printf("✅ All direct dependencies are up to date\n\n")
+	} else {
+		yellow.Printf("📦 Available upgrades (%d):\n", len(upgrades))
+		for _, step := range upgrades {
+			fmt.Printf("  • %s: %s → %s\n", step.Module, step.Current, step.Latest)
+		}
+		fmt.Println()
+	}
+
+	if len(blocked) > 0 {
+		red.Printf("⛔ Blocked upgrades (%d):\n", len(blocked))
+		for _, step := range blocked {
+			fmt.Printf("  • %s: %s → %s (%s)\n", step.Module, step.Current, step.Latest, step.BlockedBy)
+		}
+		fmt.Println()
+	}
+
+	if len(majors) > 0 {
+		blue.Printf("🔀 New major versions (import path change, not applied):\n")
+		for _, step := range majors {
+			fmt.Printf("  • %s → %s\n", step.Module, step.NewerMajor)
+		}
+		fmt.Println()
+	}
+
+	if len(failed) > 0 {
+		yellow.Printf("⚠️  Could not check %d modules:\n", len(failed))
+		for _, step := range failed {
+			switch {
+			case errors.Is(step.LookupFailed, proxy.ErrNotFound):
+				fmt.Printf("  • %s: not found in module proxy\n", step.Module)
+			case errors.Is(step.LookupFailed, proxy.ErrPrivate):
+				fmt.Printf("  • %s: private, not requested from module proxy (GONOPROXY)\n", step.Module)
+			default:
+				fmt.Printf("  • %s: %v\n", step.Module, step.LookupFailed)
+			}
+		}
+		fmt.Println()
+	}
+
+	if len(upgrades) > 0 {
+		blue.Printf("🔧 Upgrade script:\n")
+		fmt.Print(script)
+	}
+}
+
+func applyUpgrades(modFile *modfile.File, goModPath string, steps []upgradeStep) error {
+	applied := 0
+	for _, step := range steps {
+		if step.Latest == "" || step.BlockedBy != "" || step.LookupFailed != nil {
+			continue
+		}
+		if err := modFile.AddRequire(step.Module, step.Latest); err != nil {
+			return fmt.Errorf("failed to update %s: %w", step.Module, err)
+		}
+		applied++
+	}
+
+	if applied == 0 {
+		fmt.Printf("\nNo changes written to %s\n", goModPath)
+		return nil
+	}
+
+	modFile.Cleanup()
+	data, err := modFile.Format()
+	if err != nil {
+		return fmt.Errorf("failed to format go.mod: %w", err)
+	}
+
+	if err := os.WriteFile(goModPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write go.mod: %w", err)
+	}
+
+	fmt.Printf("\n✅ Updated %d requirements in %s\n", applied, goModPath)
+	fmt.Printf("Run 'go mod tidy' to refresh go.sum\n")
+	return nil
+}
+
+func init() {
+	updateCmd.Flags().BoolVar(&updateDryRun, "dry-run", true, "Only show the upgrade plan (default)")
+	updateCmd.Flags().BoolVar(&updateWrite, "write", false, "Write the upgraded requirements to go.mod")
+	updateCmd.Flags().StringVar(&updateScript, "script", "", "Write the upgrade script to a file")
+	updateCmd.MarkFlagsMutuallyExclusive("dry-run", "write")
+	addGoSumFlag(updateCmd)
+	addExcludeFlag(updateCmd)
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"goviz/pkg/parser"
+	"goviz/pkg/proxy"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+var (
+	validateStrict  bool
+	noIndirectInSum bool
+	verifyHashes    bool
+	validateVerbose bool
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [path]",
+	Short: "Check go.mod and go.sum for errors and hygiene problems",
+	Long: `Validate the go.mod and go.sum of a module.
+
+go.mod must parse, and go.sum must be well-formed and hold the go.mod
+checksum of every requirement. Each problem is reported with its line.
+
+With --strict, go.mod is also checked for mistakes the parser tolerates:
+
+- versions it silently canonicalizes, such as v1.2 for v1.2.0
+- duplicate requirements of the same module
+- a missing go directive, or a toolchain older than the go version
+- the main module requiring itself
+- replace and exclude directives for modules that are not required
+- version-specific replaces whose version differs from the required one,
+  and replaces with another version of the same module, which should be
+  a requirement of that version instead
+
+With --no-indirect-in-sum, the requirement graph is loaded from the module
+cache and proxy, and go.sum entries for module versions outside it are
+reported as tidy candidates: stale checksums 'go mod tidy' would remove.
+
+With --verify-hashes, the hash of every go.sum entry is recomputed from the
+module zip or go.mod downloaded into the module cache (GOMODCACHE), and
+mismatches are reported: the cache or go.sum has been tampered with or is
+corrupt. Entries that are not downloaded are skipped; --verbose lists each
+verified entry with its hash algorithm.
+
+The command exits with status 1 when problems are found:
+
+  goviz validate --strict`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var projectPath string
+
+		if len(args) == 0 {
+			projectPath = "."
+		} else {
+			projectPath = args[0]
+		}
+
+		absPath, err := filepath.Abs(projectPath)
+		if err != nil {
+			return fmt.Errorf("failed to get absolute path: %w", err)
+		}
+
+		goModPath, err := resolveGoModPath(absPath)
+		if err != nil {
+			return err
+		}
+
+		goSumPath, err := resolveGoSumPath(absPath)
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(goModPath)
+		if err != nil {
+			return fmt.Errorf("failed to read go.mod file: %w", err)
+		}
+
+		mode := ""
+		if validateStrict {
+			mode = " (strict)"
+		}
+		fmt.Printf("🔎 Validating %s%s...\n", goModPath, mode)
+
+		modFile, issues := parser.ValidateGoMod(goModPath, data, validateStrict)
+		var stale []parser.Issue
+		if modFile != nil {
+			issues = append(issues, parser.ValidateGoSum(goSumPath, modFile)...)
+			if noIndirectInSum {
+				stale, err = staleSumEntries(modFile, goModPath, goSumPath)
+				if err != nil {
+					return err
+				}
+				issues = append(issues, stale...)
+			}
+			if verifyHashes {
+				mismatched, err := verifySumHashes(goSumPath)
+				if err != nil {
+					return err
+				}
+				issues = append(issues, mismatched...)
+			}
+		}
+
+		if len(issues) == 0 {
+			color.New(color.FgGreen, color.Bold).Printf("✅ go.mod and go.sum are valid\n")
+			return nil
+		}
+
+		red := color.New(color.FgRed, color.Bold)
+		for _, issue := range issues {
+			red.Printf("❌ ")
+			fmt.Println(issue)
+		}
+		if len(stale) > 0 {
+			fmt.Printf("\n💡 Run 'go mod tidy' to remove %d stale go.sum %s\n", len(stale), plural(len(stale), "entry", "entries"))
+		}
+		return &FindingsError{Message: fmt.Sprintf("%d %s found", len(issues), plural(len(issues), "problem", "problems"))}
+	},
+}
+
+// staleSumEntries reports the go.sum entries for module versions that are
+// not part of the requirement graph of modFile.
+func staleSumEntries(modFile *modfile.File, goModPath, goSumPath string) ([]parser.Issue, error) {
+	lines, err := parser.ParseGoSumLines(goSumPath)
+	if errors.Is(err, parser.ErrGoSumNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	depGraph, err := graphCache().Build(modFile, goModPath, goSumPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "🧮 Loading the requirement graph...\n")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	if err := depGraph.LoadRequirementGraph(ctx, proxyClient()); err != nil {
+		return nil, fmt.Errorf("failed to load requirement graph: %w", err)
+	}
+	if len(depGraph.UnresolvedModules) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not load go.mod of %d modules, so entries they need may be reported as stale: %s\n",
+			len(depGraph.UnresolvedModules), strings.Join(depGraph.UnresolvedModules, ", "))
+	}
+
+	reachable := depGraph.ReachableVersions()
+	var issues []parser.Issue
+	for _, line := range lines {
+		if reachable[module.Version{Path: line.ModulePath, Version: line.Version}] {
+			continue
+		}
+		entry := line.ModulePath + " " + line.Version
+		if line.GoMod {
+			entry += "/go.mod"
+		}
+		issues = append(issues, parser.Issue{
+			File:    filepath.Base(goSumPath),
+			Line:    line.Line,
+			Message: fmt.Sprintf("%s is not in the module graph (tidy candidate)", entry),
+		})
+	}
+	return issues, nil
+}
+
+// verifySumHashes recomputes the hashes of the go.sum entries downloaded
+// into the module cache and reports those that differ.
+func verifySumHashes(goSumPath string) ([]parser.Issue, error) {
+	lines, err := parser.ParseGoSumLines(goSumPath)
+	if errors.Is(err, parser.ErrGoSumNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	modCacheDir := proxy.ModCacheDir()
+	var issues []parser.Issue
+	verified, skipped := 0, 0
+	for _, line := range lines {
+		entry := line.ModulePath + " " + line.Version
+		if line.GoMod {
+			entry += "/go.mod"
+		}
+
+		if parser.HashAlgorithm(line.Hash) != "h1" {
+			// Reported by ValidateGoSum; there is nothing to compare with.
+			continue
+		}
+
+		got, err := parser.VerifyHash(modCacheDir, line)
+		if errors.Is(err, parser.ErrNotInCache) {
+			skipped++
+			continue
+		}
+		if err != nil {
+			issues = append(issues, parser.Issue{
+				File:    filepath.Base(goSumPath),
+				Line:    line.Line,
+				Message: fmt.Sprintf("could not hash %s from the module cache: %v", entry, err),
+			})
+			continue
+		}
+		if got != line.Hash {
+			issues = append(issues, parser.Issue{
+				File:    filepath.Base(goSumPath),
+				Line:    line.Line,
+				Message: fmt.Sprintf("hash mismatch for %s: go.sum has %s, module cache has %s (tampered or corrupt)", entry, line.Hash, got),
+			})
+			continue
+		}
+		verified++
+		if validateVerbose {
+			fmt.Printf("  ✓ %s [%s]\n", entry, parser.HashAlgorithm(line.Hash))
+		}
+	}
+
+	fmt.Printf("🔐 Verified %d of %d go.sum %s against the module cache", verified, len(lines), plural(len(lines), "hash", "hashes"))
+	if skipped > 0 {
+		fmt.Printf(" (%d not downloaded)", skipped)
+	}
+	fmt.Println()
+	return issues, nil
+}
+
+func init() {
+	validateCmd.Flags().BoolVar(&validateStrict, "strict", false, "Also report go.mod mistakes the parser tolerates")
+	validateCmd.Flags().BoolVar(&noIndirectInSum, "no-indirect-in-sum", false, "Report go.sum entries for module versions outside the requirement graph")
+	validateCmd.Flags().BoolVar(&verifyHashes, "verify-hashes", false, "Recompute go.sum hashes from the module cache and report mismatches")
+	validateCmd.Flags().BoolVarP(&validateVerbose, "verbose", "v", false, "List each verified go.sum entry with its hash algorithm")
+	addGoSumFlag(validateCmd)
+}
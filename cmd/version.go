@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var versionFormat string
+
+type versionInfo struct {
+	Version   string `json:"version" yaml:"version"`
+	Commit    string `json:"commit" yaml:"commit"`
+	BuildTime string `json:"buildTime" yaml:"buildTime"`
+	GoVersion string `json:"goVersion" yaml:"goVersion"`
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print version and build information",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info := versionInfo{
+			Version:   Version,
+			Commit:    Commit,
+			BuildTime: BuildTime,
+			GoVersion: runtime.Version(),
+		}
+
+		switch versionFormat {
+		case "text":
+			fmt.Printf("goviz %s\n", info.Version)
+			fmt.Printf("  commit:     %s\n", info.Commit)
+			fmt.Printf("  built:      %s\n", info.BuildTime)
+			fmt.Printf("  go version: %s\n", info.GoVersion)
+		case "json":
+			data, err := json.MarshalIndent(info, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(data))
+		case "yaml":
+			data, err := yaml.Marshal(info)
+			if err != nil {
+				return fmt.Errorf("failed to marshal YAML: %w", err)
+			}
+			fmt.Print(string(data))
+		default:
+			return usageErrorf("unsupported format: %s. Supported formats: text, json, yaml", versionFormat)
+		}
+		return nil
+	},
+}
+
+func init() {
+	versionCmd.Flags().StringVarP(&versionFormat, "format", "f", "text", "Output format (text, json, yaml)")
+}
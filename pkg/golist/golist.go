@@ -0,0 +1,230 @@
+package golist
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// ErrGoNotFound is returned when the go toolchain is not available on PATH.
+var ErrGoNotFound = errors.New("go toolchain not found on PATH")
+
+// BuildPlatforms are the GOOS values whose dependencies are merged by
+// TestOnlyModules, Packages and Imports, so platform-gated imports are not
+// mistaken for unused or test-only ones.
+var BuildPlatforms = []string{"linux", "darwin", "windows"}
+
+// TestOnlyModules returns the modules that the packages under dir need
+// only for their tests: those listed by 'go list -deps -test' but not by
+// 'go list -deps' on any of BuildPlatforms. Modules neither list mentions,
+// such as go.sum-only entries or modules for other platforms, are not
+// included, since nothing shows that they are test-only.
+//
+// modFile, when not empty, is passed to the go command as -modfile in
+// place of the go.mod in dir; it must end in .mod.
+func TestOnlyModules(dir, modFile string) (map[string]bool, error) {
+	build := make(map[string]bool)
+	withTests := make(map[string]bool)
+	for _, goos := range BuildPlatforms {
+		env := []string{"GOOS=" + goos}
+		mods, err := modules(dir, modFile, false, env)
+		if err != nil {
+			return nil, err
+		}
+		for mod := range mods {
+			build[mod] = true
+		}
+
+		mods, err = modules(dir, modFile, true, env)
+		if err != nil {
+			return nil, err
+		}
+		for mod := range mods {
+			withTests[mod] = true
+		}
+	}
+
+	testOnly := make(map[string]bool)
+	for mod := range withTests {
+		if !build[mod] {
+			testOnly[mod] = true
+		}
+	}
+	return testOnly, nil
+}
+
+// Packages returns the import paths of the packages, grouped by module
+// path, that the packages under dir and their tests depend on, directly or
+// transitively, on any of BuildPlatforms. Files gated by other build
+// constraints are only seen when their tags are given. With tools set, the
+// packages of the go.mod tool directives are included too. Standard
+// library packages and packages of the main module are omitted. modFile
+// is passed on as -modfile, as for TestOnlyModules.
+func Packages(dir, modFile string, tags []string, tools bool) (map[string][]string, error) {
+	args := append(listArgs(modFile), "-deps", "-test", "-f", "{{with .Module}}{{if not .Main}}{{.Path}} {{$.ImportPath}}{{end}}{{end}}")
+	if len(tags) > 0 {
+		args = append(args, "-tags", strings.Join(tags, ","))
+	}
+	args = append(args, "./...")
+	if tools {
+		args = append(args, "tool")
+	}
+
+	seen := make(map[string]map[string]bool)
+	for _, goos := range BuildPlatforms {
+		out, err := run(dir, []string{"GOOS=" + goos}, args...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, line := range strings.Split(out, "\n") {
+			// Test variants are listed as "path [pkg.test]".
+			fields := strings.Fields(line)
+			if len(fields) < 2 {
+				continue
+			}
+			if seen[fields[0]] == nil {
+				seen[fields[0]] = make(map[string]bool)
+			}
+			seen[fields[0]][fields[1]] = true
+		}
+	}
+
+	packages := make(map[string][]string, len(seen))
+	for mod, paths := range seen {
+		for path := range paths {
+			packages[mod] = append(packages[mod], path)
+		}
+		sort.Strings(packages[mod])
+	}
+	return packages, nil
+}
+
+// Imports returns the import paths that the packages under dir and their
+// tests import directly, on any of BuildPlatforms, with the same tags as
+// Packages. With tools set, the packages of the go.mod tool directives are
+// included as well, since the main module uses them directly. modFile is
+// passed on as -modfile, as for TestOnlyModules.
+func Imports(dir, modFile string, tags []string, tools bool) (map[string]bool, error) {
+	args := append(listArgs(modFile), "-f", "{{with .Module}}{{if .Main}}{{range $.Imports}}{{.}} {{end}}{{range $.TestImports}}{{.}} {{end}}{{range $.XTestImports}}{{.}} {{end}}{{else}}{{$.ImportPath}}{{end}}{{end}}")
+	if len(tags) > 0 {
+		args = append(args, "-tags", strings.Join(tags, ","))
+	}
+	args = append(args, "./...")
+	if tools {
+		args = append(args, "tool")
+	}
+
+	imports := make(map[string]bool)
+	for _, goos := range BuildPlatforms {
+		out, err := run(dir, []string{"GOOS=" + goos}, args...)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range strings.Fields(out) {
+			imports[path] = true
+		}
+	}
+	return imports, nil
+}
+
+// Env returns the effective values of Go environment variables as seen
+// from dir, including settings from the go env config file.
+func Env(dir string, keys ...string) (map[string]string, error) {
+	out, err := run(dir, nil, append([]string{"env", "-json"}, keys...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	env := make(map[string]string)
+	if err := json.Unmarshal([]byte(out), &env); err != nil {
+		return nil, fmt.Errorf("failed to decode go env output: %w", err)
+	}
+	return env, nil
+}
+
+// TidyDiff returns the changes 'go mod tidy' would make to go.mod and go.sum
+// in dir as a unified diff, empty when they are tidy. The files are left
+// untouched. It needs Go 1.23 or later for 'go mod tidy -diff'.
+func TidyDiff(dir string) (string, error) {
+	if _, err := exec.LookPath("go"); err != nil {
+		return "", ErrGoNotFound
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", "mod", "tidy", "-diff")
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return "", nil
+	case errors.As(err, &exitErr) && stdout.Len() > 0:
+		// tidy -diff exits with status 1 after printing the diff.
+		return stdout.String(), nil
+	case strings.Contains(stderr.String(), "flag provided but not defined: -diff"):
+		return "", fmt.Errorf("go mod tidy -diff needs Go 1.23 or later")
+	default:
+		return "", fmt.Errorf("go mod tidy -diff failed: %w\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+}
+
+func modules(dir, modFile string, tests bool, env []string) (map[string]bool, error) {
+	args := append(listArgs(modFile), "-deps", "-f", "{{with .Module}}{{.Path}}{{end}}")
+	if tests {
+		args = append(args, "-test")
+	}
+	args = append(args, "./...")
+
+	out, err := run(dir, env, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := make(map[string]bool)
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			modules[line] = true
+		}
+	}
+	return modules, nil
+}
+
+// listArgs returns the start of a 'go list' command line, reading modFile
+// instead of the go.mod of the working directory when it is not empty. The
+// flag is given per command rather than through GOFLAGS so concurrent
+// callers and later go commands of the process are unaffected.
+func listArgs(modFile string) []string {
+	if modFile == "" {
+		return []string{"list"}
+	}
+	return []string{"list", "-modfile=" + modFile}
+}
+
+func run(dir string, env []string, args ...string) (string, error) {
+	if _, err := exec.LookPath("go"); err != nil {
+		return "", ErrGoNotFound
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("go %s failed: %w\n%s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// VersionSource reports whether a module version can still be downloaded.
+type VersionSource interface {
+	VersionAvailable(ctx context.Context, modulePath, version string) (bool, error)
+}
+
+const availabilityWorkers = 8
+
+// CheckVersionsAvailable asks source whether the version of every
+// dependency still exists and marks the missing ones VersionUnavailable.
+// Private modules and modules in skip, e.g. replaced ones, are not checked.
+// It returns the error for every module that could not be checked.
+func (g *EnhancedDependencyGraph) CheckVersionsAvailable(ctx context.Context, source VersionSource, skip map[string]bool) map[string]error {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed = make(map[string]error)
+	)
+	sem := make(chan struct{}, availabilityWorkers)
+
+	for name, node := range g.EnhancedNodes {
+		node.VersionUnavailable = false
+		if name == g.Root.Name || node.Private || skip[name] || node.Version == "" {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(node *EnhancedNode) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			available, err := source.VersionAvailable(ctx, node.Name, node.Version)
+			if err != nil {
+				mu.Lock()
+				failed[node.Name] = err
+				mu.Unlock()
+				return
+			}
+			node.VersionUnavailable = !available
+		}(node)
+	}
+	wg.Wait()
+
+	return failed
+}
+
+// UnavailableNodes returns the dependencies whose version no longer exists
+// upstream, sorted by name.
+func (g *EnhancedDependencyGraph) UnavailableNodes() []*EnhancedNode {
+	var nodes []*EnhancedNode
+	for name, node := range g.EnhancedNodes {
+		if name != g.Root.Name && node.VersionUnavailable {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	return nodes
+}
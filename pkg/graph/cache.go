@@ -0,0 +1,294 @@
+package graph
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"goviz/pkg/parser"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// graphCacheFormat is part of the cache key; bump it whenever the cached
+// structure, or how its content is derived, changes.
+const graphCacheFormat = "goviz-graph-v7"
+
+const DefaultGraphCacheTTL = 24 * time.Hour
+
+// GraphCache stores built dependency graphs on disk, keyed by the content
+// of go.mod and go.sum, so that repeated runs on an unchanged project skip
+// parsing go.sum and rebuilding the graph. Only the structure produced by
+// BuildEnhancedDependencyGraph is cached; licenses, conflicts and security
+// issues are always computed fresh. A nil *GraphCache disables caching.
+type GraphCache struct {
+	Dir string
+	TTL time.Duration
+}
+
+// NewGraphCache returns a cache in the user cache directory, or nil if that
+// directory cannot be determined.
+func NewGraphCache(ttl time.Duration) *GraphCache {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil
+	}
+	return &GraphCache{Dir: filepath.Join(dir, "goviz", "graphs"), TTL: ttl}
+}
+
+type cachedNode struct {
+	Name          string
+	Version       string
+	Direct        bool
+	DefinedAtLine int
+	Comment       string
+	Children      []string
+	Hash          string
+}
+
+type cachedGraph struct {
+	ModuleName       string
+	ModuleGoVersion  string
+	RootName         string
+	RootRequirements []module.Version
+	Replacements     []Replacement
+	Excludes         []module.Version
+	GodebugSettings  []GodebugSetting
+	Nodes            []cachedNode
+	GoSumEntries     map[string]parser.GoSumEntry
+	GoSumMissing     bool
+}
+
+// Build returns the graph for modFile, read from goModPath, and goSumPath.
+// A cached graph is used when both files are unchanged and the entry is
+// younger than TTL; otherwise the graph is built and stored. Cache failures
+// are not fatal. Without a go.mod path (e.g. stdin) the cache is bypassed.
+// The graph's ModuleDir is the directory of goModPath.
+func (c *GraphCache) Build(modFile *modfile.File, goModPath, goSumPath string) (*EnhancedDependencyGraph, error) {
+	enhancedGraph, err := c.build(modFile, goModPath, goSumPath)
+	if err != nil {
+		return nil, err
+	}
+	if goModPath != "" {
+		enhancedGraph.ModuleDir = filepath.Dir(goModPath)
+	}
+	return enhancedGraph, nil
+}
+
+func (c *GraphCache) build(modFile *modfile.File, goModPath, goSumPath string) (*EnhancedDependencyGraph, error) {
+	if c == nil || goModPath == "" {
+		return BuildEnhancedDependencyGraph(modFile, goSumPath)
+	}
+
+	key, err := graphCacheKey(goModPath, goSumPath)
+	if err != nil {
+		return BuildEnhancedDependencyGraph(modFile, goSumPath)
+	}
+	path := filepath.Join(c.Dir, key+".gob")
+
+	if cached, err := c.load(path); err == nil {
+		return cached.restore(), nil
+	}
+
+	enhancedGraph, err := BuildEnhancedDependencyGraph(modFile, goSumPath)
+	if err != nil {
+		return nil, err
+	}
+	c.store(path, enhancedGraph)
+
+	return enhancedGraph, nil
+}
+
+// Prune removes entries older than TTL and returns how many were removed.
+func (c *GraphCache) Prune() int {
+	entries, err := os.ReadDir(c.Dir)
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) <= c.TTL {
+			continue
+		}
+		if os.Remove(filepath.Join(c.Dir, entry.Name())) == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+func graphCacheKey(goModPath, goSumPath string) (string, error) {
+	goMod, err := os.ReadFile(goModPath)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", graphCacheFormat, goModPath)
+	h.Write(goMod)
+
+	goSum, err := os.ReadFile(goSumPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		h.Write([]byte("\x00missing"))
+	case err != nil:
+		return "", err
+	default:
+		h.Write([]byte("\x00go.sum\x00"))
+		h.Write(goSum)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (c *GraphCache) load(path string) (*cachedGraph, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if time.Since(info.ModTime()) > c.TTL {
+		return nil, fmt.Errorf("cache entry expired")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var cached cachedGraph
+	if err := gob.NewDecoder(file).Decode(&cached); err != nil {
+		return nil, err
+	}
+	return &cached, nil
+}
+
+func (c *GraphCache) store(path string, g *EnhancedDependencyGraph) {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return
+	}
+	c.Prune()
+
+	tmp, err := os.CreateTemp(c.Dir, "graph-*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(newCachedGraph(g)); err != nil {
+		tmp.Close()
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+	os.Rename(tmp.Name(), path)
+}
+
+func newCachedGraph(g *EnhancedDependencyGraph) *cachedGraph {
+	cached := &cachedGraph{
+		ModuleName:       g.ModuleName,
+		ModuleGoVersion:  g.ModuleGoVersion,
+		RootName:         g.Root.Name,
+		RootRequirements: g.RootRequirements,
+		Replacements:     g.Replacements,
+		Excludes:         g.Excludes,
+		GodebugSettings:  g.GodebugSettings,
+		GoSumEntries:     g.GoSumEntries,
+		GoSumMissing:     g.GoSumMissing,
+	}
+
+	var names []string
+	for name := range g.AllNodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		node := g.AllNodes[name]
+		entry := cachedNode{
+			Name:          node.Name,
+			Version:       node.Version,
+			Direct:        node.Direct,
+			DefinedAtLine: node.DefinedAtLine,
+			Comment:       node.Comment,
+		}
+		for _, child := range node.Children {
+			entry.Children = append(entry.Children, child.Name)
+		}
+		if enhancedNode, exists := g.EnhancedNodes[name]; exists {
+			entry.Hash = enhancedNode.Hash
+		}
+		cached.Nodes = append(cached.Nodes, entry)
+	}
+
+	return cached
+}
+
+func (cached *cachedGraph) restore() *EnhancedDependencyGraph {
+	basicGraph := &DependencyGraph{
+		AllNodes:         make(map[string]*Node),
+		ModuleName:       cached.ModuleName,
+		ModuleGoVersion:  cached.ModuleGoVersion,
+		RootRequirements: cached.RootRequirements,
+		Replacements:     cached.Replacements,
+		Excludes:         cached.Excludes,
+		GodebugSettings:  cached.GodebugSettings,
+	}
+
+	goSumEntries := cached.GoSumEntries
+	if goSumEntries == nil {
+		goSumEntries = make(map[string]parser.GoSumEntry)
+	}
+
+	enhancedGraph := &EnhancedDependencyGraph{
+		DependencyGraph: basicGraph,
+		EnhancedNodes:   make(map[string]*EnhancedNode),
+		GoSumEntries:    goSumEntries,
+		GoSumMissing:    cached.GoSumMissing,
+		LicensesSummary: make(map[string]int),
+	}
+
+	for _, entry := range cached.Nodes {
+		node := &Node{
+			Name:          entry.Name,
+			Version:       entry.Version,
+			Direct:        entry.Direct,
+			DefinedAtLine: entry.DefinedAtLine,
+			Comment:       entry.Comment,
+			Children:      make([]*Node, 0),
+		}
+		basicGraph.AllNodes[node.Name] = node
+
+		enhancedNode := &EnhancedNode{
+			Node:           node,
+			Hash:           entry.Hash,
+			Transitive:     make([]*EnhancedNode, 0),
+			Conflicts:      make([]VersionConflict, 0),
+			SecurityIssues: make([]SecurityIssue, 0),
+		}
+		detectPseudoVersion(enhancedNode)
+		enhancedGraph.EnhancedNodes[node.Name] = enhancedNode
+	}
+
+	for _, entry := range cached.Nodes {
+		node := basicGraph.AllNodes[entry.Name]
+		for _, child := range entry.Children {
+			if childNode, exists := basicGraph.AllNodes[child]; exists {
+				node.Children = append(node.Children, childNode)
+			}
+		}
+	}
+	basicGraph.Root = basicGraph.AllNodes[cached.RootName]
+
+	return enhancedGraph
+}
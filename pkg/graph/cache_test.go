@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"goviz/pkg/parser"
+)
+
+func TestGraphCacheRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	goModPath := filepath.Join(dir, "go.mod")
+	goSumPath := filepath.Join(dir, "go.sum")
+	goMod := `module example.com/app
+
+go 1.22
+
+godebug panicnil=1
+
+require (
+	// Pinned until the v2 migration.
+	example.com/a v1.0.0
+	example.com/b v0.0.0-20230101000000-abcdef123456
+	example.com/c v1.2.0 // indirect
+)
+
+replace example.com/a => example.com/fork/a v1.1.0
+
+exclude example.com/c v1.1.0
+`
+	goSum := `example.com/a v1.0.0 h1:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa=
+example.com/a v1.0.0/go.mod h1:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb=
+example.com/c v1.2.0 h1:ccccccccccccccccccccccccccccccccccccccccccc=
+example.com/d v1.3.0 h1:ddddddddddddddddddddddddddddddddddddddddddd=
+example.com/d v1.3.0/go.mod h1:eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee=
+`
+	if err := os.WriteFile(goModPath, []byte(goMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(goSumPath, []byte(goSum), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modFile, err := parser.ParseGoModReader(strings.NewReader(goMod), goModPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	built, err := BuildEnhancedDependencyGraph(modFile, goSumPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := &GraphCache{Dir: filepath.Join(dir, "cache"), TTL: time.Hour}
+	path := filepath.Join(cache.Dir, "graph.gob")
+	cache.store(path, built)
+	cached, err := cache.load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Compare against a graph nobody else holds, so that a field the
+	// cache leaves out cannot be masked by sharing with the stored graph.
+	want, err := BuildEnhancedDependencyGraph(modFile, goSumPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cached.restore(); !reflect.DeepEqual(got, want) {
+		t.Errorf("restored graph differs from a fresh build:\n got %+v\nwant %+v", got.DependencyGraph, want.DependencyGraph)
+	}
+}
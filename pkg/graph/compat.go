@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+)
+
+// ProprietaryLicense stands for a closed-source project license in
+// CheckLicenseCompatibility. LicenseRef-* identifiers are treated the same.
+const ProprietaryLicense = "Proprietary"
+
+// LicenseConflict is a pair of licenses that cannot be combined in one
+// binary, with the modules (or the main module, for the project license)
+// using each.
+type LicenseConflict struct {
+	Licenses [2]string   `json:"licenses" yaml:"licenses"`
+	Reason   string      `json:"reason" yaml:"reason"`
+	Modules  [2][]string `json:"modules" yaml:"modules"`
+}
+
+type licensePair [2]string
+
+// licenseIncompatibilities is the compatibility matrix: every pair of
+// licenses listed here is incompatible within one statically linked Go
+// binary, following the FSF's license list and the license texts; any pair
+// not listed is treated as compatible. Licenses are normalized by
+// compatibilityID first.
+//
+//	GPL-2.0-only  × Apache-2.0, LGPL-3.0, GPL-3.0, AGPL-3.0, EPL-2.0, CDDL-1.0
+//	GPL-2.0-or-later, GPL-3.0, AGPL-3.0 × EPL-2.0, CDDL-1.0
+//	GPL-2.0-only, GPL-2.0-or-later, GPL-3.0, AGPL-3.0 × Proprietary
+var licenseIncompatibilities = map[licensePair]string{
+	{"Apache-2.0", "GPL-2.0-only"}: "Apache-2.0's patent termination and indemnity terms are additional restrictions GPL-2.0 forbids",
+	{"GPL-2.0-only", "LGPL-3.0"}:   "LGPL-3.0 code can only be combined under GPL-3.0 terms, which GPL-2.0-only code does not allow",
+	{"GPL-2.0-only", "GPL-3.0"}:    "the combined work would have to be under both GPL-2.0-only and GPL-3.0, which conflict",
+	{"AGPL-3.0", "GPL-2.0-only"}:   "AGPL-3.0 may only be combined with GPL-3.0, not GPL-2.0-only",
+	{"EPL-2.0", "GPL-2.0-only"}:    "EPL-2.0 is GPL-incompatible unless GPL is designated as a Secondary License",
+	{"CDDL-1.0", "GPL-2.0-only"}:   "CDDL-1.0's file-level copyleft conflicts with the GPL's whole-work copyleft",
+
+	{"EPL-2.0", "GPL-2.0-or-later"}:  "EPL-2.0 is GPL-incompatible unless GPL is designated as a Secondary License",
+	{"CDDL-1.0", "GPL-2.0-or-later"}: "CDDL-1.0's file-level copyleft conflicts with the GPL's whole-work copyleft",
+	{"EPL-2.0", "GPL-3.0"}:           "EPL-2.0 is GPL-incompatible unless GPL is designated as a Secondary License",
+	{"CDDL-1.0", "GPL-3.0"}:          "CDDL-1.0's file-level copyleft conflicts with the GPL's whole-work copyleft",
+	{"AGPL-3.0", "EPL-2.0"}:          "EPL-2.0 is GPL-incompatible unless GPL is designated as a Secondary License",
+	{"AGPL-3.0", "CDDL-1.0"}:         "CDDL-1.0's file-level copyleft conflicts with the GPL's whole-work copyleft",
+
+	{"GPL-2.0-only", ProprietaryLicense}:     "distributing the binary requires releasing it under the GPL",
+	{"GPL-2.0-or-later", ProprietaryLicense}: "distributing the binary requires releasing it under the GPL",
+	{"GPL-3.0", ProprietaryLicense}:          "distributing the binary requires releasing it under the GPL",
+	{"AGPL-3.0", ProprietaryLicense}:         "distributing or serving the binary over a network requires releasing it under the AGPL",
+}
+
+// compatibilityID normalizes an SPDX license ID for the compatibility
+// matrix: the bare and -only forms of GPL-2.0 mean GPL-2.0-only, GPL-2.0+
+// means GPL-2.0-or-later, and version 3 licenses drop their suffixes, since
+// both forms combine the same way.
+func compatibilityID(id string) string {
+	base, _, _ := strings.Cut(id, " WITH ")
+	switch {
+	case base == ProprietaryLicense || strings.EqualFold(base, "proprietary") || strings.HasPrefix(base, "LicenseRef-"):
+		return ProprietaryLicense
+	case base == "GPL-2.0" || base == "GPL-2.0-only":
+		return "GPL-2.0-only"
+	case base == "GPL-2.0+" || base == "GPL-2.0-or-later":
+		return "GPL-2.0-or-later"
+	}
+	base = strings.TrimSuffix(base, "+")
+	base = strings.TrimSuffix(base, "-only")
+	return strings.TrimSuffix(base, "-or-later")
+}
+
+// CheckLicenseCompatibility looks up every pair of licenses used in the
+// graph in the compatibility matrix and records the incompatible ones in
+// LicenseConflicts. Modules count under the most permissive alternative of
+// their license expression. projectLicense, if set, is the main module's
+// own license (e.g. ProprietaryLicense) and is checked against all others.
+// It relies on the licenses set by AnalyzeLicenses.
+func (g *EnhancedDependencyGraph) CheckLicenseCompatibility(projectLicense string) []LicenseConflict {
+	users := make(map[string][]string)
+	for name, node := range g.EnhancedNodes {
+		if name == g.Root.Name {
+			continue
+		}
+		for _, id := range PreferredLicenses(node.License) {
+			users[compatibilityID(id)] = append(users[compatibilityID(id)], name)
+		}
+	}
+	if projectLicense != "" {
+		id := compatibilityID(projectLicense)
+		users[id] = append(users[id], g.Root.Name)
+	}
+
+	var licenses []string
+	for license, modules := range users {
+		sort.Strings(modules)
+		licenses = append(licenses, license)
+	}
+	sort.Strings(licenses)
+
+	g.LicenseConflicts = nil
+	for i, a := range licenses {
+		for _, b := range licenses[i+1:] {
+			if reason, ok := licenseIncompatibilities[licensePair{a, b}]; ok {
+				g.LicenseConflicts = append(g.LicenseConflicts, LicenseConflict{
+					Licenses: [2]string{a, b},
+					Reason:   reason,
+					Modules:  [2][]string{users[a], users[b]},
+				})
+			}
+		}
+	}
+	return g.LicenseConflicts
+}
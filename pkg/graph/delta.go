@@ -0,0 +1,165 @@
+package graph
+
+import (
+	"sort"
+
+	"goviz/pkg/parser"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// ModuleChange is a dependency that differs from an earlier version of the
+// project. OldVersion is empty for added modules and NewVersion is empty
+// for removed ones. Downgrade marks changes to an older version, which are
+// often accidental.
+type ModuleChange struct {
+	Path       string `json:"path" yaml:"path"`
+	OldVersion string `json:"old_version,omitempty" yaml:"old_version,omitempty"`
+	NewVersion string `json:"new_version,omitempty" yaml:"new_version,omitempty"`
+	Downgrade  bool   `json:"downgrade,omitempty" yaml:"downgrade,omitempty"`
+
+	// Bump classifies a version change, see VersionBump.
+	Bump string `json:"bump,omitempty" yaml:"bump,omitempty"`
+}
+
+// Version bump kinds returned by VersionBump.
+const (
+	BumpMajor  = "major"
+	BumpMinor  = "minor"
+	BumpPatch  = "patch"
+	BumpPseudo = "pseudo"
+)
+
+// BumpKinds lists the bump kinds from the most to the least significant.
+var BumpKinds = []string{BumpMajor, BumpMinor, BumpPatch, BumpPseudo}
+
+// ChangesSince compares the dependencies of g with those of old and returns
+// the added, changed and removed modules, sorted by path.
+func (g *EnhancedDependencyGraph) ChangesSince(old *EnhancedDependencyGraph) []ModuleChange {
+	return DiffVersions(old.dependencyVersions(), g.dependencyVersions())
+}
+
+// DiffVersions compares two sets of module versions, keyed by module path,
+// and returns the added, changed and removed modules, sorted by path.
+func DiffVersions(oldVersions, newVersions map[string]string) []ModuleChange {
+	changes := make([]ModuleChange, 0)
+
+	for path, version := range newVersions {
+		oldVersion, exists := oldVersions[path]
+		switch {
+		case !exists:
+			changes = append(changes, ModuleChange{Path: path, NewVersion: version})
+		case oldVersion != version:
+			changes = append(changes, ModuleChange{
+				Path:       path,
+				OldVersion: oldVersion,
+				NewVersion: version,
+				Downgrade:  CompareModuleVersions(version, oldVersion) < 0,
+				Bump:       VersionBump(oldVersion, version),
+			})
+		}
+	}
+
+	for path, version := range oldVersions {
+		if _, exists := newVersions[path]; !exists {
+			changes = append(changes, ModuleChange{Path: path, OldVersion: version})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// VersionBump classifies a change between two versions by the most
+// significant component that differs: major, minor or patch, where a
+// change of only the prerelease counts as patch. Changes from or to a
+// pseudo-version are pseudo, as its base version says little about the
+// commit it points at.
+func VersionBump(oldVersion, newVersion string) string {
+	switch {
+	case module.IsPseudoVersion(oldVersion) || module.IsPseudoVersion(newVersion):
+		return BumpPseudo
+	case semver.Major(oldVersion) != semver.Major(newVersion):
+		return BumpMajor
+	case semver.MajorMinor(oldVersion) != semver.MajorMinor(newVersion):
+		return BumpMinor
+	default:
+		return BumpPatch
+	}
+}
+
+// CompareModuleVersions compares two module versions like semver.Compare,
+// except that two pseudo-versions are ordered by their commit timestamps,
+// since their base versions need not reflect the order of the commits.
+func CompareModuleVersions(a, b string) int {
+	if module.IsPseudoVersion(a) && module.IsPseudoVersion(b) {
+		ta, errA := module.PseudoVersionTime(a)
+		tb, errB := module.PseudoVersionTime(b)
+		if errA == nil && errB == nil && !ta.Equal(tb) {
+			return ta.Compare(tb)
+		}
+	}
+	return semver.Compare(a, b)
+}
+
+// dependencyVersions returns the version of every dependency, keyed by
+// module path.
+func (g *EnhancedDependencyGraph) dependencyVersions() map[string]string {
+	versions := make(map[string]string, len(g.EnhancedNodes))
+	for name, node := range g.EnhancedNodes {
+		if name != g.Root.Name {
+			versions[name] = node.Version
+		}
+	}
+	return versions
+}
+
+// RetainChanged removes every dependency that is not added or changed in
+// changes, so later passes only look at the delta, and records changes on
+// the graph.
+func (g *EnhancedDependencyGraph) RetainChanged(changes []ModuleChange) {
+	keep := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		if change.NewVersion != "" {
+			keep[change.Path] = true
+		}
+	}
+
+	var remove []string
+	for name := range g.EnhancedNodes {
+		if name != g.Root.Name && !keep[name] {
+			remove = append(remove, name)
+		}
+	}
+	for _, name := range remove {
+		g.RemoveNode(name)
+	}
+
+	g.Changes = changes
+}
+
+// RetainModule removes every dependency except path, together with the
+// go.sum entries of other modules, so later passes only look at that one
+// module.
+func (g *EnhancedDependencyGraph) RetainModule(path string) {
+	var remove []string
+	for name := range g.EnhancedNodes {
+		if name != g.Root.Name && name != path {
+			remove = append(remove, name)
+		}
+	}
+	for _, name := range remove {
+		g.RemoveNode(name)
+	}
+
+	entries := make(map[string]parser.GoSumEntry)
+	for key, entry := range g.GoSumEntries {
+		if entry.ModulePath == path {
+			entries[key] = entry
+		}
+	}
+	g.GoSumEntries = entries
+}
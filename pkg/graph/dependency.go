@@ -1,7 +1,11 @@
 package graph
 
 import (
+	"sort"
+	"strings"
+
 	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
 )
 
 type Node struct {
@@ -9,6 +13,14 @@ type Node struct {
 	Version  string
 	Direct   bool
 	Children []*Node
+
+	// DefinedAtLine is the go.mod line of the require directive, or 0 when
+	// the module was discovered elsewhere (e.g. only in go.sum).
+	DefinedAtLine int
+
+	// Comment is the text of the comments on and above the require line,
+	// without the "// indirect" marker, e.g. why the team added it.
+	Comment string
 }
 
 type DependencyGraph struct {
@@ -16,6 +28,59 @@ type DependencyGraph struct {
 	AllNodes        map[string]*Node
 	ModuleName      string
 	ModuleGoVersion string
+
+	// RootRequirements are the require directives of the main module.
+	RootRequirements []module.Version
+
+	// Replacements are the replace directives of the main module.
+	Replacements []Replacement
+
+	// Excludes are the module versions excluded by the exclude directives
+	// of the main module.
+	Excludes []module.Version
+
+	// ModuleDir is the directory of the main module's go.mod, which local
+	// replace directives are relative to; empty when go.mod was not read
+	// from disk.
+	ModuleDir string
+
+	// GodebugSettings are the godebug directives of the main module.
+	GodebugSettings []GodebugSetting
+}
+
+type Replacement struct {
+	Old module.Version
+	New module.Version
+
+	// DefinedAtLine is the go.mod line of the replace directive.
+	DefinedAtLine int
+}
+
+// replacementOf returns the replace directive that applies to mv. As in
+// the go command, a directive for that exact version takes precedence over
+// one for every version of the module.
+func (g *DependencyGraph) replacementOf(mv module.Version) (Replacement, bool) {
+	var wildcard Replacement
+	found := false
+	for _, replacement := range g.Replacements {
+		if replacement.Old.Path != mv.Path {
+			continue
+		}
+		if replacement.Old.Version == mv.Version {
+			return replacement, true
+		}
+		if replacement.Old.Version == "" {
+			wildcard, found = replacement, true
+		}
+	}
+	return wildcard, found
+}
+
+// IsLocal reports whether the replacement points at a directory on disk
+// (e.g. "=> ../fork") rather than at another module version. Local
+// replacements only work inside the main module and break its consumers.
+func (r Replacement) IsLocal() bool {
+	return r.New.Version == "" && modfile.IsDirectoryPath(r.New.Path)
 }
 
 func BuildDependencyGraph(modFile *modfile.File) *DependencyGraph {
@@ -38,12 +103,18 @@ func BuildDependencyGraph(modFile *modfile.File) *DependencyGraph {
 	graph.AllNodes[root.Name] = root
 
 	for _, require := range modFile.Require {
+		graph.RootRequirements = append(graph.RootRequirements, require.Mod)
+
 		node := &Node{
 			Name:     require.Mod.Path,
 			Version:  require.Mod.Version,
 			Direct:   !require.Indirect,
 			Children: make([]*Node, 0),
 		}
+		if require.Syntax != nil {
+			node.DefinedAtLine = require.Syntax.Start.Line
+			node.Comment = requireComment(require.Syntax)
+		}
 
 		graph.AllNodes[node.Name] = node
 
@@ -52,13 +123,67 @@ func BuildDependencyGraph(modFile *modfile.File) *DependencyGraph {
 		}
 	}
 
+	for _, replace := range modFile.Replace {
+		replacement := Replacement{Old: replace.Old, New: replace.New}
+		if replace.Syntax != nil {
+			replacement.DefinedAtLine = replace.Syntax.Start.Line
+		}
+		graph.Replacements = append(graph.Replacements, replacement)
+	}
+
+	for _, exclude := range modFile.Exclude {
+		graph.Excludes = append(graph.Excludes, exclude.Mod)
+	}
+
+	for _, godebug := range modFile.Godebug {
+		setting := GodebugSetting{
+			Key:      godebug.Key,
+			Value:    godebug.Value,
+			Insecure: insecureGodebugs[godebug.Key+"="+godebug.Value],
+		}
+		if godebug.Syntax != nil {
+			setting.DefinedAtLine = godebug.Syntax.Start.Line
+		}
+		graph.GodebugSettings = append(graph.GodebugSettings, setting)
+	}
+
 	return graph
 }
 
+// requireComment joins the comments above a require line and after it. The
+// "indirect" marker the go command maintains is dropped, keeping any reason
+// written after it ("// indirect; needed by the tests").
+func requireComment(line *modfile.Line) string {
+	var parts []string
+	for _, comment := range line.Comments.Before {
+		if text := commentText(comment.Token); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	for _, comment := range line.Comments.Suffix {
+		text := commentText(comment.Token)
+		if text == "indirect" {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(text, "indirect;"); ok {
+			text = strings.TrimSpace(rest)
+		}
+		if text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func commentText(token string) string {
+	return strings.TrimSpace(strings.TrimPrefix(token, "//"))
+}
+
 func (g *DependencyGraph) GetDirectDependencies() []*Node {
 	return g.Root.Children
 }
 
+// GetAllDependencies returns every module except the root, sorted by path.
 func (g *DependencyGraph) GetAllDependencies() []*Node {
 	var deps []*Node
 	for name, node := range g.AllNodes {
@@ -66,6 +191,9 @@ func (g *DependencyGraph) GetAllDependencies() []*Node {
 			deps = append(deps, node)
 		}
 	}
+	sort.Slice(deps, func(i, j int) bool {
+		return deps[i].Name < deps[j].Name
+	})
 	return deps
 }
 
@@ -81,3 +209,22 @@ func (g *DependencyGraph) GetDependencyCount() (direct, indirect int) {
 	}
 	return direct, indirect
 }
+
+// RemoveNode drops a module from the graph along with every edge pointing
+// to it. The root module cannot be removed.
+func (g *DependencyGraph) RemoveNode(name string) {
+	if name == g.Root.Name {
+		return
+	}
+
+	delete(g.AllNodes, name)
+	for _, node := range g.AllNodes {
+		children := node.Children[:0]
+		for _, child := range node.Children {
+			if child.Name != name {
+				children = append(children, child)
+			}
+		}
+		node.Children = children
+	}
+}
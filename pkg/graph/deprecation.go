@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/mod/modfile"
+)
+
+// DeprecationSource finds the latest version of a module and serves its
+// go.mod, whose module directive carries the "// Deprecated:" notice.
+type DeprecationSource interface {
+	LatestVersion(ctx context.Context, modulePath string) (string, error)
+	GoMod(ctx context.Context, modulePath, version string) ([]byte, error)
+}
+
+// LoadDeprecations reads the go.mod of the latest version of every
+// dependency, like the go command does, and records its deprecation
+// message in Deprecated. Private modules are skipped. It returns the error
+// for every module that could not be checked.
+func (g *EnhancedDependencyGraph) LoadDeprecations(ctx context.Context, source DeprecationSource) map[string]error {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed = make(map[string]error)
+	)
+	sem := make(chan struct{}, requirementFetchWorkers)
+
+	for name, node := range g.EnhancedNodes {
+		node.Deprecated = ""
+		if name == g.Root.Name || node.Private {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(node *EnhancedNode) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			deprecated, err := fetchDeprecation(ctx, source, node.Name)
+			if err != nil {
+				mu.Lock()
+				failed[node.Name] = err
+				mu.Unlock()
+				return
+			}
+			node.Deprecated = deprecated
+		}(node)
+	}
+	wg.Wait()
+
+	return failed
+}
+
+func fetchDeprecation(ctx context.Context, source DeprecationSource, modulePath string) (string, error) {
+	latest, err := source.LatestVersion(ctx, modulePath)
+	if err != nil {
+		return "", err
+	}
+	data, err := source.GoMod(ctx, modulePath, latest)
+	if err != nil {
+		return "", err
+	}
+	modFile, err := modfile.ParseLax(modulePath+"@"+latest+"/go.mod", data, nil)
+	if err != nil {
+		return "", err
+	}
+	if modFile.Module == nil {
+		return "", nil
+	}
+	return strings.Join(strings.Fields(modFile.Module.Deprecated), " "), nil
+}
+
+// DeprecatedNodes returns the dependencies whose upstream is deprecated,
+// sorted by name.
+func (g *EnhancedDependencyGraph) DeprecatedNodes() []*EnhancedNode {
+	var nodes []*EnhancedNode
+	for name, node := range g.EnhancedNodes {
+		if name != g.Root.Name && node.Deprecated != "" {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	return nodes
+}
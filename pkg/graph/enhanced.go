@@ -1,6 +1,11 @@
+// Package graph builds the dependency graph of a module and runs the
+// analyses on it. Nothing is shared between graphs: separate graphs can be
+// built, analyzed and reported on from concurrent goroutines. A single
+// graph is not safe for concurrent modification.
 package graph
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -9,6 +14,8 @@ import (
 	"goviz/pkg/parser"
 
 	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
 )
 
 type EnhancedNode struct {
@@ -18,9 +25,72 @@ type EnhancedNode struct {
 	Conflicts       []VersionConflict
 	SecurityIssues  []SecurityIssue
 	License         string
+	LicenseText     string
+	RepoURL         string
 	LastUpdate      time.Time
 	IsOutdated      bool
 	UpdateAvailable string
+
+	IsPseudoVersion   bool
+	PseudoVersionTime time.Time
+	PseudoVersionRev  string
+
+	// TestOnly marks modules that are not needed to build the non-test
+	// packages of the main module.
+	TestOnly bool
+
+	// Imported is set when the main module or its tests import one of the
+	// module's packages, directly or transitively; ImportedPackages lists
+	// them. Both are only meaningful when the graph's PackagesLoaded is set.
+	Imported         bool
+	ImportedPackages []string
+
+	// Unused marks direct dependencies none of whose packages are imported,
+	// which 'go mod tidy' would remove or demote to indirect.
+	Unused bool
+
+	// DirectImports lists the packages of the module that the main module
+	// or its tests import themselves rather than through another
+	// dependency. MissingDirect marks modules with such imports that go.mod
+	// lists as indirect or not at all; 'go mod tidy' would make them direct.
+	DirectImports []string
+	MissingDirect bool
+
+	// Private marks modules matched by the GOPRIVATE-style patterns given to
+	// MarkPrivate; NotScannedBy lists the remote vulnerability providers
+	// that were therefore not queried for them.
+	Private      bool
+	NotScannedBy []string
+
+	// RequiresCGO marks modules with packages that import "C", listed in
+	// CGOPackages; UnsupportedOS lists the GOOS values for which none of
+	// their inspected packages has buildable Go files. PlatformUnchecked is
+	// set when the module source was not in the module cache. See
+	// LoadPlatformConstraints.
+	RequiresCGO       bool
+	CGOPackages       []string
+	UnsupportedOS     []string
+	PlatformUnchecked bool
+	// VersionUnavailable marks modules whose version the module proxy no
+	// longer serves, so builds without a warm module cache fail. See
+	// CheckVersionsAvailable.
+	VersionUnavailable bool
+
+	// GoVersion is the go directive of the module's own go.mod, the
+	// minimum Go version it declares. See LoadGoVersions.
+	GoVersion string
+
+	// LatestRelease is when the module's latest version was published,
+	// zero until LoadReleaseDates succeeds for it.
+	LatestRelease time.Time
+
+	// Deprecated is the deprecation notice of the module's latest go.mod,
+	// empty when it is not deprecated. See LoadDeprecations.
+	Deprecated string
+
+	// Provenance is whether the checksum database vouches for the version,
+	// one of the Provenance constants; empty until LoadProvenance runs.
+	Provenance string
 }
 
 type VersionConflict struct {
@@ -31,29 +101,85 @@ type VersionConflict struct {
 }
 
 type SecurityIssue struct {
+	Module      string
+	Version     string
 	ID          string
 	Severity    string
+	CVSSScore   float64
 	Description string
 	FixedIn     string
+
+	// Provider is the name of the VulnProvider that reported the issue.
+	Provider string
+}
+
+// Heuristic reports whether the issue comes from HeuristicProvider rather
+// than an advisory database.
+func (i SecurityIssue) Heuristic() bool {
+	return i.Provider == HeuristicProvider{}.Name()
 }
 
 type EnhancedDependencyGraph struct {
 	*DependencyGraph
 	EnhancedNodes   map[string]*EnhancedNode
 	GoSumEntries    map[string]parser.GoSumEntry
+	GoSumMissing    bool
 	Conflicts       []VersionConflict
 	SecurityIssues  []SecurityIssue
 	TotalSize       int64
 	BuildTime       time.Duration
 	LicensesSummary map[string]int
+
+	// LicenseConflicts are the incompatible license pairs found by
+	// CheckLicenseCompatibility.
+	LicenseConflicts []LicenseConflict
+
+	// Overlaps are the groups of alternative modules found by
+	// DetectOverlaps.
+	Overlaps []Overlap
+
+	Requirements      map[module.Version][]module.Version
+	UnresolvedModules []string
+
+	// ExcludedUpgrades maps requirements on versions excluded by the main
+	// module to the higher version LoadRequirementGraph loaded instead.
+	ExcludedUpgrades map[module.Version]module.Version
+
+	// ProvenanceDatabase names the checksum database LoadProvenance looked
+	// the dependencies up in; empty when it has not run.
+	ProvenanceDatabase string
+
+	// PackagesLoaded reports whether MarkImported has recorded package
+	// usage from the project sources.
+	PackagesLoaded bool
+
+	// Changes lists the dependency changes the graph was restricted to by
+	// RetainChanged; nil for a full analysis.
+	Changes []ModuleChange
+
+	// Excluded lists the dependencies removed by ExcludeModules.
+	Excluded []string
+
+	// DirectUnknown is set when the source of the graph, such as the build
+	// info of a binary, does not record which modules the main module
+	// requires directly. They are all indirect then, and the statistics
+	// leave out the indirect ratio.
+	DirectUnknown bool
+
+	// Warnings collects the non-fatal problems that make the analysis
+	// incomplete.
+	Warnings []Warning
 }
 
 func BuildEnhancedDependencyGraph(modFile *modfile.File, goSumPath string) (*EnhancedDependencyGraph, error) {
 
 	basicGraph := BuildDependencyGraph(modFile)
 
+	goSumMissing := false
 	goSumEntries, err := parser.ParseGoSum(goSumPath)
-	if err != nil {
+	if errors.Is(err, parser.ErrGoSumNotFound) {
+		goSumMissing = true
+	} else if err != nil {
 		return nil, fmt.Errorf("failed to parse go.sum: %w", err)
 	}
 
@@ -61,6 +187,7 @@ func BuildEnhancedDependencyGraph(modFile *modfile.File, goSumPath string) (*Enh
 		DependencyGraph: basicGraph,
 		EnhancedNodes:   make(map[string]*EnhancedNode),
 		GoSumEntries:    goSumEntries,
+		GoSumMissing:    goSumMissing,
 		LicensesSummary: make(map[string]int),
 	}
 
@@ -72,10 +199,10 @@ func BuildEnhancedDependencyGraph(modFile *modfile.File, goSumPath string) (*Enh
 			SecurityIssues: make([]SecurityIssue, 0),
 		}
 
-		key := name + "@" + node.Version
-		if entry, exists := goSumEntries[key]; exists {
+		if entry, exists := parser.LookupGoSum(goSumEntries, name, node.Version); exists {
 			enhancedNode.Hash = entry.Hash
 		}
+		detectPseudoVersion(enhancedNode)
 
 		enhancedGraph.EnhancedNodes[name] = enhancedNode
 	}
@@ -99,6 +226,7 @@ func BuildEnhancedDependencyGraph(modFile *modfile.File, goSumPath string) (*Enh
 				Conflicts:      make([]VersionConflict, 0),
 				SecurityIssues: make([]SecurityIssue, 0),
 			}
+			detectPseudoVersion(enhancedNode)
 
 			enhancedGraph.EnhancedNodes[transDep.ModulePath] = enhancedNode
 			enhancedGraph.AllNodes[transDep.ModulePath] = node
@@ -108,16 +236,196 @@ func BuildEnhancedDependencyGraph(modFile *modfile.File, goSumPath string) (*Enh
 	return enhancedGraph, nil
 }
 
+// detectPseudoVersion records the commit time and revision encoded in
+// pseudo-versions such as v0.0.0-20230101000000-abcdef123456.
+func detectPseudoVersion(node *EnhancedNode) {
+	if !module.IsPseudoVersion(node.Version) {
+		return
+	}
+
+	node.IsPseudoVersion = true
+	if t, err := module.PseudoVersionTime(node.Version); err == nil {
+		node.PseudoVersionTime = t
+	}
+	if rev, err := module.PseudoVersionRev(node.Version); err == nil {
+		node.PseudoVersionRev = rev
+	}
+}
+
+func (g *EnhancedDependencyGraph) PseudoVersionNodes() []*EnhancedNode {
+	var nodes []*EnhancedNode
+	for name, node := range g.EnhancedNodes {
+		if name == g.Root.Name || !node.IsPseudoVersion {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	return nodes
+}
+
+func (g *EnhancedDependencyGraph) RemoveNode(name string) {
+	if name == g.Root.Name {
+		return
+	}
+	g.DependencyGraph.RemoveNode(name)
+	delete(g.EnhancedNodes, name)
+}
+
+// MarkTestOnly flags the dependencies in testOnly, the modules that only
+// the tests of the main module need. Modules not known to be test-only,
+// including ones the toolchain did not list at all, stay unflagged.
+func (g *EnhancedDependencyGraph) MarkTestOnly(testOnly map[string]bool) int {
+	count := 0
+	for name, node := range g.EnhancedNodes {
+		if name == g.Root.Name {
+			continue
+		}
+		node.TestOnly = testOnly[name]
+		if node.TestOnly {
+			count++
+		}
+	}
+	return count
+}
+
+// MarkImported records the imported packages of every dependency from
+// packages, keyed by module path, and returns how many modules are
+// imported.
+func (g *EnhancedDependencyGraph) MarkImported(packages map[string][]string) int {
+	count := 0
+	for name, node := range g.EnhancedNodes {
+		if name == g.Root.Name {
+			continue
+		}
+		node.ImportedPackages = packages[name]
+		node.Imported = len(node.ImportedPackages) > 0
+		node.Unused = node.Direct && !node.Imported
+		if node.Imported {
+			count++
+		}
+	}
+	g.PackagesLoaded = true
+	return count
+}
+
+// MarkDirectImports records which packages of every dependency are among
+// imports, the import paths used by the main module itself, and returns how
+// many modules are imported directly without being direct requirements. It
+// relies on the packages recorded by MarkImported.
+func (g *EnhancedDependencyGraph) MarkDirectImports(imports map[string]bool) int {
+	count := 0
+	for name, node := range g.EnhancedNodes {
+		if name == g.Root.Name {
+			continue
+		}
+		node.DirectImports = nil
+		for _, pkg := range node.ImportedPackages {
+			if imports[pkg] {
+				node.DirectImports = append(node.DirectImports, pkg)
+			}
+		}
+		node.MissingDirect = !node.Direct && len(node.DirectImports) > 0
+		if node.MissingDirect {
+			count++
+		}
+	}
+	return count
+}
+
+// MissingDirectNodes returns the dependencies marked MissingDirect, sorted by
+// module path.
+func (g *EnhancedDependencyGraph) MissingDirectNodes() []*EnhancedNode {
+	var nodes []*EnhancedNode
+	for name, node := range g.EnhancedNodes {
+		if name != g.Root.Name && node.MissingDirect {
+			nodes = append(nodes, node)
+		}
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	return nodes
+}
+
+// NotImportedNodes returns the dependencies listed in go.mod that provide
+// no imported package, sorted by module path.
+func (g *EnhancedDependencyGraph) NotImportedNodes() []*EnhancedNode {
+	var nodes []*EnhancedNode
+	for name, node := range g.EnhancedNodes {
+		if name == g.Root.Name || node.Imported {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	return nodes
+}
+
+// UnusedNodes returns the direct dependencies marked Unused, sorted by
+// module path.
+func (g *EnhancedDependencyGraph) UnusedNodes() []*EnhancedNode {
+	var nodes []*EnhancedNode
+	for _, node := range g.NotImportedNodes() {
+		if node.Unused {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// PruneTestOnly removes test-only dependencies and returns their names.
+func (g *EnhancedDependencyGraph) PruneTestOnly() []string {
+	var removed []string
+	for name, node := range g.EnhancedNodes {
+		if node.TestOnly {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+
+	for _, name := range removed {
+		g.RemoveNode(name)
+	}
+	return removed
+}
+
 func (g *EnhancedDependencyGraph) DetectVersionConflicts() {
 	versionMap := make(map[string][]string)
 
 	for _, entry := range g.GoSumEntries {
-		versionMap[entry.ModulePath] = append(versionMap[entry.ModulePath], entry.Version)
+		// Versions with only a go.mod hash were read for the module graph
+		// but are not part of the build.
+		if entry.Hash != "" {
+			versionMap[entry.ModulePath] = append(versionMap[entry.ModulePath], entry.Version)
+		}
 	}
 
-	for modulePath, versions := range versionMap {
+	modulePaths := make([]string, 0, len(versionMap))
+	for modulePath := range versionMap {
+		modulePaths = append(modulePaths, modulePath)
+	}
+	sort.Strings(modulePaths)
+
+	for _, modulePath := range modulePaths {
+		versions := versionMap[modulePath]
 		if len(versions) > 1 {
-			sort.Strings(versions)
+			sort.Slice(versions, func(i, j int) bool {
+				if c := semver.Compare(versions[i], versions[j]); c != 0 {
+					return c < 0
+				}
+				return versions[i] < versions[j]
+			})
 			for i := 0; i < len(versions)-1; i++ {
 				conflict := VersionConflict{
 					ModulePath:      modulePath,
@@ -143,7 +451,7 @@ func (g *EnhancedDependencyGraph) AnalyzeLicenses() error {
 		"github.com/awalterschulze/gographviz": "Apache-2.0",
 		"github.com/inconshreveable/mousetrap": "Apache-2.0",
 		"golang.org/x/mod":                     "BSD-3-Clause",
-		"gopkg.in/yaml.v3":                     "Apache-2.0",
+		"gopkg.in/yaml.v3":                     "MIT AND Apache-2.0",
 		"github.com/google/licensecheck":       "BSD-3-Clause",
 		"github.com/fatih/color":               "MIT",
 	}
@@ -151,114 +459,21 @@ func (g *EnhancedDependencyGraph) AnalyzeLicenses() error {
 	for name, node := range g.EnhancedNodes {
 		if license, exists := knownLicenses[name]; exists {
 			node.License = license
-			g.LicensesSummary[license]++
 		} else {
 
 			if strings.Contains(name, "golang.org/x/") {
 				node.License = "BSD-3-Clause"
-				g.LicensesSummary["BSD-3-Clause"]++
 			} else if strings.Contains(name, "github.com/mattn/") {
 				node.License = "MIT"
-				g.LicensesSummary["MIT"]++
 			} else {
 				node.License = "Unknown"
-				g.LicensesSummary["Unknown"]++
 			}
 		}
-	}
-
-	return nil
-}
 
-func (g *EnhancedDependencyGraph) CheckSecurity() error {
-
-	vulnerablePatterns := map[string]SecurityIssue{
-
-		"github.com/gin-gonic/gin": {
-			ID:          "GHSA-example",
-			Severity:    "MEDIUM",
-			Description: "Check for latest version with security fixes",
-			FixedIn:     "v1.9.1+",
-		},
-		"github.com/gorilla/websocket": {
-			ID:          "CVE-2023-example",
-			Severity:    "HIGH",
-			Description: "WebSocket vulnerability in older versions",
-			FixedIn:     "v1.5.0+",
-		},
-	}
-
-	for name, node := range g.EnhancedNodes {
-		if name == g.Root.Name {
-			continue
-		}
-
-		if issue, exists := vulnerablePatterns[name]; exists {
-
-			if strings.Contains(node.Version, "v1.8") ||
-				strings.Contains(node.Version, "v1.7") ||
-				strings.Contains(node.Version, "v1.4") {
-				node.SecurityIssues = append(node.SecurityIssues, issue)
-				g.SecurityIssues = append(g.SecurityIssues, issue)
-			}
-		}
-
-		if strings.Contains(node.Version, "dev") ||
-			strings.Contains(node.Version, "alpha") ||
-			strings.Contains(node.Version, "beta") ||
-			strings.Contains(node.Version, "rc") ||
-			strings.Contains(node.Version, "snapshot") {
-			issue := SecurityIssue{
-				ID:          "DEV-VERSION",
-				Severity:    "LOW",
-				Description: "Development version detected in dependencies",
-				FixedIn:     "Use stable release version",
-			}
-			node.SecurityIssues = append(node.SecurityIssues, issue)
-			g.SecurityIssues = append(g.SecurityIssues, issue)
-		}
-
-		if strings.Contains(node.Version, "20161208") ||
-			strings.Contains(node.Version, "20170") ||
-			strings.Contains(node.Version, "20180") {
-			issue := SecurityIssue{
-				ID:          "OLD-VERSION",
-				Severity:    "MEDIUM",
-				Description: "Very old package version may have security vulnerabilities",
-				FixedIn:     "Update to latest version",
-			}
-			node.SecurityIssues = append(node.SecurityIssues, issue)
-			g.SecurityIssues = append(g.SecurityIssues, issue)
-		}
-
-		insecurePatterns := []string{
-			"crypto/md5",
-			"crypto/sha1",
-			"net/http/httputil",
-		}
-
-		for _, pattern := range insecurePatterns {
-			if strings.Contains(name, pattern) {
-				issue := SecurityIssue{
-					ID:          "INSECURE-CRYPTO",
-					Severity:    "HIGH",
-					Description: "Package uses insecure cryptographic functions",
-					FixedIn:     "Use secure alternatives (SHA-256, bcrypt, etc.)",
-				}
-				node.SecurityIssues = append(node.SecurityIssues, issue)
-				g.SecurityIssues = append(g.SecurityIssues, issue)
-			}
-		}
-
-		if node.Version == "" || node.Version == "v0.0.0" {
-			issue := SecurityIssue{
-				ID:          "NO-VERSION",
-				Severity:    "LOW",
-				Description: "Package without proper versioning detected",
-				FixedIn:     "Use properly versioned packages",
-			}
-			node.SecurityIssues = append(node.SecurityIssues, issue)
-			g.SecurityIssues = append(g.SecurityIssues, issue)
+		// License may be an SPDX expression; the summary counts the
+		// licenses of its most permissive alternative.
+		for _, license := range PreferredLicenses(node.License) {
+			g.LicensesSummary[license]++
 		}
 	}
 
@@ -267,7 +482,13 @@ func (g *EnhancedDependencyGraph) CheckSecurity() error {
 
 func (g *EnhancedDependencyGraph) GetStatistics() map[string]any {
 	direct, indirect := g.GetDependencyCount()
-	transitive := len(g.GoSumEntries) - direct - indirect
+	transitive := 0
+	for _, entry := range g.GoSumEntries {
+		if entry.Hash != "" {
+			transitive++
+		}
+	}
+	transitive -= direct + indirect
 
 	stats := map[string]any{
 		"total_dependencies":      len(g.AllNodes) - 1,
@@ -279,6 +500,14 @@ func (g *EnhancedDependencyGraph) GetStatistics() map[string]any {
 		"unique_licenses":         len(g.LicensesSummary),
 		"licenses_breakdown":      g.LicensesSummary,
 	}
+	if len(g.Excluded) > 0 {
+		stats["excluded_dependencies"] = len(g.Excluded)
+	}
+	if g.DirectUnknown {
+		stats["direct_unknown"] = true
+	} else {
+		stats["indirect_ratio"] = g.IndirectRatio()
+	}
 
 	return stats
 }
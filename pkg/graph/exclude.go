@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"fmt"
+	"path"
+	"sort"
+)
+
+// ExcludeModules removes the dependencies matching patterns, module paths
+// or path.Match patterns such as "github.com/org/*", together with their
+// go.sum entries, so every later pass and report sees the graph without
+// them. Modules they require are kept: other dependencies may need them
+// too, and go.mod lists them in its own right. The removed names are
+// recorded in Excluded and returned, with the patterns that matched
+// nothing.
+func (g *EnhancedDependencyGraph) ExcludeModules(patterns []string) (excluded, unmatched []string, err error) {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, nil, fmt.Errorf("invalid module pattern %q: %w", pattern, err)
+		}
+	}
+
+	remove := make(map[string]bool)
+	for _, pattern := range patterns {
+		matched := false
+		for name := range g.EnhancedNodes {
+			if name == g.Root.Name {
+				continue
+			}
+			if ok, _ := path.Match(pattern, name); ok {
+				remove[name] = true
+				matched = true
+			}
+		}
+		if !matched {
+			unmatched = append(unmatched, pattern)
+		}
+	}
+
+	for name := range remove {
+		g.RemoveNode(name)
+		excluded = append(excluded, name)
+	}
+	sort.Strings(excluded)
+
+	for key, entry := range g.GoSumEntries {
+		if remove[entry.ModulePath] {
+			delete(g.GoSumEntries, key)
+		}
+	}
+
+	g.Excluded = append(g.Excluded, excluded...)
+	sort.Strings(g.Excluded)
+	return excluded, unmatched, nil
+}
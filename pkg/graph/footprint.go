@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"math"
+	"sort"
+)
+
+// DependencyFootprint is the number of modules a direct dependency pulls in
+// along requirement edges, itself excluded.
+type DependencyFootprint struct {
+	Module     string `json:"module" yaml:"module"`
+	Version    string `json:"version" yaml:"version"`
+	Transitive int    `json:"transitive" yaml:"transitive"`
+}
+
+// IndirectRatio returns the number of indirect dependencies per direct
+// dependency, rounded to two decimals. Without direct dependencies every
+// indirect one counts in full.
+func (g *EnhancedDependencyGraph) IndirectRatio() float64 {
+	direct, indirect := g.GetDependencyCount()
+	ratio := float64(indirect) / float64(max(direct, 1))
+	return math.Round(ratio*100) / 100
+}
+
+// DependencyFootprints returns the footprint of every direct dependency,
+// largest first. It needs the requirement edges of LoadRequirementGraph
+// and returns nil until they are loaded.
+func (g *EnhancedDependencyGraph) DependencyFootprints() []DependencyFootprint {
+	if g.Requirements == nil {
+		return nil
+	}
+
+	var footprints []DependencyFootprint
+	for _, node := range g.SortedNodes("name") {
+		if !node.Direct || node.Name == g.Root.Name {
+			continue
+		}
+
+		seen := map[string]bool{node.Name: true}
+		queue := []string{node.Name}
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			for _, child := range g.ChildNodes(name) {
+				if !seen[child.Name] && child.Name != g.Root.Name {
+					seen[child.Name] = true
+					queue = append(queue, child.Name)
+				}
+			}
+		}
+
+		footprints = append(footprints, DependencyFootprint{
+			Module:     node.Name,
+			Version:    node.Version,
+			Transitive: len(seen) - 1,
+		})
+	}
+
+	sort.SliceStable(footprints, func(i, j int) bool {
+		return footprints[i].Transitive > footprints[j].Transitive
+	})
+	return footprints
+}
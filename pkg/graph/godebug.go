@@ -0,0 +1,41 @@
+package graph
+
+// GodebugSetting is a godebug directive of the main module's go.mod, which
+// changes runtime defaults of the binaries built from it. Insecure explains
+// how the setting relaxes a security-relevant default, if it does.
+type GodebugSetting struct {
+	Key           string `json:"key" yaml:"key"`
+	Value         string `json:"value" yaml:"value"`
+	DefinedAtLine int    `json:"defined_at_line,omitempty" yaml:"defined_at_line,omitempty"`
+	Insecure      string `json:"insecure,omitempty" yaml:"insecure,omitempty"`
+}
+
+// insecureGodebugs are the key=value settings that restore behavior the Go
+// team removed or disabled by default for security reasons.
+var insecureGodebugs = map[string]string{
+	"x509sha1=1":             "accepts certificates signed with SHA-1",
+	"x509negativeserial=1":   "accepts certificates with negative serial numbers",
+	"x509ignoreCN=0":         "matches host names against the certificate Common Name",
+	"rsa1024min=0":           "allows RSA keys shorter than 1024 bits",
+	"tlsrsakex=1":            "enables TLS RSA key exchange cipher suites without forward secrecy",
+	"tls10server=1":          "lets TLS servers accept TLS 1.0 and 1.1 by default",
+	"tls3des=1":              "enables 3DES TLS cipher suites",
+	"tlsunsafeekm=1":         "allows exporting keying material without extended master secret",
+	"tlsmlkem=0":             "disables post-quantum ML-KEM key exchange",
+	"tlskyber=0":             "disables post-quantum Kyber key exchange",
+	"zipinsecurepath=1":      "allows archive entries with absolute or parent-relative paths in archive/zip",
+	"tarinsecurepath=1":      "allows archive entries with absolute or parent-relative paths in archive/tar",
+	"httplaxcontentlength=1": "accepts empty Content-Length headers in net/http",
+}
+
+// InsecureGodebugs returns the godebug settings that relax a
+// security-relevant default.
+func (g *DependencyGraph) InsecureGodebugs() []GodebugSetting {
+	var settings []GodebugSetting
+	for _, setting := range g.GodebugSettings {
+		if setting.Insecure != "" {
+			settings = append(settings, setting)
+		}
+	}
+	return settings
+}
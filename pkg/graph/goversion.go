@@ -0,0 +1,119 @@
+package graph
+
+import (
+	"context"
+	"go/version"
+	"sort"
+	"sync"
+
+	"golang.org/x/mod/modfile"
+)
+
+// LoadGoVersions reads the go directive of every dependency's go.mod from
+// source into GoVersion. Modules replaced by another module version use the
+// replacement's go.mod; locally replaced modules are skipped. It returns the
+// modules whose go.mod could not be read, sorted.
+func (g *EnhancedDependencyGraph) LoadGoVersions(ctx context.Context, source RequirementSource) []string {
+	replacements := make(map[string]Replacement)
+	for _, replacement := range g.Replacements {
+		replacements[replacement.Old.Path] = replacement
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failures []string
+	)
+	sem := make(chan struct{}, requirementFetchWorkers)
+
+	for name, node := range g.EnhancedNodes {
+		if name == g.Root.Name {
+			continue
+		}
+		path, modVersion := node.Name, node.Version
+		if replacement, ok := replacements[name]; ok && (replacement.Old.Version == "" || replacement.Old.Version == node.Version) {
+			if replacement.IsLocal() {
+				continue
+			}
+			path, modVersion = replacement.New.Path, replacement.New.Version
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(node *EnhancedNode, path, modVersion string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			goVersion, err := fetchGoVersion(ctx, source, path, modVersion)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures = append(failures, node.Name)
+				return
+			}
+			node.GoVersion = goVersion
+		}(node, path, modVersion)
+	}
+	wg.Wait()
+
+	sort.Strings(failures)
+	return failures
+}
+
+func fetchGoVersion(ctx context.Context, source RequirementSource, path, modVersion string) (string, error) {
+	data, err := source.GoMod(ctx, path, modVersion)
+	if err != nil {
+		return "", err
+	}
+	modFile, err := modfile.ParseLax(path+"@"+modVersion+"/go.mod", data, nil)
+	if err != nil {
+		return "", err
+	}
+	if modFile.Go == nil {
+		return "", nil
+	}
+	return modFile.Go.Version, nil
+}
+
+// GoVersionNodes returns the dependencies with a known go directive, the
+// highest requirement first.
+func (g *EnhancedDependencyGraph) GoVersionNodes() []*EnhancedNode {
+	var nodes []*EnhancedNode
+	for name, node := range g.EnhancedNodes {
+		if name != g.Root.Name && node.GoVersion != "" {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if c := CompareGoVersions(nodes[i].GoVersion, nodes[j].GoVersion); c != 0 {
+			return c > 0
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+	return nodes
+}
+
+// CompareGoVersions compares go directive versions such as "1.21" and
+// "1.22.3", like strings.Compare.
+func CompareGoVersions(a, b string) int {
+	return version.Compare("go"+a, "go"+b)
+}
+
+// EffectiveGoVersion returns the Go version building the main module
+// requires: the highest of its own go directive and those of the
+// dependencies loaded by LoadGoVersions.
+func (g *EnhancedDependencyGraph) EffectiveGoVersion() string {
+	effective := g.ModuleGoVersion
+	for _, node := range g.GoVersionNodes() {
+		if node.NeedsNewerGo(effective) {
+			effective = node.GoVersion
+		}
+	}
+	return effective
+}
+
+// NeedsNewerGo reports whether the module's go directive is higher than
+// goVersion, or goVersion is unset.
+func (n *EnhancedNode) NeedsNewerGo(goVersion string) bool {
+	return n.GoVersion != "" && (goVersion == "" || CompareGoVersions(n.GoVersion, goVersion) > 0)
+}
@@ -0,0 +1,110 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GroupByKeys are the groupings accepted by GroupDependencies.
+var GroupByKeys = []string{"license", "org", "severity"}
+
+type DependencyGroup struct {
+	Key     string   `json:"key" yaml:"key"`
+	Count   int      `json:"count" yaml:"count"`
+	Modules []string `json:"modules" yaml:"modules"`
+}
+
+// OrgPrefix returns the host and first path element of a module path,
+// e.g. "github.com/spf13" for "github.com/spf13/cobra".
+func OrgPrefix(modulePath string) string {
+	parts := strings.Split(modulePath, "/")
+	if len(parts) > 1 {
+		return strings.Join(parts[:2], "/")
+	}
+	return parts[0]
+}
+
+// GroupDependencies partitions the dependencies (excluding the main module)
+// by license, by organization prefix or by their most severe security
+// issue. License and org groups are sorted by key, severity groups from
+// most to least severe with unaffected modules under "NONE".
+func (g *EnhancedDependencyGraph) GroupDependencies(by string) ([]DependencyGroup, error) {
+	var keyFunc func(*EnhancedNode) string
+	switch by {
+	case "license":
+		keyFunc = func(node *EnhancedNode) string {
+			if node.License == "" {
+				return "Unknown"
+			}
+			return node.License
+		}
+	case "org":
+		keyFunc = func(node *EnhancedNode) string {
+			return OrgPrefix(node.Name)
+		}
+	case "severity":
+		keyFunc = func(node *EnhancedNode) string {
+			if severity := MaxSeverity(node.SecurityIssues); severity != "" {
+				return severity
+			}
+			return "NONE"
+		}
+	default:
+		return nil, fmt.Errorf("unsupported grouping: %s (supported: %s)", by, strings.Join(GroupByKeys, ", "))
+	}
+
+	index := make(map[string]int)
+	var groups []DependencyGroup
+	for _, node := range g.SortedNodes("name") {
+		key := keyFunc(node)
+		i, exists := index[key]
+		if !exists {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, DependencyGroup{Key: key})
+		}
+		groups[i].Modules = append(groups[i].Modules, node.Name)
+		groups[i].Count++
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if by == "severity" {
+			return SeverityRank(groups[i].Key) > SeverityRank(groups[j].Key)
+		}
+		return groups[i].Key < groups[j].Key
+	})
+
+	return groups, nil
+}
+
+// OrgLicenses is the license mix of the dependencies under one
+// organization prefix (see OrgPrefix).
+type OrgLicenses struct {
+	Org      string         `json:"org" yaml:"org"`
+	Modules  int            `json:"modules" yaml:"modules"`
+	Licenses map[string]int `json:"licenses" yaml:"licenses"`
+	Mixed    bool           `json:"mixed,omitempty" yaml:"mixed,omitempty"`
+}
+
+// LicensesByOrg pivots the dependency licenses by organization prefix,
+// sorted by prefix, so an org that introduces a mix of licenses stands out.
+// Mixed is set when an org's modules use more than one license.
+func (g *EnhancedDependencyGraph) LicensesByOrg() []OrgLicenses {
+	groups, _ := g.GroupDependencies("org")
+
+	orgs := make([]OrgLicenses, 0, len(groups))
+	for _, group := range groups {
+		org := OrgLicenses{Org: group.Key, Modules: group.Count, Licenses: make(map[string]int)}
+		for _, name := range group.Modules {
+			license := g.EnhancedNodes[name].License
+			if license == "" {
+				license = "Unknown"
+			}
+			org.Licenses[license]++
+		}
+		org.Mixed = len(org.Licenses) > 1
+		orgs = append(orgs, org)
+	}
+	return orgs
+}
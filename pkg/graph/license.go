@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// LicenseTextUnavailable is stored as the license text of modules whose
+// license file could not be read, so outputs state that it is missing.
+const LicenseTextUnavailable = "NOASSERTION: license file not found in the module cache"
+
+// licenseFileNames are tried in order in the module root.
+var licenseFileNames = []string{
+	"LICENSE", "LICENSE.txt", "LICENSE.md",
+	"LICENCE", "LICENCE.txt", "LICENCE.md",
+	"COPYING", "COPYING.txt", "COPYING.md",
+	"license", "license.txt", "license.md",
+}
+
+// LoadLicenseTexts reads the license file of every dependency extracted in
+// the module cache at modCacheDir and returns how many were found. Missing
+// ones get LicenseTextUnavailable.
+func (g *EnhancedDependencyGraph) LoadLicenseTexts(modCacheDir string) int {
+	found := 0
+	for name, node := range g.EnhancedNodes {
+		if name == g.Root.Name {
+			continue
+		}
+
+		node.LicenseText = LicenseTextUnavailable
+		if text, ok := readLicenseText(modCacheDir, node.Name, node.Version); ok {
+			node.LicenseText = text
+			found++
+		}
+	}
+	return found
+}
+
+func readLicenseText(modCacheDir, modulePath, version string) (string, bool) {
+	dir, ok := moduleSourceDir(modCacheDir, modulePath, version)
+	if !ok {
+		return "", false
+	}
+
+	for _, name := range licenseFileNames {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			return string(data), true
+		}
+	}
+	return "", false
+}
+
+// moduleSourceDir returns where the module cache at modCacheDir extracts
+// modulePath at version. The directory need not exist.
+func moduleSourceDir(modCacheDir, modulePath, version string) (string, bool) {
+	if modCacheDir == "" || version == "" {
+		return "", false
+	}
+
+	escapedPath, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", false
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return "", false
+	}
+
+	return filepath.Join(modCacheDir, filepath.FromSlash(escapedPath)+"@"+escapedVersion), true
+}
+
+// copyrightLine matches copyright statements, which name a year or the (c)
+// sign, as opposed to license prose mentioning "copyright notice".
+var copyrightLine = regexp.MustCompile(`(?i)^(copyright|©).*(\b(19|20)\d{2}\b|\(c\)|©)`)
+
+// CopyrightNotices returns the copyright lines of a license text, such as
+// "Copyright (c) 2015 The Go Authors. All rights reserved.", skipping the
+// placeholders of license templates like "Copyright [yyyy]" or "<year>".
+func CopyrightNotices(text string) []string {
+	var notices []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#/*"))
+		if !copyrightLine.MatchString(line) || strings.ContainsAny(line, "[{") || strings.Contains(strings.ToLower(line), "<year>") {
+			continue
+		}
+		if !seen[line] {
+			seen[line] = true
+			notices = append(notices, line)
+		}
+	}
+	return notices
+}
@@ -0,0 +1,179 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseLicenseExpression parses an SPDX license expression such as
+// "MIT OR (Apache-2.0 AND BSD-3-Clause)" into its alternatives: the module
+// may be used under any one alternative, which requires complying with all
+// of its licenses. "X WITH exception" is kept as a single license.
+func ParseLicenseExpression(expr string) ([][]string, error) {
+	tokens := tokenizeLicenseExpression(expr)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty license expression")
+	}
+
+	p := &licenseExprParser{tokens: tokens}
+	options, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid license expression %q: %w", expr, err)
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("invalid license expression %q: unexpected %q", expr, p.tokens[p.pos])
+	}
+	return options, nil
+}
+
+// LicenseIDs returns every license mentioned in an expression, in order of
+// appearance. Unparseable expressions are returned as a single license.
+func LicenseIDs(expr string) []string {
+	options, err := ParseLicenseExpression(expr)
+	if err != nil {
+		return []string{expr}
+	}
+
+	seen := make(map[string]bool)
+	var ids []string
+	for _, option := range options {
+		for _, id := range option {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// PreferredLicenses returns the licenses of the most permissive alternative
+// of an expression, i.e. the terms a consumer would normally choose.
+func PreferredLicenses(expr string) []string {
+	options, err := ParseLicenseExpression(expr)
+	if err != nil {
+		return []string{expr}
+	}
+
+	best, bestRank := options[0], -1
+	for _, option := range options {
+		rank := 0
+		for _, id := range option {
+			rank = max(rank, licenseRestrictiveness(id))
+		}
+		if bestRank == -1 || rank < bestRank {
+			best, bestRank = option, rank
+		}
+	}
+	return best
+}
+
+// licenseRestrictiveness ranks licenses from permissive (0) through weak
+// copyleft, strong copyleft and network copyleft to unknown (4).
+func licenseRestrictiveness(id string) int {
+	base, _, _ := strings.Cut(id, " WITH ")
+	switch {
+	case base == "Unknown" || base == "":
+		return 4
+	case strings.HasPrefix(base, "AGPL"):
+		return 3
+	case strings.HasPrefix(base, "GPL"):
+		return 2
+	case strings.HasPrefix(base, "LGPL"), strings.HasPrefix(base, "MPL"),
+		strings.HasPrefix(base, "EPL"), strings.HasPrefix(base, "CDDL"):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func tokenizeLicenseExpression(expr string) []string {
+	expr = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expr)
+	return strings.Fields(expr)
+}
+
+type licenseExprParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *licenseExprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *licenseExprParser) parseOr() ([][]string, error) {
+	options, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "OR") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, right...)
+	}
+	return options, nil
+}
+
+func (p *licenseExprParser) parseAnd() ([][]string, error) {
+	options, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "AND") {
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+
+		var combined [][]string
+		for _, left := range options {
+			for _, r := range right {
+				option := append(append([]string{}, left...), r...)
+				combined = append(combined, option)
+			}
+		}
+		options = combined
+	}
+	return options, nil
+}
+
+func (p *licenseExprParser) parseTerm() ([][]string, error) {
+	token := p.peek()
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case token == "(":
+		p.pos++
+		options, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return options, nil
+	case token == ")" || strings.EqualFold(token, "OR") || strings.EqualFold(token, "AND") || strings.EqualFold(token, "WITH"):
+		return nil, fmt.Errorf("unexpected %q", token)
+	}
+
+	p.pos++
+	id := token
+	if strings.EqualFold(p.peek(), "WITH") {
+		p.pos++
+		exception := p.peek()
+		if exception == "" || exception == "(" || exception == ")" {
+			return nil, fmt.Errorf("missing exception after WITH")
+		}
+		p.pos++
+		id += " WITH " + exception
+	}
+	return [][]string{{id}}, nil
+}
@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"sort"
+)
+
+type ModuleSummary struct {
+	Name           string `json:"name" yaml:"name"`
+	Total          int    `json:"total_dependencies" yaml:"total_dependencies"`
+	Direct         int    `json:"direct_dependencies" yaml:"direct_dependencies"`
+	Indirect       int    `json:"indirect_dependencies" yaml:"indirect_dependencies"`
+	SecurityIssues int    `json:"security_issues" yaml:"security_issues"`
+	Conflicts      int    `json:"version_conflicts" yaml:"version_conflicts"`
+}
+
+type MultiModuleSummary struct {
+	Modules            []ModuleSummary `json:"modules" yaml:"modules"`
+	TotalDependencies  int             `json:"total_dependencies" yaml:"total_dependencies"`
+	UniqueDependencies int             `json:"unique_dependencies" yaml:"unique_dependencies"`
+	SecurityIssues     int             `json:"security_issues" yaml:"security_issues"`
+}
+
+type ModuleUsage struct {
+	Module  string `json:"module" yaml:"module"`
+	Version string `json:"version" yaml:"version"`
+}
+
+// CrossModuleConflict is a dependency required at different versions by
+// separately analyzed modules.
+type CrossModuleConflict struct {
+	ModulePath string        `json:"module_path" yaml:"module_path"`
+	Usages     []ModuleUsage `json:"usages" yaml:"usages"`
+}
+
+func SummarizeModules(graphs []*EnhancedDependencyGraph) MultiModuleSummary {
+	var summary MultiModuleSummary
+	unique := make(map[string]bool)
+
+	for _, g := range graphs {
+		direct, indirect := g.GetDependencyCount()
+		summary.Modules = append(summary.Modules, ModuleSummary{
+			Name:           g.ModuleName,
+			Total:          len(g.AllNodes) - 1,
+			Direct:         direct,
+			Indirect:       indirect,
+			SecurityIssues: len(g.SecurityIssues),
+			Conflicts:      len(g.Conflicts),
+		})
+
+		summary.TotalDependencies += len(g.AllNodes) - 1
+		summary.SecurityIssues += len(g.SecurityIssues)
+		for name := range g.AllNodes {
+			if name != g.Root.Name {
+				unique[name] = true
+			}
+		}
+	}
+	summary.UniqueDependencies = len(unique)
+
+	return summary
+}
+
+func CrossModuleConflicts(graphs []*EnhancedDependencyGraph) []CrossModuleConflict {
+	usages := make(map[string][]ModuleUsage)
+	for _, g := range graphs {
+		for name, node := range g.AllNodes {
+			if name == g.Root.Name {
+				continue
+			}
+			usages[name] = append(usages[name], ModuleUsage{Module: g.ModuleName, Version: node.Version})
+		}
+	}
+
+	var conflicts []CrossModuleConflict
+	for modulePath, moduleUsages := range usages {
+		versions := make(map[string]bool)
+		for _, usage := range moduleUsages {
+			versions[usage.Version] = true
+		}
+		if len(versions) < 2 {
+			continue
+		}
+
+		sort.Slice(moduleUsages, func(i, j int) bool {
+			return moduleUsages[i].Module < moduleUsages[j].Module
+		})
+		conflicts = append(conflicts, CrossModuleConflict{ModulePath: modulePath, Usages: moduleUsages})
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].ModulePath < conflicts[j].ModulePath
+	})
+
+	return conflicts
+}
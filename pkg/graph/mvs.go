@@ -0,0 +1,316 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"go/version"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// RequirementSource supplies go.mod files of module versions, e.g. from the
+// module cache or a module proxy.
+type RequirementSource interface {
+	GoMod(ctx context.Context, modulePath, version string) ([]byte, error)
+}
+
+// VersionLister lists the known versions of a module, sorted by semver. A
+// RequirementSource that also implements it lets LoadRequirementGraph move
+// requirements on excluded versions to a higher version.
+type VersionLister interface {
+	List(ctx context.Context, modulePath string) ([]string, error)
+}
+
+const requirementFetchWorkers = 8
+
+// LoadRequirementGraph walks the require directives of reachable module
+// versions, starting at the main module, and records them in Requirements.
+// As in minimal version selection, the main module's replace directives
+// apply: the requirements of a replaced version are read from the go.mod of
+// its replacement, found in ModuleDir for local replacements, and recorded
+// under the replaced version. As the go command does, a requirement on a
+// version excluded by the main module is moved to the next higher version
+// that is not excluded, taken from the versions listed by source if it is a
+// VersionLister; requirements that cannot be moved are dropped, listed in
+// UnresolvedModules and recorded as warnings. Module graph pruning (go
+// 1.17+) is honoured: requirements of a pruned dependency are recorded but
+// not expanded further unless they are also reached through an unpruned
+// module. Versions whose go.mod cannot be fetched are listed in
+// UnresolvedModules, recorded as warnings and treated as having no
+// requirements.
+func (g *EnhancedDependencyGraph) LoadRequirementGraph(ctx context.Context, source RequirementSource) error {
+	g.Requirements = make(map[module.Version][]module.Version)
+	g.ExcludedUpgrades = make(map[module.Version]module.Version)
+	g.UnresolvedModules = nil
+
+	pruneAll := isPrunedGoVersion(g.ModuleGoVersion)
+	excluded := g.excludedVersions()
+
+	var mu sync.Mutex
+	tried := make(map[module.Version]bool)
+	// skipExcluded moves requirements on excluded versions to the next
+	// higher version and drops those that cannot be moved. It must be
+	// called with mu held; excluded requirements are rare, so listing
+	// versions under the lock costs little.
+	skipExcluded := func(reqs []module.Version) []module.Version {
+		kept := make([]module.Version, 0, len(reqs))
+		for _, req := range reqs {
+			if !excluded[req] {
+				kept = append(kept, req)
+				continue
+			}
+			if !tried[req] {
+				tried[req] = true
+				upgrade, err := nextAllowedVersion(ctx, source, req, excluded)
+				if err != nil {
+					g.UnresolvedModules = append(g.UnresolvedModules, req.Path+"@"+req.Version)
+					g.AddWarning(WarningUnresolvedModule, req.Path, "could not replace excluded %s@%s with a higher version: %v", req.Path, req.Version, err)
+				} else {
+					g.ExcludedUpgrades[req] = upgrade
+				}
+			}
+			if upgrade, ok := g.ExcludedUpgrades[req]; ok {
+				kept = append(kept, upgrade)
+			}
+		}
+		return kept
+	}
+
+	expanded := make(map[module.Version]bool)
+	var frontier []module.Version
+	mu.Lock()
+	for _, req := range skipExcluded(g.RootRequirements) {
+		if !expanded[req] {
+			expanded[req] = true
+			frontier = append(frontier, req)
+		}
+	}
+	mu.Unlock()
+
+	for len(frontier) > 0 {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("requirement graph loading cancelled: %w", err)
+		}
+
+		var next []module.Version
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, requirementFetchWorkers)
+
+		for _, mv := range frontier {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(mv module.Version) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				reqs, goVersion, err := g.fetchRequirements(ctx, source, mv)
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					g.UnresolvedModules = append(g.UnresolvedModules, mv.Path+"@"+mv.Version)
+					g.AddWarning(WarningUnresolvedModule, mv.Path, "could not load go.mod of %s@%s: %v", mv.Path, mv.Version, err)
+					return
+				}
+				reqs = skipExcluded(reqs)
+				g.Requirements[mv] = reqs
+				if pruneAll && isPrunedGoVersion(goVersion) {
+					return
+				}
+				for _, req := range reqs {
+					if !expanded[req] {
+						expanded[req] = true
+						next = append(next, req)
+					}
+				}
+			}(mv)
+		}
+		wg.Wait()
+
+		frontier = next
+	}
+
+	sort.Strings(g.UnresolvedModules)
+	return nil
+}
+
+// fetchRequirements returns the requirements and go version of mv, from
+// the go.mod of its replacement when the main module replaces it.
+func (g *EnhancedDependencyGraph) fetchRequirements(ctx context.Context, source RequirementSource, mv module.Version) ([]module.Version, string, error) {
+	var data []byte
+	var err error
+	name := mv.Path + "@" + mv.Version + "/go.mod"
+	switch replacement, ok := g.replacementOf(mv); {
+	case ok && replacement.IsLocal():
+		dir := replacement.New.Path
+		if !filepath.IsAbs(dir) {
+			if g.ModuleDir == "" {
+				return nil, "", fmt.Errorf("local replacement %s is relative to the main module, whose directory is unknown", dir)
+			}
+			dir = filepath.Join(g.ModuleDir, dir)
+		}
+		name = filepath.Join(dir, "go.mod")
+		data, err = os.ReadFile(name)
+	case ok:
+		data, err = source.GoMod(ctx, replacement.New.Path, replacement.New.Version)
+	default:
+		data, err = source.GoMod(ctx, mv.Path, mv.Version)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	modFile, err := modfile.ParseLax(name, data, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var goVersion string
+	if modFile.Go != nil {
+		goVersion = modFile.Go.Version
+	}
+
+	reqs := make([]module.Version, 0, len(modFile.Require))
+	for _, require := range modFile.Require {
+		reqs = append(reqs, require.Mod)
+	}
+	return reqs, goVersion, nil
+}
+
+// nextAllowedVersion returns the lowest tagged version of mv's module above
+// mv.Version that is not excluded, as listed by source.
+func nextAllowedVersion(ctx context.Context, source RequirementSource, mv module.Version, excluded map[module.Version]bool) (module.Version, error) {
+	lister, ok := source.(VersionLister)
+	if !ok {
+		return module.Version{}, fmt.Errorf("the versions of %s cannot be listed", mv.Path)
+	}
+	versions, err := lister.List(ctx, mv.Path)
+	if err != nil {
+		return module.Version{}, err
+	}
+	for _, v := range versions {
+		next := module.Version{Path: mv.Path, Version: v}
+		if !module.IsPseudoVersion(v) && semver.Compare(v, mv.Version) > 0 && !excluded[next] {
+			return next, nil
+		}
+	}
+	return module.Version{}, fmt.Errorf("no higher version of %s is available", mv.Path)
+}
+
+func (g *EnhancedDependencyGraph) excludedVersions() map[module.Version]bool {
+	excluded := make(map[module.Version]bool, len(g.Excludes))
+	for _, mv := range g.Excludes {
+		excluded[mv] = true
+	}
+	return excluded
+}
+
+// isPrunedGoVersion reports whether a go directive enables module graph
+// pruning, which was introduced in Go 1.17.
+func isPrunedGoVersion(goVersion string) bool {
+	return goVersion != "" && version.Compare("go"+goVersion, "go1.17") >= 0
+}
+
+// ReachableVersions returns every module version in the loaded requirement
+// graph, expanded or not, together with the targets of the main module's
+// replace directives, which stand in for the versions they replace. These
+// are the versions whose checksums go.sum may need.
+func (g *EnhancedDependencyGraph) ReachableVersions() map[module.Version]bool {
+	reachable := make(map[module.Version]bool)
+	for _, req := range g.RootRequirements {
+		reachable[req] = true
+	}
+	for mv, reqs := range g.Requirements {
+		reachable[mv] = true
+		for _, req := range reqs {
+			reachable[req] = true
+		}
+	}
+	for _, replacement := range g.Replacements {
+		if !replacement.IsLocal() {
+			reachable[replacement.New] = true
+		}
+	}
+	return reachable
+}
+
+// SelectedVersions applies minimal version selection: every module version
+// reachable from the main module is visited and the highest required
+// version of each module path wins. Versions excluded by the main module
+// are never selected; requirements on them count as requirements on their
+// ExcludedUpgrades, if any. Without a loaded requirement graph only the main
+// module's go.mod is considered.
+func (g *EnhancedDependencyGraph) SelectedVersions() map[string]string {
+	selected := make(map[string]string)
+	visited := make(map[module.Version]bool)
+	excluded := g.excludedVersions()
+
+	queue := append([]module.Version(nil), g.RootRequirements...)
+	for len(queue) > 0 {
+		mv := queue[0]
+		queue = queue[1:]
+		if excluded[mv] {
+			upgrade, ok := g.ExcludedUpgrades[mv]
+			if !ok {
+				continue
+			}
+			mv = upgrade
+		}
+		if visited[mv] {
+			continue
+		}
+		visited[mv] = true
+
+		if current, exists := selected[mv.Path]; !exists || semver.Compare(mv.Version, current) > 0 {
+			selected[mv.Path] = mv.Version
+		}
+
+		queue = append(queue, g.Requirements[mv]...)
+	}
+
+	return selected
+}
+
+// ChildNodes returns the modules required by the named module, sorted by
+// path. Edges beyond the main module are only known once the requirement
+// graph is loaded; until then the main module lists every dependency, direct
+// ones first, and other modules have no children.
+func (g *EnhancedDependencyGraph) ChildNodes(name string) []*EnhancedNode {
+	node, exists := g.EnhancedNodes[name]
+	if !exists {
+		return nil
+	}
+
+	if g.Requirements == nil {
+		if name != g.Root.Name {
+			return nil
+		}
+		children := g.SortedNodes("name")
+		sort.SliceStable(children, func(i, j int) bool {
+			return children[i].Direct && !children[j].Direct
+		})
+		return children
+	}
+
+	reqs := g.RootRequirements
+	if name != g.Root.Name {
+		reqs = g.Requirements[module.Version{Path: name, Version: node.Version}]
+	}
+
+	seen := make(map[string]bool)
+	var children []*EnhancedNode
+	for _, req := range reqs {
+		if child, exists := g.EnhancedNodes[req.Path]; exists && !seen[req.Path] {
+			seen[req.Path] = true
+			children = append(children, child)
+		}
+	}
+	SortNodes(children, "name")
+	return children
+}
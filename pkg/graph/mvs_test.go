@@ -0,0 +1,175 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"goviz/pkg/parser"
+
+	"golang.org/x/mod/module"
+)
+
+// fakeSource serves go.mod files and version lists from memory and records
+// what was fetched.
+type fakeSource struct {
+	mu       sync.Mutex
+	files    map[string]string
+	versions map[string][]string
+	fetched  []string
+}
+
+func (s *fakeSource) GoMod(ctx context.Context, modulePath, version string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetched = append(s.fetched, modulePath+"@"+version)
+	data, ok := s.files[modulePath+"@"+version]
+	if !ok {
+		return nil, fmt.Errorf("%s@%s: not found", modulePath, version)
+	}
+	return []byte(data), nil
+}
+
+func (s *fakeSource) List(ctx context.Context, modulePath string) ([]string, error) {
+	versions, ok := s.versions[modulePath]
+	if !ok {
+		return nil, fmt.Errorf("%s: not found", modulePath)
+	}
+	return versions, nil
+}
+
+func TestLoadRequirementGraphReplaceExclude(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "local", "b"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	localGoMod := "module example.com/b\n\nrequire example.com/c v1.1.0\n"
+	if err := os.WriteFile(filepath.Join(dir, "local", "b", "go.mod"), []byte(localGoMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	source := &fakeSource{files: map[string]string{
+		"example.com/a@v1.0.0":      "module example.com/a\n\nrequire example.com/e v1.0.0\n",
+		"example.com/fork/a@v1.1.0": "module example.com/a\n\nrequire (\n\texample.com/c v1.2.0\n\texample.com/d v1.0.0\n)\n",
+		"example.com/b@v1.0.0":      "module example.com/b\n\nrequire example.com/e v1.0.0\n",
+		"example.com/c@v1.1.0":      "module example.com/c\n",
+		"example.com/c@v1.2.0":      "module example.com/c\n",
+		"example.com/c@v1.3.0":      "module example.com/c\n",
+		"example.com/d@v1.0.0":      "module example.com/d\n",
+		"example.com/e@v1.0.0":      "module example.com/e\n",
+	}, versions: map[string][]string{
+		"example.com/c": {"v1.1.0", "v1.2.0", "v1.2.1-0.20200101000000-abcdef123456", "v1.3.0"},
+	}}
+
+	modFile, err := parser.ParseGoModReader(strings.NewReader(`module example.com/app
+
+go 1.21
+
+require (
+	example.com/a v1.0.0
+	example.com/b v1.0.0
+)
+
+replace example.com/a => example.com/other/a v1.0.5
+
+replace example.com/a v1.0.0 => example.com/fork/a v1.1.0
+
+replace example.com/b => ./local/b
+
+exclude example.com/c v1.2.0
+`), "go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	depGraph, err := BuildEnhancedDependencyGraph(modFile, filepath.Join(dir, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	depGraph.ModuleDir = dir
+
+	if err := depGraph.LoadRequirementGraph(context.Background(), source); err != nil {
+		t.Fatal(err)
+	}
+	if len(depGraph.UnresolvedModules) > 0 {
+		t.Fatalf("unresolved modules: %v", depGraph.UnresolvedModules)
+	}
+
+	a := module.Version{Path: "example.com/a", Version: "v1.0.0"}
+	if got, want := depGraph.Requirements[a], []module.Version{{Path: "example.com/c", Version: "v1.3.0"}, {Path: "example.com/d", Version: "v1.0.0"}}; !slices.Equal(got, want) {
+		t.Errorf("requirements of %v = %v, want %v from the replacement with the excluded version moved up", a, got, want)
+	}
+	b := module.Version{Path: "example.com/b", Version: "v1.0.0"}
+	if got, want := depGraph.Requirements[b], []module.Version{{Path: "example.com/c", Version: "v1.1.0"}}; !slices.Equal(got, want) {
+		t.Errorf("requirements of %v = %v, want %v from the local replacement", b, got, want)
+	}
+	for _, fetched := range source.fetched {
+		switch fetched {
+		case "example.com/a@v1.0.0", "example.com/b@v1.0.0", "example.com/other/a@v1.0.5", "example.com/c@v1.2.0":
+			t.Errorf("fetched go.mod of %s, which is replaced or excluded", fetched)
+		}
+	}
+
+	selected := depGraph.SelectedVersions()
+	want := map[string]string{
+		"example.com/a": "v1.0.0",
+		"example.com/b": "v1.0.0",
+		"example.com/c": "v1.3.0",
+		"example.com/d": "v1.0.0",
+	}
+	if len(selected) != len(want) {
+		t.Errorf("SelectedVersions() = %v, want %v", selected, want)
+	}
+	for path, version := range want {
+		if selected[path] != version {
+			t.Errorf("SelectedVersions()[%s] = %q, want %q", path, selected[path], version)
+		}
+	}
+}
+
+func TestLoadRequirementGraphExcludedWithoutHigherVersion(t *testing.T) {
+	source := &fakeSource{files: map[string]string{
+		"example.com/a@v1.1.0": "module example.com/a\n\nrequire example.com/b v1.0.0\n",
+		"example.com/b@v1.0.0": "module example.com/b\n",
+	}, versions: map[string][]string{
+		"example.com/a": {"v1.0.0", "v1.1.0"},
+		"example.com/b": {"v1.0.0"},
+	}}
+
+	modFile, err := parser.ParseGoModReader(strings.NewReader(`module example.com/app
+
+go 1.21
+
+require example.com/a v1.0.0
+
+exclude (
+	example.com/a v1.0.0
+	example.com/b v1.0.0
+)
+`), "go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	depGraph, err := BuildEnhancedDependencyGraph(modFile, filepath.Join(t.TempDir(), "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := depGraph.LoadRequirementGraph(context.Background(), source); err != nil {
+		t.Fatal(err)
+	}
+	a := module.Version{Path: "example.com/a", Version: "v1.1.0"}
+	if got := depGraph.Requirements[a]; len(got) != 0 {
+		t.Errorf("requirements of %v = %v, want none: the only version of example.com/b is excluded", a, got)
+	}
+	if want := []string{"example.com/b@v1.0.0"}; !slices.Equal(depGraph.UnresolvedModules, want) {
+		t.Errorf("UnresolvedModules = %v, want %v", depGraph.UnresolvedModules, want)
+	}
+	if selected := depGraph.SelectedVersions(); len(selected) != 1 || selected["example.com/a"] != "v1.1.0" {
+		t.Errorf("SelectedVersions() = %v, want example.com/a moved up to v1.1.0 only", selected)
+	}
+}
@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+)
+
+// LicenseObligations are the main conditions a license attaches to
+// distributing software that includes the licensed code.
+type LicenseObligations struct {
+	// Attribution requires reproducing the copyright and license notices.
+	Attribution bool `json:"attribution" yaml:"attribution"`
+	// SourceDisclosure requires offering the source of the licensed code,
+	// and for strong copyleft of the combined work, to recipients.
+	SourceDisclosure bool `json:"source_disclosure" yaml:"source_disclosure"`
+	// NetworkDisclosure extends source disclosure to users interacting
+	// with the software over a network.
+	NetworkDisclosure bool `json:"network_disclosure" yaml:"network_disclosure"`
+	// PatentGrant is set when the license grants an express patent license.
+	PatentGrant bool `json:"patent_grant" yaml:"patent_grant"`
+}
+
+// licenseObligations is keyed by SPDX ID, without -only/-or-later suffixes.
+var licenseObligations = map[string]LicenseObligations{
+	"0BSD":         {},
+	"CC0-1.0":      {},
+	"Unlicense":    {},
+	"MIT":          {Attribution: true},
+	"MIT-0":        {},
+	"ISC":          {Attribution: true},
+	"BSD-2-Clause": {Attribution: true},
+	"BSD-3-Clause": {Attribution: true},
+	"Zlib":         {Attribution: true},
+	"Apache-2.0":   {Attribution: true, PatentGrant: true},
+	"MPL-2.0":      {Attribution: true, SourceDisclosure: true, PatentGrant: true},
+	"EPL-2.0":      {Attribution: true, SourceDisclosure: true, PatentGrant: true},
+	"CDDL-1.0":     {Attribution: true, SourceDisclosure: true, PatentGrant: true},
+	"LGPL-2.1":     {Attribution: true, SourceDisclosure: true},
+	"LGPL-3.0":     {Attribution: true, SourceDisclosure: true, PatentGrant: true},
+	"GPL-2.0":      {Attribution: true, SourceDisclosure: true},
+	"GPL-3.0":      {Attribution: true, SourceDisclosure: true, PatentGrant: true},
+	"AGPL-3.0":     {Attribution: true, SourceDisclosure: true, NetworkDisclosure: true, PatentGrant: true},
+}
+
+// ObligationsFor returns the obligations of an SPDX license ID. Exceptions
+// ("X WITH exception") are judged by their base license. It reports false
+// for unknown licenses.
+func ObligationsFor(id string) (LicenseObligations, bool) {
+	base, _, _ := strings.Cut(id, " WITH ")
+	base = strings.TrimSuffix(base, "+")
+	base = strings.TrimSuffix(base, "-only")
+	base = strings.TrimSuffix(base, "-or-later")
+	obligations, ok := licenseObligations[base]
+	return obligations, ok
+}
+
+// Names lists the obligations that apply, e.g. for reports.
+func (o LicenseObligations) Names() []string {
+	names := make([]string, 0, 4)
+	if o.Attribution {
+		names = append(names, "attribution")
+	}
+	if o.SourceDisclosure {
+		names = append(names, "source-disclosure")
+	}
+	if o.NetworkDisclosure {
+		names = append(names, "network-disclosure")
+	}
+	if o.PatentGrant {
+		names = append(names, "patent-grant")
+	}
+	return names
+}
+
+// ModuleObligations combines the obligations of the licenses a module is
+// used under: those of the most permissive alternative of its license
+// expression. It reports false if any of them is unknown.
+func ModuleObligations(license string) (LicenseObligations, bool) {
+	var combined LicenseObligations
+	for _, id := range PreferredLicenses(license) {
+		obligations, ok := ObligationsFor(id)
+		if !ok {
+			return LicenseObligations{}, false
+		}
+		combined.Attribution = combined.Attribution || obligations.Attribution
+		combined.SourceDisclosure = combined.SourceDisclosure || obligations.SourceDisclosure
+		combined.NetworkDisclosure = combined.NetworkDisclosure || obligations.NetworkDisclosure
+		combined.PatentGrant = combined.PatentGrant || obligations.PatentGrant
+	}
+	return combined, true
+}
+
+// ObligationSummary counts the dependencies subject to each obligation.
+// Unclassified modules have an unknown license or one missing from the
+// obligations table, and are listed for manual review.
+type ObligationSummary struct {
+	Attribution       int      `json:"attribution" yaml:"attribution"`
+	SourceDisclosure  int      `json:"source_disclosure" yaml:"source_disclosure"`
+	NetworkDisclosure int      `json:"network_disclosure" yaml:"network_disclosure"`
+	PatentGrant       int      `json:"patent_grant" yaml:"patent_grant"`
+	Unclassified      []string `json:"unclassified,omitempty" yaml:"unclassified,omitempty"`
+}
+
+// ObligationsSummary aggregates the license obligations of every
+// dependency. It relies on the licenses set by AnalyzeLicenses.
+func (g *EnhancedDependencyGraph) ObligationsSummary() ObligationSummary {
+	var summary ObligationSummary
+	for name, node := range g.EnhancedNodes {
+		if name == g.Root.Name {
+			continue
+		}
+
+		obligations, ok := ModuleObligations(node.License)
+		if !ok {
+			summary.Unclassified = append(summary.Unclassified, name)
+			continue
+		}
+		if obligations.Attribution {
+			summary.Attribution++
+		}
+		if obligations.SourceDisclosure {
+			summary.SourceDisclosure++
+		}
+		if obligations.NetworkDisclosure {
+			summary.NetworkDisclosure++
+		}
+		if obligations.PatentGrant {
+			summary.PatentGrant++
+		}
+	}
+	sort.Strings(summary.Unclassified)
+	return summary
+}
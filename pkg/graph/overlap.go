@@ -0,0 +1,138 @@
+package graph
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+
+	"golang.org/x/mod/module"
+	"gopkg.in/yaml.v3"
+)
+
+// OverlapGroup is a set of well-known modules that do the same job, such as
+// logging libraries. Modules are module paths without a major version
+// suffix, or path.Match patterns such as "github.com/org/*".
+type OverlapGroup struct {
+	Name    string   `yaml:"name"`
+	Modules []string `yaml:"modules"`
+}
+
+// OverlapsFile is the format of an --overlaps file. The default groups are
+// used as well unless defaults is false.
+type OverlapsFile struct {
+	Defaults *bool          `yaml:"defaults,omitempty"`
+	Groups   []OverlapGroup `yaml:"overlaps"`
+}
+
+// Overlap lists the direct dependencies that match the same OverlapGroup.
+type Overlap struct {
+	Group   string   `json:"group" yaml:"group"`
+	Modules []string `json:"modules" yaml:"modules"`
+}
+
+// DefaultOverlaps are the built-in groups of alternative modules.
+var DefaultOverlaps = []OverlapGroup{
+	{Name: "logging", Modules: []string{"github.com/sirupsen/logrus", "go.uber.org/zap", "github.com/rs/zerolog", "github.com/apex/log", "github.com/inconshreveable/log15", "github.com/golang/glog", "github.com/go-kit/log"}},
+	{Name: "HTTP routing", Modules: []string{"github.com/gin-gonic/gin", "github.com/labstack/echo", "github.com/go-chi/chi", "github.com/gorilla/mux", "github.com/julienschmidt/httprouter", "github.com/gofiber/fiber"}},
+	{Name: "CLI framework", Modules: []string{"github.com/spf13/cobra", "github.com/urfave/cli", "github.com/alecthomas/kong"}},
+	{Name: "configuration", Modules: []string{"github.com/spf13/viper", "github.com/knadh/koanf", "github.com/kelseyhightower/envconfig", "github.com/caarlos0/env"}},
+	{Name: "UUID", Modules: []string{"github.com/google/uuid", "github.com/gofrs/uuid", "github.com/satori/go.uuid"}},
+	{Name: "JSON encoding", Modules: []string{"github.com/json-iterator/go", "github.com/goccy/go-json", "github.com/bytedance/sonic", "github.com/segmentio/encoding"}},
+	{Name: "YAML encoding", Modules: []string{"gopkg.in/yaml", "sigs.k8s.io/yaml", "github.com/goccy/go-yaml", "github.com/ghodss/yaml"}},
+	{Name: "test assertions", Modules: []string{"github.com/stretchr/testify", "github.com/onsi/gomega", "gotest.tools", "github.com/matryer/is"}},
+	{Name: "error wrapping", Modules: []string{"github.com/pkg/errors", "github.com/cockroachdb/errors", "github.com/go-errors/errors"}},
+	{Name: "SQL access", Modules: []string{"gorm.io/gorm", "github.com/jmoiron/sqlx", "entgo.io/ent", "github.com/uptrace/bun"}},
+}
+
+// LoadOverlaps reads an overlaps file and returns the groups to check,
+// including DefaultOverlaps unless the file disables them.
+func LoadOverlaps(filename string) ([]OverlapGroup, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overlaps file: %w", err)
+	}
+
+	var file OverlapsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse overlaps file %s: %w", filename, err)
+	}
+
+	for i, group := range file.Groups {
+		if err := group.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: overlap %d: %w", filename, i+1, err)
+		}
+	}
+
+	if file.Defaults != nil && !*file.Defaults {
+		return file.Groups, nil
+	}
+	return append(append([]OverlapGroup(nil), DefaultOverlaps...), file.Groups...), nil
+}
+
+// Validate checks that the group has a name, at least two modules and
+// well-formed patterns.
+func (o OverlapGroup) Validate() error {
+	if o.Name == "" {
+		return fmt.Errorf("missing name")
+	}
+	if len(o.Modules) < 2 {
+		return fmt.Errorf("%s: at least two modules are needed", o.Name)
+	}
+	for _, pattern := range o.Modules {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s: invalid module pattern %q: %w", o.Name, pattern, err)
+		}
+	}
+	return nil
+}
+
+// matches reports whether one of the group's modules is modulePath,
+// ignoring a major version suffix.
+func (o OverlapGroup) matches(modulePath string) bool {
+	prefix, _, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		prefix = modulePath
+	}
+	for _, pattern := range o.Modules {
+		if matched, _ := path.Match(pattern, modulePath); matched {
+			return true
+		}
+		if matched, _ := path.Match(pattern, prefix); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectOverlaps records in Overlaps the groups matched by more than one
+// direct dependency. Major versions of the same module count once, so a
+// migration from v1 to v2 is not reported.
+func (g *EnhancedDependencyGraph) DetectOverlaps(groups []OverlapGroup) {
+	g.Overlaps = nil
+
+	for _, group := range groups {
+		var modules []string
+		seen := make(map[string]bool)
+		for _, node := range g.SortedNodes("name") {
+			if !node.Direct || !group.matches(node.Name) {
+				continue
+			}
+			prefix, _, ok := module.SplitPathVersion(node.Name)
+			if !ok {
+				prefix = node.Name
+			}
+			if !seen[prefix] {
+				seen[prefix] = true
+				modules = append(modules, node.Name)
+			}
+		}
+		if len(modules) > 1 {
+			g.Overlaps = append(g.Overlaps, Overlap{Group: group.Name, Modules: modules})
+		}
+	}
+
+	sort.SliceStable(g.Overlaps, func(i, j int) bool {
+		return g.Overlaps[i].Group < g.Overlaps[j].Group
+	})
+}
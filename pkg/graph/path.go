@@ -0,0 +1,62 @@
+package graph
+
+import "fmt"
+
+// PathSet is the part of the graph that lies on a requirement path from the
+// main module to Target: the modules on any such path and the edges between
+// them, as "from" and "to" module paths.
+type PathSet struct {
+	Target string
+	Nodes  map[string]bool
+	Edges  map[[2]string]bool
+}
+
+// PathsTo returns every module and edge on a path from the main module to
+// target, following ChildNodes. Requirement edges beyond the main module
+// are only known once LoadRequirementGraph has run.
+func (g *EnhancedDependencyGraph) PathsTo(target string) (*PathSet, error) {
+	if _, exists := g.EnhancedNodes[target]; !exists || target == g.Root.Name {
+		return nil, fmt.Errorf("module %s is not a dependency of %s", target, g.ModuleName)
+	}
+
+	// Walk forward from the root, recording the reverse edges.
+	parents := make(map[string][]string)
+	reachable := map[string]bool{g.Root.Name: true}
+	queue := []string{g.Root.Name}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, child := range g.ChildNodes(name) {
+			parents[child.Name] = append(parents[child.Name], name)
+			if !reachable[child.Name] {
+				reachable[child.Name] = true
+				queue = append(queue, child.Name)
+			}
+		}
+	}
+	if !reachable[target] {
+		return nil, fmt.Errorf("no requirement path from %s to %s", g.ModuleName, target)
+	}
+
+	// Walk back from the target; every module reached this way is reachable
+	// from the root and leads to the target.
+	paths := &PathSet{
+		Target: target,
+		Nodes:  map[string]bool{target: true},
+		Edges:  make(map[[2]string]bool),
+	}
+	queue = []string{target}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, parent := range parents[name] {
+			paths.Edges[[2]string{parent, name}] = true
+			if !paths.Nodes[parent] {
+				paths.Nodes[parent] = true
+				queue = append(queue, parent)
+			}
+		}
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,163 @@
+package graph
+
+import (
+	"errors"
+	"go/build"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LoadPlatformConstraints inspects the sources of every dependency
+// extracted in the module cache at modCacheDir for cgo use and GOOS
+// restrictions among platforms, and returns how many modules were
+// inspected. When package usage is loaded, only the imported packages of a
+// module are inspected, otherwise all of them. Modules whose source is not
+// in the cache are marked PlatformUnchecked.
+func (g *EnhancedDependencyGraph) LoadPlatformConstraints(modCacheDir string, platforms []string) int {
+	inspected := 0
+	for name, node := range g.EnhancedNodes {
+		if name == g.Root.Name {
+			continue
+		}
+
+		node.RequiresCGO, node.CGOPackages, node.UnsupportedOS, node.PlatformUnchecked = false, nil, nil, false
+		if g.PackagesLoaded && !node.Imported {
+			continue
+		}
+
+		dir, ok := moduleSourceDir(modCacheDir, node.Name, node.Version)
+		if ok {
+			_, err := os.Stat(dir)
+			ok = err == nil
+		}
+		node.PlatformUnchecked = !ok
+		if !ok {
+			continue
+		}
+
+		var packageDirs []string
+		if g.PackagesLoaded {
+			for _, pkg := range node.ImportedPackages {
+				rel := strings.TrimPrefix(strings.TrimPrefix(pkg, node.Name), "/")
+				packageDirs = append(packageDirs, filepath.Join(dir, filepath.FromSlash(rel)))
+			}
+		} else {
+			packageDirs = modulePackageDirs(dir)
+		}
+
+		inspectPlatforms(node, dir, packageDirs, platforms)
+		inspected++
+	}
+	return inspected
+}
+
+// inspectPlatforms records on node which of packageDirs use cgo and which
+// platforms none of them builds on. Commands are skipped, since they are
+// never imported.
+func inspectPlatforms(node *EnhancedNode, moduleDir string, packageDirs []string, platforms []string) {
+	supported := make(map[string]bool, len(platforms))
+	cgo := make(map[string]bool)
+	checked := false
+
+	for _, dir := range packageDirs {
+		for _, goos := range platforms {
+			ctxt := build.Default
+			ctxt.GOOS = goos
+			ctxt.GOARCH = "amd64"
+			ctxt.CgoEnabled = true
+
+			pkg, err := ctxt.ImportDir(dir, 0)
+			var noGo *build.NoGoError
+			if errors.As(err, &noGo) {
+				checked = true
+				continue
+			}
+			if err != nil || pkg.Name == "main" {
+				continue
+			}
+
+			checked = true
+			supported[goos] = true
+			if len(pkg.CgoFiles) > 0 {
+				rel, err := filepath.Rel(moduleDir, dir)
+				if err != nil {
+					continue
+				}
+				cgo[path.Join(node.Name, filepath.ToSlash(rel))] = true
+			}
+		}
+	}
+
+	for pkg := range cgo {
+		node.CGOPackages = append(node.CGOPackages, pkg)
+	}
+	sort.Strings(node.CGOPackages)
+	node.RequiresCGO = len(node.CGOPackages) > 0
+
+	if !checked {
+		return
+	}
+	for _, goos := range platforms {
+		if !supported[goos] {
+			node.UnsupportedOS = append(node.UnsupportedOS, goos)
+		}
+	}
+}
+
+// modulePackageDirs returns the directories under moduleDir that may hold
+// packages of the module, skipping testdata, vendor, hidden directories and
+// nested modules.
+func modulePackageDirs(moduleDir string) []string {
+	var dirs []string
+	filepath.WalkDir(moduleDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if p != moduleDir {
+			name := d.Name()
+			if name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(p, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+		dirs = append(dirs, p)
+		return nil
+	})
+	return dirs
+}
+
+// PlatformConstrainedNodes returns the dependencies that use cgo or do not
+// build on every inspected platform, sorted by name.
+func (g *EnhancedDependencyGraph) PlatformConstrainedNodes() []*EnhancedNode {
+	var nodes []*EnhancedNode
+	for name, node := range g.EnhancedNodes {
+		if name != g.Root.Name && (node.RequiresCGO || len(node.UnsupportedOS) > 0) {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	return nodes
+}
+
+// PlatformUncheckedNodes returns the dependencies whose source was not in
+// the module cache, sorted by name.
+func (g *EnhancedDependencyGraph) PlatformUncheckedNodes() []*EnhancedNode {
+	var nodes []*EnhancedNode
+	for name, node := range g.EnhancedNodes {
+		if name != g.Root.Name && node.PlatformUnchecked {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	return nodes
+}
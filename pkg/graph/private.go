@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"sort"
+
+	"golang.org/x/mod/module"
+)
+
+// MarkPrivate flags the modules matching patterns, a comma-separated list
+// of module path prefix globs as used by GOPRIVATE, and returns how many
+// dependencies matched. Private modules are never sent to public services:
+// remote vulnerability providers and repository URL lookups skip them.
+func (g *EnhancedDependencyGraph) MarkPrivate(patterns string) int {
+	count := 0
+	for name, node := range g.EnhancedNodes {
+		node.Private = patterns != "" && module.MatchPrefixPatterns(patterns, name)
+		if node.Private && name != g.Root.Name {
+			count++
+		}
+	}
+	return count
+}
+
+// NotScannedNodes returns the dependencies some vulnerability provider
+// skipped because they are private, sorted by module path.
+func (g *EnhancedDependencyGraph) NotScannedNodes() []*EnhancedNode {
+	var nodes []*EnhancedNode
+	for name, node := range g.EnhancedNodes {
+		if name == g.Root.Name || len(node.NotScannedBy) == 0 {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	return nodes
+}
+
+// isRemote reports whether provider sends module paths to an external
+// service.
+func isRemote(provider VulnProvider) bool {
+	remote, ok := provider.(RemoteProvider)
+	return ok && remote.Remote()
+}
@@ -0,0 +1,149 @@
+package graph
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"goviz/pkg/parser"
+)
+
+// Provenance states recorded by LoadProvenance.
+const (
+	// ProvenanceVerified: the checksum database's transparency log records
+	// the version, with the hash go.sum holds for it.
+	ProvenanceVerified = "verified"
+	// ProvenanceMismatch: the log records a different hash than go.sum, so
+	// the code in go.sum is not what was published.
+	ProvenanceMismatch = "mismatch"
+	// ProvenanceMissing: the log has no record of the version.
+	ProvenanceMissing = "missing"
+	// ProvenanceUnknown: the lookup failed, go.sum holds no hash of the
+	// module zip to compare, or the module is private or replaced by a
+	// local directory.
+	ProvenanceUnknown = "unknown"
+)
+
+// ProvenanceSource looks up module versions in a checksum database.
+type ProvenanceSource interface {
+	// Name identifies the database in reports.
+	Name() string
+
+	// RecordedHash returns the hash logged for the module zip of
+	// path@version, or "" when the database has no record of it.
+	RecordedHash(ctx context.Context, path, version string) (string, error)
+}
+
+// ProvenanceCoverage counts the dependencies by provenance state.
+type ProvenanceCoverage struct {
+	Total    int `json:"total" yaml:"total"`
+	Verified int `json:"verified" yaml:"verified"`
+	Mismatch int `json:"mismatch" yaml:"mismatch"`
+	Missing  int `json:"missing" yaml:"missing"`
+	Unknown  int `json:"unknown" yaml:"unknown"`
+}
+
+// LoadProvenance looks up every dependency in source and records the
+// result in Provenance. Modules replaced by another module version are
+// looked up as the replacement. It returns the error for every module that
+// could not be looked up; those are left unknown, as are modules whose
+// go.sum entry has no hash of the module zip to compare.
+func (g *EnhancedDependencyGraph) LoadProvenance(ctx context.Context, source ProvenanceSource) map[string]error {
+	g.ProvenanceDatabase = source.Name()
+
+	replacements := make(map[string]Replacement)
+	for _, replacement := range g.Replacements {
+		replacements[replacement.Old.Path] = replacement
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed = make(map[string]error)
+	)
+	sem := make(chan struct{}, requirementFetchWorkers)
+
+	for name, node := range g.EnhancedNodes {
+		if name == g.Root.Name {
+			continue
+		}
+		node.Provenance = ProvenanceUnknown
+		if node.Private {
+			continue
+		}
+		path, modVersion := node.Name, node.Version
+		if replacement, ok := replacements[name]; ok && (replacement.Old.Version == "" || replacement.Old.Version == node.Version) {
+			if replacement.IsLocal() {
+				continue
+			}
+			path, modVersion = replacement.New.Path, replacement.New.Version
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(node *EnhancedNode, path, modVersion string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			recorded, err := source.RecordedHash(ctx, path, modVersion)
+			if err != nil {
+				mu.Lock()
+				failed[node.Name] = err
+				mu.Unlock()
+				return
+			}
+
+			switch entry, ok := parser.LookupGoSum(g.GoSumEntries, path, modVersion); {
+			case recorded == "":
+				node.Provenance = ProvenanceMissing
+			case ok && entry.Hash != "" && entry.Hash != recorded:
+				node.Provenance = ProvenanceMismatch
+			case !ok || entry.Hash == "":
+				node.Provenance = ProvenanceUnknown
+			default:
+				node.Provenance = ProvenanceVerified
+			}
+		}(node, path, modVersion)
+	}
+	wg.Wait()
+
+	return failed
+}
+
+// ProvenanceCoverage counts the dependencies by the provenance recorded by
+// LoadProvenance.
+func (g *EnhancedDependencyGraph) ProvenanceCoverage() ProvenanceCoverage {
+	var coverage ProvenanceCoverage
+	for name, node := range g.EnhancedNodes {
+		if name == g.Root.Name {
+			continue
+		}
+		coverage.Total++
+		switch node.Provenance {
+		case ProvenanceVerified:
+			coverage.Verified++
+		case ProvenanceMismatch:
+			coverage.Mismatch++
+		case ProvenanceMissing:
+			coverage.Missing++
+		default:
+			coverage.Unknown++
+		}
+	}
+	return coverage
+}
+
+// ProvenanceNodes returns the dependencies with the given provenance state,
+// sorted by name.
+func (g *EnhancedDependencyGraph) ProvenanceNodes(state string) []*EnhancedNode {
+	var nodes []*EnhancedNode
+	for name, node := range g.EnhancedNodes {
+		if name != g.Root.Name && node.Provenance == state {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	return nodes
+}
@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"context"
+	"sync"
+)
+
+// RepoURLResolver maps a module path to its source repository URL.
+type RepoURLResolver interface {
+	RepoURL(ctx context.Context, modulePath string) (string, error)
+}
+
+const repoURLWorkers = 8
+
+// ResolveRepoURLs sets RepoURL on every node, including the main module,
+// and returns how many were resolved. Modules that cannot be resolved keep
+// an empty RepoURL, as do private modules, which are not looked up. Failed
+// lookups are recorded as warnings.
+func (g *EnhancedDependencyGraph) ResolveRepoURLs(ctx context.Context, resolver RepoURLResolver) int {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		resolved int
+	)
+	sem := make(chan struct{}, repoURLWorkers)
+
+	for _, node := range g.EnhancedNodes {
+		if node.Private {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(node *EnhancedNode) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			url, err := resolver.RepoURL(ctx, node.Name)
+			if err != nil {
+				mu.Lock()
+				g.AddWarning(WarningRepoURLUnresolved, node.Name, "could not resolve repository URL of %s: %v", node.Name, err)
+				mu.Unlock()
+				return
+			}
+			if url == "" {
+				return
+			}
+			node.RepoURL = url
+
+			mu.Lock()
+			resolved++
+			mu.Unlock()
+		}(node)
+	}
+	wg.Wait()
+
+	return resolved
+}
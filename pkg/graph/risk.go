@@ -0,0 +1,102 @@
+package graph
+
+import "time"
+
+// Risk score weights. Each factor contributes points up to its cap, and the
+// total is capped at 100:
+//
+//	vulnerabilities  CRITICAL 25, HIGH 15, MEDIUM 5, LOW 2 each, at most 60
+//	stale modules    2 each, at most 15
+//	unknown licenses 2 each, at most 15
+//	conflicts        3 each, at most 10
+const (
+	riskCriticalWeight = 25
+	riskHighWeight     = 15
+	riskMediumWeight   = 5
+	riskLowWeight      = 2
+	riskVulnCap        = 60
+
+	riskStaleWeight    = 2
+	riskStaleCap       = 15
+	riskLicenseWeight  = 2
+	riskLicenseCap     = 15
+	riskConflictWeight = 3
+	riskConflictCap    = 10
+)
+
+// staleAge is how old a pseudo-version commit may be before the module
+// counts as stale.
+const staleAge = 365 * 24 * time.Hour
+
+// RiskScore summarizes the security and compliance exposure of a module's
+// dependencies. Score runs from 0 (no findings) to 100; the points of each
+// factor are listed separately so the figure can be explained.
+type RiskScore struct {
+	Score           int    `json:"score" yaml:"score"`
+	Grade           string `json:"grade" yaml:"grade"`
+	Vulnerabilities int    `json:"vulnerabilities" yaml:"vulnerabilities"`
+	Stale           int    `json:"stale" yaml:"stale"`
+	UnknownLicenses int    `json:"unknown_licenses" yaml:"unknown_licenses"`
+	Conflicts       int    `json:"conflicts" yaml:"conflicts"`
+}
+
+// RiskScore computes the dependency risk score from the security issues,
+// licenses and conflicts already recorded on the graph, so it should be
+// called after CheckSecurity, AnalyzeLicenses and DetectVersionConflicts.
+// A module is stale when it is marked outdated or pinned to a pseudo-version
+// whose commit is more than a year old. Unlike the doctor health score,
+// which rates maintenance, this rates exposure.
+func (g *EnhancedDependencyGraph) RiskScore() RiskScore {
+	var risk RiskScore
+
+	for _, issue := range g.SecurityIssues {
+		switch issue.Severity {
+		case "CRITICAL":
+			risk.Vulnerabilities += riskCriticalWeight
+		case "HIGH":
+			risk.Vulnerabilities += riskHighWeight
+		case "MEDIUM":
+			risk.Vulnerabilities += riskMediumWeight
+		case "LOW":
+			risk.Vulnerabilities += riskLowWeight
+		}
+	}
+	risk.Vulnerabilities = min(risk.Vulnerabilities, riskVulnCap)
+
+	for name, node := range g.EnhancedNodes {
+		if name == g.Root.Name {
+			continue
+		}
+		if node.IsOutdated || (node.IsPseudoVersion && !node.PseudoVersionTime.IsZero() && time.Since(node.PseudoVersionTime) > staleAge) {
+			risk.Stale += riskStaleWeight
+		}
+		if node.License == "" || node.License == "Unknown" {
+			risk.UnknownLicenses += riskLicenseWeight
+		}
+	}
+	risk.Stale = min(risk.Stale, riskStaleCap)
+	risk.UnknownLicenses = min(risk.UnknownLicenses, riskLicenseCap)
+
+	risk.Conflicts = min(len(g.Conflicts)*riskConflictWeight, riskConflictCap)
+
+	risk.Score = min(risk.Vulnerabilities+risk.Stale+risk.UnknownLicenses+risk.Conflicts, 100)
+	risk.Grade = RiskGrade(risk.Score)
+	return risk
+}
+
+// RiskGrade maps a risk score to a letter: A below 10, B below 25, C below
+// 40, D below 60 and F otherwise.
+func RiskGrade(score int) string {
+	switch {
+	case score < 10:
+		return "A"
+	case score < 25:
+		return "B"
+	case score < 40:
+		return "C"
+	case score < 60:
+		return "D"
+	default:
+		return "F"
+	}
+}
@@ -0,0 +1,227 @@
+package graph
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule flags module versions matching a policy. Every condition that is set
+// must match; within a list, any entry matches. A rule without conditions
+// matches nothing.
+type Rule struct {
+	ID string `yaml:"id"`
+
+	// Module is a module path or a path.Match pattern such as
+	// "github.com/org/*".
+	Module         string   `yaml:"module,omitempty"`
+	ModuleContains []string `yaml:"module_contains,omitempty"`
+
+	// Version is a comma-separated list of comparisons that must all hold,
+	// e.g. ">=v1.2.0, <v1.5.3". Operators are =, !=, <, <=, > and >=.
+	Version         string   `yaml:"version,omitempty"`
+	VersionContains []string `yaml:"version_contains,omitempty"`
+	Versions        []string `yaml:"versions,omitempty"`
+
+	Severity string `yaml:"severity"`
+	Message  string `yaml:"message"`
+	FixedIn  string `yaml:"fixed_in,omitempty"`
+}
+
+// RulesFile is the format of a --rules file. The default rules are applied
+// as well unless defaults is false.
+type RulesFile struct {
+	Defaults *bool  `yaml:"defaults,omitempty"`
+	Rules    []Rule `yaml:"rules"`
+}
+
+// DefaultRules are the built-in heuristics used by HeuristicProvider.
+var DefaultRules = []Rule{
+	{
+		ID:              "GHSA-example",
+		Module:          "github.com/gin-gonic/gin",
+		VersionContains: []string{"v1.8", "v1.7", "v1.4"},
+		Severity:        "MEDIUM",
+		Message:         "Check for latest version with security fixes",
+		FixedIn:         "v1.9.1+",
+	},
+	{
+		ID:              "CVE-2023-example",
+		Module:          "github.com/gorilla/websocket",
+		VersionContains: []string{"v1.8", "v1.7", "v1.4"},
+		Severity:        "HIGH",
+		Message:         "WebSocket vulnerability in older versions",
+		FixedIn:         "v1.5.0+",
+	},
+	{
+		ID:              "DEV-VERSION",
+		VersionContains: []string{"dev", "alpha", "beta", "rc", "snapshot"},
+		Severity:        "LOW",
+		Message:         "Development version detected in dependencies",
+		FixedIn:         "Use stable release version",
+	},
+	{
+		ID:              "OLD-VERSION",
+		VersionContains: []string{"20161208", "20170", "20180"},
+		Severity:        "MEDIUM",
+		Message:         "Very old package version may have security vulnerabilities",
+		FixedIn:         "Update to latest version",
+	},
+	{
+		ID:             "INSECURE-CRYPTO",
+		ModuleContains: []string{"crypto/md5", "crypto/sha1", "net/http/httputil"},
+		Severity:       "HIGH",
+		Message:        "Package uses insecure cryptographic functions",
+		FixedIn:        "Use secure alternatives (SHA-256, bcrypt, etc.)",
+	},
+	{
+		ID:       "NO-VERSION",
+		Versions: []string{"", "v0.0.0"},
+		Severity: "LOW",
+		Message:  "Package without proper versioning detected",
+		FixedIn:  "Use properly versioned packages",
+	},
+}
+
+// LoadRules reads a rules file and returns the rules to apply, including
+// DefaultRules unless the file disables them.
+func LoadRules(filename string) ([]Rule, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	var file RulesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse rules file %s: %w", filename, err)
+	}
+
+	for i, rule := range file.Rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: rule %d: %w", filename, i+1, err)
+		}
+	}
+
+	if file.Defaults != nil && !*file.Defaults {
+		return file.Rules, nil
+	}
+	return append(append([]Rule(nil), DefaultRules...), file.Rules...), nil
+}
+
+// Validate checks that the rule has an ID, a canonical severity, at least
+// one condition and well-formed patterns.
+func (r Rule) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("missing id")
+	}
+	if SeverityRank(strings.ToUpper(r.Severity)) == 0 {
+		return fmt.Errorf("%s: unsupported severity %q (supported: %s)", r.ID, r.Severity, strings.Join(SeverityLevels, ", "))
+	}
+	if r.Module == "" && len(r.ModuleContains) == 0 && r.Version == "" && len(r.VersionContains) == 0 && r.Versions == nil {
+		return fmt.Errorf("%s: no match conditions", r.ID)
+	}
+	if _, err := path.Match(r.Module, ""); err != nil {
+		return fmt.Errorf("%s: invalid module pattern %q: %w", r.ID, r.Module, err)
+	}
+	if _, err := matchVersionConstraint(r.Version, "v0.0.0"); err != nil {
+		return fmt.Errorf("%s: %w", r.ID, err)
+	}
+	return nil
+}
+
+// Match reports whether the rule applies to modulePath at version.
+func (r Rule) Match(modulePath, version string) bool {
+	if r.Module == "" && len(r.ModuleContains) == 0 && r.Version == "" && len(r.VersionContains) == 0 && r.Versions == nil {
+		return false
+	}
+	if r.Module != "" {
+		if matched, _ := path.Match(r.Module, modulePath); !matched {
+			return false
+		}
+	}
+	if len(r.ModuleContains) > 0 && !containsAny(modulePath, r.ModuleContains) {
+		return false
+	}
+	if r.Version != "" {
+		if matched, err := matchVersionConstraint(r.Version, version); err != nil || !matched {
+			return false
+		}
+	}
+	if len(r.VersionContains) > 0 && !containsAny(version, r.VersionContains) {
+		return false
+	}
+	if r.Versions != nil && !hasString(r.Versions, version) {
+		return false
+	}
+	return true
+}
+
+// Issue returns the security issue reported for a match.
+func (r Rule) Issue() SecurityIssue {
+	return SecurityIssue{
+		ID:          r.ID,
+		Severity:    strings.ToUpper(r.Severity),
+		Description: r.Message,
+		FixedIn:     r.FixedIn,
+	}
+}
+
+// matchVersionConstraint evaluates a comma-separated list of comparisons
+// against version using semantic version ordering.
+func matchVersionConstraint(constraint, version string) (bool, error) {
+	if strings.TrimSpace(constraint) == "" {
+		return true, nil
+	}
+
+	matched := true
+	for _, part := range strings.Split(constraint, ",") {
+		part = strings.TrimSpace(part)
+		bound := strings.TrimLeft(part, "<>=!")
+		op := part[:len(part)-len(bound)]
+		bound = strings.TrimSpace(bound)
+		if !semver.IsValid(bound) {
+			return false, fmt.Errorf("invalid version constraint %q", part)
+		}
+
+		c := semver.Compare(version, bound)
+		switch op {
+		case "=", "":
+			matched = matched && c == 0
+		case "!=":
+			matched = matched && c != 0
+		case "<":
+			matched = matched && c < 0
+		case "<=":
+			matched = matched && c <= 0
+		case ">":
+			matched = matched && c > 0
+		case ">=":
+			matched = matched && c >= 0
+		default:
+			return false, fmt.Errorf("invalid operator in version constraint %q", part)
+		}
+	}
+	return matched, nil
+}
+
+func containsAny(s string, substrings []string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SeverityLevels are the canonical severities, most severe first.
+var SeverityLevels = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"}
+
+// NormalizeSeverity maps a provider severity to one of SeverityLevels.
+// severity may be a label (including GitHub's MODERATE), a numeric CVSS
+// score or a CVSS v3 vector; score is an already known CVSS score. The
+// returned score is the CVSS score when one is known, otherwise 0.
+// Advisories without any usable severity are treated as MEDIUM.
+func NormalizeSeverity(severity string, score float64) (string, float64) {
+	severity = strings.ToUpper(strings.TrimSpace(severity))
+
+	switch severity {
+	case "CRITICAL":
+		return "CRITICAL", score
+	case "HIGH", "IMPORTANT":
+		return "HIGH", score
+	case "MEDIUM", "MODERATE":
+		return "MEDIUM", score
+	case "LOW", "NEGLIGIBLE", "MINIMAL", "INFO":
+		return "LOW", score
+	}
+
+	if score == 0 {
+		if value, err := strconv.ParseFloat(severity, 64); err == nil {
+			score = value
+		} else if value, err := CVSSv3Score(severity); err == nil {
+			score = value
+		}
+	}
+
+	switch {
+	case score >= 9.0:
+		return "CRITICAL", score
+	case score >= 7.0:
+		return "HIGH", score
+	case score >= 4.0:
+		return "MEDIUM", score
+	case score > 0:
+		return "LOW", score
+	default:
+		return "MEDIUM", 0
+	}
+}
+
+// SeverityRank orders canonical severities: CRITICAL is 4, LOW is 1 and
+// anything else 0.
+func SeverityRank(severity string) int {
+	for i, level := range SeverityLevels {
+		if level == severity {
+			return len(SeverityLevels) - i
+		}
+	}
+	return 0
+}
+
+var cvssWeights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// CVSSv3Score computes the base score of a CVSS v3.0 or v3.1 vector such
+// as "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H".
+func CVSSv3Score(vector string) (float64, error) {
+	parts := strings.Split(strings.ToUpper(vector), "/")
+	if len(parts) == 0 || !strings.HasPrefix(parts[0], "CVSS:3") {
+		return 0, fmt.Errorf("not a CVSS v3 vector: %s", vector)
+	}
+
+	metrics := make(map[string]string)
+	for _, part := range parts[1:] {
+		key, value, ok := strings.Cut(part, ":")
+		if !ok {
+			return 0, fmt.Errorf("invalid CVSS metric %q", part)
+		}
+		metrics[key] = value
+	}
+
+	weight := make(map[string]float64)
+	for metric, values := range cvssWeights {
+		w, ok := values[metrics[metric]]
+		if !ok {
+			return 0, fmt.Errorf("missing or invalid CVSS metric %s", metric)
+		}
+		weight[metric] = w
+	}
+
+	changed := metrics["S"] == "C"
+	if !changed && metrics["S"] != "U" {
+		return 0, fmt.Errorf("missing or invalid CVSS metric S")
+	}
+
+	var privileges float64
+	switch metrics["PR"] {
+	case "N":
+		privileges = 0.85
+	case "L":
+		privileges = 0.62
+		if changed {
+			privileges = 0.68
+		}
+	case "H":
+		privileges = 0.27
+		if changed {
+			privileges = 0.5
+		}
+	default:
+		return 0, fmt.Errorf("missing or invalid CVSS metric PR")
+	}
+
+	iss := 1 - (1-weight["C"])*(1-weight["I"])*(1-weight["A"])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, nil
+	}
+
+	exploitability := 8.22 * weight["AV"] * weight["AC"] * privileges * weight["UI"]
+	if changed {
+		return cvssRoundUp(math.Min(1.08*(impact+exploitability), 10)), nil
+	}
+	return cvssRoundUp(math.Min(impact+exploitability, 10)), nil
+}
+
+// cvssRoundUp rounds up to one decimal as defined by CVSS v3.1, avoiding
+// floating point artifacts such as 4.000001 becoming 4.1.
+func cvssRoundUp(value float64) float64 {
+	scaled := int(math.Round(value * 100000))
+	if scaled%10000 == 0 {
+		return float64(scaled) / 100000
+	}
+	return float64(scaled/10000+1) / 10
+}
@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"sort"
+
+	"golang.org/x/mod/semver"
+)
+
+// SortKeys are the orderings accepted by SortNodes and SortSecurityIssues.
+var SortKeys = []string{"name", "version", "severity", "risk"}
+
+func ValidSortKey(key string) bool {
+	for _, k := range SortKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// SortNodes orders nodes by module path, by version (oldest first), by the
+// most severe security issue (most severe first), or by risk: the most
+// severe issue, then outdated modules first. Severities are ranked like
+// --fail-on ranks them. Ties are broken by module path so the order is
+// always deterministic.
+func SortNodes(nodes []*EnhancedNode, key string) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		switch key {
+		case "version":
+			if c := semver.Compare(a.Version, b.Version); c != 0 {
+				return c < 0
+			}
+		case "severity":
+			if ra, rb := maxSeverityRank(a.SecurityIssues), maxSeverityRank(b.SecurityIssues); ra != rb {
+				return ra > rb
+			}
+		case "risk":
+			if ra, rb := maxSeverityRank(a.SecurityIssues), maxSeverityRank(b.SecurityIssues); ra != rb {
+				return ra > rb
+			}
+			if a.IsOutdated != b.IsOutdated {
+				return a.IsOutdated
+			}
+		}
+		return a.Name < b.Name
+	})
+}
+
+// SortedNodes returns the dependency nodes, excluding the main module, in
+// the order given by key.
+func (g *EnhancedDependencyGraph) SortedNodes(key string) []*EnhancedNode {
+	nodes := make([]*EnhancedNode, 0, len(g.EnhancedNodes))
+	for name, node := range g.EnhancedNodes {
+		if name != g.Root.Name {
+			nodes = append(nodes, node)
+		}
+	}
+	SortNodes(nodes, key)
+	return nodes
+}
+
+// SortSecurityIssues orders issues like SortNodes orders their modules;
+// issues of the same module are ordered by ID. Issues carry no outdated
+// flag, so risk orders them like severity.
+func SortSecurityIssues(issues []SecurityIssue, key string) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		switch key {
+		case "version":
+			if c := semver.Compare(a.Version, b.Version); c != 0 {
+				return c < 0
+			}
+		case "severity", "risk":
+			if ra, rb := SeverityRank(a.Severity), SeverityRank(b.Severity); ra != rb {
+				return ra > rb
+			}
+		}
+		if a.Module != b.Module {
+			return a.Module < b.Module
+		}
+		return a.ID < b.ID
+	})
+}
+
+func maxSeverityRank(issues []SecurityIssue) int {
+	rank := 0
+	for _, issue := range issues {
+		rank = max(rank, SeverityRank(issue.Severity))
+	}
+	return rank
+}
+
+// MaxSeverity returns the most severe canonical severity among issues, or
+// "" if none of them has one.
+func MaxSeverity(issues []SecurityIssue) string {
+	if rank := maxSeverityRank(issues); rank > 0 {
+		return SeverityLevels[len(SeverityLevels)-rank]
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestSortNodes(t *testing.T) {
+	node := func(name, version string, outdated bool, severities ...string) *EnhancedNode {
+		n := &EnhancedNode{Node: &Node{Name: name, Version: version}, IsOutdated: outdated}
+		for _, severity := range severities {
+			n.SecurityIssues = append(n.SecurityIssues, SecurityIssue{Module: name, Version: version, Severity: severity})
+		}
+		return n
+	}
+	nodes := []*EnhancedNode{
+		node("example.com/a", "v1.2.0", false, "LOW"),
+		node("example.com/b", "v1.10.0", false, "HIGH"),
+		node("example.com/c", "v1.2.0", true, "LOW", "HIGH"),
+		node("example.com/d", "v0.9.0", true),
+		node("example.com/e", "v1.10.0", false, "LOW"),
+	}
+
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{"name", []string{"example.com/a", "example.com/b", "example.com/c", "example.com/d", "example.com/e"}},
+		{"version", []string{"example.com/d", "example.com/a", "example.com/c", "example.com/b", "example.com/e"}},
+		{"severity", []string{"example.com/b", "example.com/c", "example.com/a", "example.com/e", "example.com/d"}},
+		{"risk", []string{"example.com/c", "example.com/b", "example.com/a", "example.com/e", "example.com/d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			for range 20 {
+				shuffled := slices.Clone(nodes)
+				rand.Shuffle(len(shuffled), func(i, j int) {
+					shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+				})
+				SortNodes(shuffled, tt.key)
+
+				var got []string
+				for _, n := range shuffled {
+					got = append(got, n.Name)
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Fatalf("SortNodes(%q) = %v, want %v", tt.key, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSortSecurityIssues(t *testing.T) {
+	issues := []SecurityIssue{
+		{Module: "example.com/b", Version: "v1.10.0", ID: "GO-2", Severity: "HIGH"},
+		{Module: "example.com/b", Version: "v1.10.0", ID: "GO-1", Severity: "HIGH"},
+		{Module: "example.com/a", Version: "v1.2.0", ID: "GO-3", Severity: "LOW"},
+		{Module: "example.com/c", Version: "v1.2.0", ID: "GO-4", Severity: "CRITICAL"},
+		{Module: "example.com/a", Version: "v1.2.0", ID: "GO-0", Severity: "LOW"},
+	}
+
+	bySeverity := []string{"example.com/c GO-4", "example.com/b GO-1", "example.com/b GO-2", "example.com/a GO-0", "example.com/a GO-3"}
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{"name", []string{"example.com/a GO-0", "example.com/a GO-3", "example.com/b GO-1", "example.com/b GO-2", "example.com/c GO-4"}},
+		{"version", []string{"example.com/a GO-0", "example.com/a GO-3", "example.com/c GO-4", "example.com/b GO-1", "example.com/b GO-2"}},
+		{"severity", bySeverity},
+		{"risk", bySeverity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			for range 20 {
+				shuffled := slices.Clone(issues)
+				rand.Shuffle(len(shuffled), func(i, j int) {
+					shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+				})
+				SortSecurityIssues(shuffled, tt.key)
+
+				var got []string
+				for _, issue := range shuffled {
+					got = append(got, issue.Module+" "+issue.ID)
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Fatalf("SortSecurityIssues(%q) = %v, want %v", tt.key, got, tt.want)
+				}
+			}
+		})
+	}
+}
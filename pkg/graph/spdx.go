@@ -0,0 +1,64 @@
+package graph
+
+import "strings"
+
+// SPDXLicense is the SPDX License List data of a license.
+type SPDXLicense struct {
+	ID          string `json:"id" yaml:"id"`
+	Name        string `json:"name" yaml:"name"`
+	OSIApproved bool   `json:"osi_approved" yaml:"osi_approved"`
+	Reference   string `json:"reference" yaml:"reference"`
+}
+
+// spdxLicenses holds the SPDX License List entries of the licenses goviz
+// classifies, including the deprecated IDs without -only/-or-later, so
+// reports work offline.
+var spdxLicenses = map[string]SPDXLicense{}
+
+func init() {
+	for _, license := range []SPDXLicense{
+		{ID: "0BSD", Name: "BSD Zero Clause License", OSIApproved: true},
+		{ID: "CC0-1.0", Name: "Creative Commons Zero v1.0 Universal"},
+		{ID: "Unlicense", Name: "The Unlicense", OSIApproved: true},
+		{ID: "MIT", Name: "MIT License", OSIApproved: true},
+		{ID: "MIT-0", Name: "MIT No Attribution", OSIApproved: true},
+		{ID: "ISC", Name: "ISC License", OSIApproved: true},
+		{ID: "BSD-2-Clause", Name: `BSD 2-Clause "Simplified" License`, OSIApproved: true},
+		{ID: "BSD-3-Clause", Name: `BSD 3-Clause "New" or "Revised" License`, OSIApproved: true},
+		{ID: "Zlib", Name: "zlib License", OSIApproved: true},
+		{ID: "Apache-2.0", Name: "Apache License 2.0", OSIApproved: true},
+		{ID: "MPL-2.0", Name: "Mozilla Public License 2.0", OSIApproved: true},
+		{ID: "EPL-2.0", Name: "Eclipse Public License 2.0", OSIApproved: true},
+		{ID: "CDDL-1.0", Name: "Common Development and Distribution License 1.0", OSIApproved: true},
+		{ID: "LGPL-2.1", Name: "GNU Lesser General Public License v2.1 only", OSIApproved: true},
+		{ID: "LGPL-2.1-only", Name: "GNU Lesser General Public License v2.1 only", OSIApproved: true},
+		{ID: "LGPL-2.1-or-later", Name: "GNU Lesser General Public License v2.1 or later", OSIApproved: true},
+		{ID: "LGPL-3.0", Name: "GNU Lesser General Public License v3.0 only", OSIApproved: true},
+		{ID: "LGPL-3.0-only", Name: "GNU Lesser General Public License v3.0 only", OSIApproved: true},
+		{ID: "LGPL-3.0-or-later", Name: "GNU Lesser General Public License v3.0 or later", OSIApproved: true},
+		{ID: "GPL-2.0", Name: "GNU General Public License v2.0 only", OSIApproved: true},
+		{ID: "GPL-2.0-only", Name: "GNU General Public License v2.0 only", OSIApproved: true},
+		{ID: "GPL-2.0-or-later", Name: "GNU General Public License v2.0 or later", OSIApproved: true},
+		{ID: "GPL-3.0", Name: "GNU General Public License v3.0 only", OSIApproved: true},
+		{ID: "GPL-3.0-only", Name: "GNU General Public License v3.0 only", OSIApproved: true},
+		{ID: "GPL-3.0-or-later", Name: "GNU General Public License v3.0 or later", OSIApproved: true},
+		{ID: "AGPL-3.0", Name: "GNU Affero General Public License v3.0", OSIApproved: true},
+		{ID: "AGPL-3.0-only", Name: "GNU Affero General Public License v3.0 only", OSIApproved: true},
+		{ID: "AGPL-3.0-or-later", Name: "GNU Affero General Public License v3.0 or later", OSIApproved: true},
+	} {
+		license.Reference = "https://spdx.org/licenses/" + license.ID + ".html"
+		spdxLicenses[license.ID] = license
+	}
+}
+
+// LookupSPDXLicense returns the SPDX License List data of a license ID. A
+// "+" suffix is read as -or-later, and "X WITH exception" is looked up as
+// its base license X. It reports false for IDs outside the embedded list.
+func LookupSPDXLicense(id string) (SPDXLicense, bool) {
+	base, _, _ := strings.Cut(id, " WITH ")
+	if strings.HasSuffix(base, "+") {
+		base = strings.TrimSuffix(base, "+") + "-or-later"
+	}
+	license, ok := spdxLicenses[base]
+	return license, ok
+}
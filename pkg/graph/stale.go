@@ -0,0 +1,76 @@
+package graph
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ReleaseSource reports when the latest version of a module was published.
+type ReleaseSource interface {
+	LatestReleaseTime(ctx context.Context, modulePath string) (time.Time, error)
+}
+
+// LoadReleaseDates looks up the latest release of every dependency in
+// source and records it in LatestRelease and LastUpdate. Private modules
+// are skipped. It returns the error for every module that could not be
+// looked up.
+func (g *EnhancedDependencyGraph) LoadReleaseDates(ctx context.Context, source ReleaseSource) map[string]error {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed = make(map[string]error)
+	)
+	sem := make(chan struct{}, availabilityWorkers)
+
+	for name, node := range g.EnhancedNodes {
+		if name == g.Root.Name || node.Private {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(node *EnhancedNode) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			released, err := source.LatestReleaseTime(ctx, node.Name)
+			if err != nil || released.IsZero() {
+				mu.Lock()
+				failed[node.Name] = err
+				mu.Unlock()
+				return
+			}
+			node.LatestRelease = released
+			node.LastUpdate = released
+		}(node)
+	}
+	wg.Wait()
+
+	return failed
+}
+
+// StaleNodes returns the dependencies whose latest release is older than
+// maxAge, sorted by name, except those listed in ignore. Modules without a
+// known release date are never stale.
+func (g *EnhancedDependencyGraph) StaleNodes(maxAge time.Duration, ignore []string) []*EnhancedNode {
+	ignored := make(map[string]bool, len(ignore))
+	for _, name := range ignore {
+		ignored[name] = true
+	}
+
+	cutoff := time.Now().Add(-maxAge)
+	var nodes []*EnhancedNode
+	for name, node := range g.EnhancedNodes {
+		if name == g.Root.Name || ignored[name] || node.LatestRelease.IsZero() {
+			continue
+		}
+		if node.LatestRelease.Before(cutoff) {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	return nodes
+}
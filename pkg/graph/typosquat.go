@@ -0,0 +1,171 @@
+package graph
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"gopkg.in/yaml.v3"
+)
+
+// TyposquatID is the ID of the issue HeuristicProvider reports for a module
+// path that imitates a popular module.
+const TyposquatID = "TYPOSQUAT"
+
+// PopularModulesFile is the format of a --popular-modules file. The default
+// modules are used as well unless defaults is false.
+type PopularModulesFile struct {
+	Defaults *bool    `yaml:"defaults,omitempty"`
+	Modules  []string `yaml:"modules"`
+}
+
+// DefaultPopularModules are the widely used modules that dependency paths
+// are compared against, without major version suffixes.
+var DefaultPopularModules = []string{
+	"github.com/stretchr/testify",
+	"github.com/sirupsen/logrus",
+	"go.uber.org/zap",
+	"github.com/rs/zerolog",
+	"github.com/spf13/cobra",
+	"github.com/spf13/viper",
+	"github.com/spf13/pflag",
+	"github.com/urfave/cli",
+	"github.com/fatih/color",
+	"github.com/gin-gonic/gin",
+	"github.com/labstack/echo",
+	"github.com/go-chi/chi",
+	"github.com/gorilla/mux",
+	"github.com/gorilla/websocket",
+	"github.com/gofiber/fiber",
+	"github.com/valyala/fasthttp",
+	"github.com/google/uuid",
+	"github.com/google/go-cmp",
+	"github.com/pkg/errors",
+	"github.com/golang/protobuf",
+	"google.golang.org/protobuf",
+	"google.golang.org/grpc",
+	"github.com/golang-jwt/jwt",
+	"github.com/go-sql-driver/mysql",
+	"github.com/lib/pq",
+	"github.com/jackc/pgx",
+	"github.com/mattn/go-sqlite3",
+	"gorm.io/gorm",
+	"github.com/redis/go-redis",
+	"github.com/go-redis/redis",
+	"go.mongodb.org/mongo-driver",
+	"github.com/json-iterator/go",
+	"github.com/davecgh/go-spew",
+	"github.com/prometheus/client_golang",
+	"github.com/aws/aws-sdk-go",
+	"github.com/hashicorp/go-multierror",
+	"github.com/BurntSushi/toml",
+	"gopkg.in/yaml",
+	"github.com/go-playground/validator",
+	"github.com/joho/godotenv",
+	"github.com/mitchellh/mapstructure",
+	"github.com/cespare/xxhash",
+	"github.com/klauspost/compress",
+	"go.etcd.io/bbolt",
+	"k8s.io/client-go",
+	"github.com/docker/docker",
+}
+
+// LoadPopularModules reads a popular modules file and returns the modules to
+// compare against, including DefaultPopularModules unless the file disables
+// them.
+func LoadPopularModules(filename string) ([]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read popular modules file: %w", err)
+	}
+
+	var file PopularModulesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse popular modules file %s: %w", filename, err)
+	}
+
+	for i, modulePath := range file.Modules {
+		if err := module.CheckPath(modulePath); err != nil {
+			return nil, fmt.Errorf("%s: module %d: %w", filename, i+1, err)
+		}
+	}
+
+	if file.Defaults != nil && !*file.Defaults {
+		return file.Modules, nil
+	}
+	return append(append([]string(nil), DefaultPopularModules...), file.Modules...), nil
+}
+
+// TyposquatTarget returns the popular module that modulePath is a near miss
+// of: at most two edits away, or one for short paths, ignoring case and
+// major version suffixes. Popular modules themselves and modules published
+// by the same owner, which controls that namespace, are never near misses.
+func TyposquatTarget(modulePath string, popular []string) (string, int, bool) {
+	candidate := strings.ToLower(modulePathPrefix(modulePath))
+
+	best, bestDistance := "", 0
+	for _, target := range popular {
+		target = modulePathPrefix(target)
+		lower := strings.ToLower(target)
+		if lower == candidate {
+			return "", 0, false
+		}
+		if path.Dir(lower) == path.Dir(candidate) {
+			continue
+		}
+
+		maxDistance := 2
+		if len(lower) < 16 {
+			maxDistance = 1
+		}
+		distance := editDistance(candidate, lower)
+		if distance <= maxDistance && (best == "" || distance < bestDistance) {
+			best, bestDistance = target, distance
+		}
+	}
+	return best, bestDistance, best != ""
+}
+
+// typosquatIssue returns the issue reported for a near miss of target.
+func typosquatIssue(target string, distance int) SecurityIssue {
+	edits := "1 edit"
+	if distance != 1 {
+		edits = fmt.Sprintf("%d edits", distance)
+	}
+	return SecurityIssue{
+		ID:          TyposquatID,
+		Severity:    "HIGH",
+		Description: fmt.Sprintf("Module path is %s away from popular module %s; review that it is the intended dependency", edits, target),
+		FixedIn:     "Use " + target + " if it was intended",
+	}
+}
+
+func modulePathPrefix(modulePath string) string {
+	if prefix, _, ok := module.SplitPathVersion(modulePath); ok {
+		return prefix
+	}
+	return modulePath
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
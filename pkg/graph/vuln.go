@@ -0,0 +1,194 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// VulnProvider is a source of security advisories, such as OSV or an
+// internal advisory database.
+type VulnProvider interface {
+	Name() string
+	Query(ctx context.Context, modulePath, version string) ([]SecurityIssue, error)
+}
+
+// RemoteProvider is implemented by providers that send module paths to an
+// external service. They are not queried for modules marked Private.
+type RemoteProvider interface {
+	Remote() bool
+}
+
+const vulnQueryWorkers = 8
+
+// CheckSecurity queries every provider for each dependency and records the
+// merged issues with their severity normalized. Issues reported by more than
+// one provider for the same module are kept once, by ID, in provider order.
+// Remote providers are skipped for private modules, which record the
+// skipped provider names in NotScannedBy. Without providers the built-in
+// heuristics are used.
+func (g *EnhancedDependencyGraph) CheckSecurity(providers ...VulnProvider) error {
+	if len(providers) == 0 {
+		providers = []VulnProvider{HeuristicProvider{}}
+	}
+	ctx := context.Background()
+
+	var names []string
+	for name := range g.EnhancedNodes {
+		if name != g.Root.Name {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	results := make([][][]SecurityIssue, len(names))
+	errs := make([]error, len(names))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, vulnQueryWorkers)
+	for i, name := range names {
+		node := g.EnhancedNodes[name]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, node *EnhancedNode) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i] = make([][]SecurityIssue, len(providers))
+			for j, provider := range providers {
+				if node.Private && isRemote(provider) {
+					continue
+				}
+				issues, err := provider.Query(ctx, node.Name, node.Version)
+				if err != nil {
+					errs[i] = fmt.Errorf("%s provider failed for %s@%s: %w", provider.Name(), node.Name, node.Version, err)
+					return
+				}
+				for k := range issues {
+					issues[k].Module, issues[k].Version = node.Name, node.Version
+					issues[k].Provider = provider.Name()
+					issues[k].Severity, issues[k].CVSSScore = NormalizeSeverity(issues[k].Severity, issues[k].CVSSScore)
+				}
+				results[i][j] = issues
+			}
+		}(i, node)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, name := range names {
+		node := g.EnhancedNodes[name]
+		for _, provider := range providers {
+			if node.Private && isRemote(provider) && !slices.Contains(node.NotScannedBy, provider.Name()) {
+				node.NotScannedBy = append(node.NotScannedBy, provider.Name())
+			}
+		}
+		node.SecurityIssues = append(node.SecurityIssues, mergeIssues(node.SecurityIssues, results[i]...)...)
+	}
+
+	// The aggregate lists each (module, issue ID) pair once, so repeated
+	// checks or overlapping providers do not inflate the reported count.
+	g.SecurityIssues = nil
+	seen := make(map[string]bool)
+	for _, name := range names {
+		for _, issue := range g.EnhancedNodes[name].SecurityIssues {
+			key := name + "|" + issue.ID
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			g.SecurityIssues = append(g.SecurityIssues, issue)
+		}
+	}
+
+	return nil
+}
+
+// FilterSecurityIssues drops issues below minSeverity from every node and
+// from the aggregate, and returns how many aggregate issues were dropped.
+func (g *EnhancedDependencyGraph) FilterSecurityIssues(minSeverity string) int {
+	minRank := SeverityRank(minSeverity)
+	keep := func(issues []SecurityIssue) []SecurityIssue {
+		var kept []SecurityIssue
+		for _, issue := range issues {
+			if SeverityRank(issue.Severity) >= minRank {
+				kept = append(kept, issue)
+			}
+		}
+		return kept
+	}
+
+	for _, node := range g.EnhancedNodes {
+		node.SecurityIssues = keep(node.SecurityIssues)
+	}
+
+	before := len(g.SecurityIssues)
+	g.SecurityIssues = keep(g.SecurityIssues)
+	return before - len(g.SecurityIssues)
+}
+
+// mergeIssues returns the issues from sets whose IDs are neither in existing
+// nor already seen in an earlier set.
+func mergeIssues(existing []SecurityIssue, sets ...[]SecurityIssue) []SecurityIssue {
+	seen := make(map[string]bool, len(existing))
+	for _, issue := range existing {
+		seen[issue.ID] = true
+	}
+
+	var merged []SecurityIssue
+	for _, issues := range sets {
+		for _, issue := range issues {
+			if seen[issue.ID] {
+				continue
+			}
+			seen[issue.ID] = true
+			merged = append(merged, issue)
+		}
+	}
+	return merged
+}
+
+// HeuristicProvider flags risky versions by matching rules against module
+// paths and version naming, without contacting any advisory database.
+// Without Rules, DefaultRules are used.
+type HeuristicProvider struct {
+	Rules []Rule
+
+	// PopularModules are compared against module paths to flag typo-squats,
+	// DefaultPopularModules when nil.
+	PopularModules []string
+}
+
+func (HeuristicProvider) Name() string {
+	return "heuristic"
+}
+
+func (p HeuristicProvider) Query(ctx context.Context, modulePath, version string) ([]SecurityIssue, error) {
+	rules := p.Rules
+	if rules == nil {
+		rules = DefaultRules
+	}
+
+	var issues []SecurityIssue
+	for _, rule := range rules {
+		if rule.Match(modulePath, version) {
+			issues = append(issues, rule.Issue())
+		}
+	}
+
+	popular := p.PopularModules
+	if popular == nil {
+		popular = DefaultPopularModules
+	}
+	if target, distance, ok := TyposquatTarget(modulePath, popular); ok {
+		issues = append(issues, typosquatIssue(target, distance))
+	}
+	return issues, nil
+}
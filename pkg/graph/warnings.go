@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Warning codes identify the kind of non-fatal problem a Warning reports,
+// so consumers of the structured reports can filter on them.
+const (
+	WarningGoSumMissing       = "go_sum_missing"
+	WarningUnresolvedModule   = "unresolved_module"
+	WarningRepoURLUnresolved  = "repo_url_unresolved"
+	WarningUpdateCheckFailed  = "update_check_failed"
+	WarningReleaseDateUnknown = "release_date_unknown"
+	WarningDeprecationUnknown = "deprecation_unknown"
+	WarningProvenanceUnknown  = "provenance_unknown"
+	WarningDirectUnknown      = "direct_unknown"
+)
+
+// Warning is a non-fatal problem found while building or analyzing the
+// graph that makes a report less complete, such as a missing go.sum or a
+// module the proxy could not serve.
+type Warning struct {
+	Code    string `json:"code" yaml:"code"`
+	Module  string `json:"module,omitempty" yaml:"module,omitempty"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// AddWarning records a warning on the graph.
+func (g *EnhancedDependencyGraph) AddWarning(code, module, format string, args ...any) {
+	g.Warnings = append(g.Warnings, Warning{Code: code, Module: module, Message: fmt.Sprintf(format, args...)})
+}
+
+// SortedWarnings returns the warnings ordered by code and module.
+func (g *EnhancedDependencyGraph) SortedWarnings() []Warning {
+	warnings := append([]Warning(nil), g.Warnings...)
+	sort.SliceStable(warnings, func(i, j int) bool {
+		if warnings[i].Code != warnings[j].Code {
+			return warnings[i].Code < warnings[j].Code
+		}
+		return warnings[i].Module < warnings[j].Module
+	})
+	return warnings
+}
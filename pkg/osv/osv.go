@@ -0,0 +1,383 @@
+package osv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"goviz/pkg/graph"
+
+	"golang.org/x/mod/semver"
+)
+
+const DefaultURL = "https://api.osv.dev"
+
+// ErrAdvisoryNotFound is returned by AffectedBy for unknown advisory IDs.
+var ErrAdvisoryNotFound = errors.New("advisory not found in OSV")
+
+// Client queries the OSV advisory database (https://osv.dev) for Go
+// modules. It implements graph.VulnProvider.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+func NewClient() *Client {
+	return &Client{
+		BaseURL:    DefaultURL,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type queryRequest struct {
+	Package   queryPackage `json:"package"`
+	Version   string       `json:"version"`
+	PageToken string       `json:"page_token,omitempty"`
+}
+
+type queryPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+type queryResponse struct {
+	Vulns         []vulnerability `json:"vulns"`
+	NextPageToken string          `json:"next_page_token"`
+}
+
+type vulnerability struct {
+	ID               string           `json:"id"`
+	Aliases          []string         `json:"aliases"`
+	Summary          string           `json:"summary"`
+	Details          string           `json:"details"`
+	Affected         []affected       `json:"affected"`
+	Severity         []severityScore  `json:"severity"`
+	DatabaseSpecific databaseSpecific `json:"database_specific"`
+}
+
+type severityScore struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type affected struct {
+	Package  queryPackage `json:"package"`
+	Versions []string     `json:"versions"`
+	Ranges   []struct {
+		Type   string `json:"type"`
+		Events []struct {
+			Introduced   string `json:"introduced,omitempty"`
+			Fixed        string `json:"fixed,omitempty"`
+			LastAffected string `json:"last_affected,omitempty"`
+		} `json:"events"`
+	} `json:"ranges"`
+}
+
+type databaseSpecific struct {
+	Severity string `json:"severity"`
+}
+
+func (c *Client) Name() string {
+	return "osv"
+}
+
+// Remote reports that queries send module paths to the OSV service, so
+// private modules are not looked up.
+func (c *Client) Remote() bool {
+	return true
+}
+
+// Query returns the advisories affecting a module version. Advisories that
+// are aliases of each other (e.g. a GHSA entry mirroring a GO entry) are
+// reported once, under the ID returned first, with the highest severity
+// label and CVSS score of the group: Go vulndb entries carry neither, so
+// they come from the aliases.
+func (c *Client) Query(ctx context.Context, modulePath, version string) ([]graph.SecurityIssue, error) {
+	if version == "" {
+		return nil, nil
+	}
+
+	req := queryRequest{
+		Package: queryPackage{Name: modulePath, Ecosystem: "Go"},
+		Version: strings.TrimPrefix(version, "v"),
+	}
+
+	var vulns []vulnerability
+	for {
+		resp, err := c.query(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		vulns = append(vulns, resp.Vulns...)
+		if resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+
+	group := make(map[string]int)
+	var issues []graph.SecurityIssue
+	for _, vuln := range vulns {
+		index, merged := group[vuln.ID]
+		for _, alias := range vuln.Aliases {
+			if i, ok := group[alias]; ok && !merged {
+				index, merged = i, true
+			}
+		}
+		if !merged {
+			index = len(issues)
+			issues = append(issues, graph.SecurityIssue{
+				ID:          vuln.ID,
+				Description: description(vuln),
+				FixedIn:     fixedIn(vuln, modulePath, version),
+			})
+		}
+		group[vuln.ID] = index
+		for _, alias := range vuln.Aliases {
+			group[alias] = index
+		}
+
+		issue := &issues[index]
+		if severityRank(vuln.DatabaseSpecific.Severity) > severityRank(issue.Severity) {
+			issue.Severity = vuln.DatabaseSpecific.Severity
+		}
+		issue.CVSSScore = max(issue.CVSSScore, cvssScore(vuln))
+		if issue.FixedIn == "" {
+			issue.FixedIn = fixedIn(vuln, modulePath, version)
+		}
+	}
+
+	return issues, nil
+}
+
+// severityRank ranks an advisory's severity label; advisories without one
+// rank below all others.
+func severityRank(label string) int {
+	if strings.TrimSpace(label) == "" {
+		return 0
+	}
+	severity, _ := graph.NormalizeSeverity(label, 0)
+	return graph.SeverityRank(severity)
+}
+
+// AffectedBy looks up a single advisory by ID (GO-, GHSA- or CVE-) and
+// returns an issue for every module in versions, a map from module path to
+// version, that the advisory affects. Only the ID is sent to OSV. When the
+// advisory itself lists no Go modules, e.g. a CVE record, the Go advisories
+// among its aliases are used instead.
+func (c *Client) AffectedBy(ctx context.Context, id string, versions map[string]string) ([]graph.SecurityIssue, error) {
+	vuln, err := c.vuln(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []*vulnerability{vuln}
+	if !vuln.affectsGoModules() {
+		candidates = nil
+		for _, alias := range vuln.Aliases {
+			aliased, err := c.vuln(ctx, alias)
+			if errors.Is(err, ErrAdvisoryNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if aliased.affectsGoModules() {
+				candidates = append(candidates, aliased)
+			}
+		}
+	}
+
+	var issues []graph.SecurityIssue
+	reported := make(map[string]bool)
+	for _, candidate := range candidates {
+		for _, aff := range candidate.Affected {
+			version, exists := versions[aff.Package.Name]
+			if aff.Package.Ecosystem != "Go" || !exists || reported[aff.Package.Name] || !aff.affects(version) {
+				continue
+			}
+			reported[aff.Package.Name] = true
+			issues = append(issues, graph.SecurityIssue{
+				Module:      aff.Package.Name,
+				Version:     version,
+				ID:          candidate.ID,
+				Severity:    candidate.DatabaseSpecific.Severity,
+				CVSSScore:   cvssScore(*candidate),
+				Description: description(*candidate),
+				FixedIn:     fixedIn(*candidate, aff.Package.Name, version),
+				Provider:    c.Name(),
+			})
+		}
+	}
+
+	return issues, nil
+}
+
+func (c *Client) vuln(ctx context.Context, id string) (*vulnerability, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+"/v1/vulns/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query OSV: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", id, ErrAdvisoryNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OSV returned %s", resp.Status)
+	}
+
+	var vuln vulnerability
+	if err := json.NewDecoder(resp.Body).Decode(&vuln); err != nil {
+		return nil, fmt.Errorf("failed to decode OSV advisory: %w", err)
+	}
+	return &vuln, nil
+}
+
+// affectsGoModules reports whether the advisory lists Go modules; the
+// standard library and toolchain entries are not modules of the graph.
+func (v *vulnerability) affectsGoModules() bool {
+	for _, aff := range v.Affected {
+		if aff.Package.Ecosystem == "Go" && aff.Package.Name != "stdlib" && aff.Package.Name != "toolchain" {
+			return true
+		}
+	}
+	return false
+}
+
+// affects reports whether version is listed in the affected versions or
+// falls into one of the SEMVER ranges, whose events are in order.
+func (a affected) affects(version string) bool {
+	for _, v := range a.Versions {
+		if "v"+strings.TrimPrefix(v, "v") == version {
+			return true
+		}
+	}
+
+	for _, r := range a.Ranges {
+		if r.Type != "SEMVER" {
+			continue
+		}
+		vulnerable := false
+		for _, event := range r.Events {
+			switch {
+			case event.Introduced != "":
+				if event.Introduced == "0" || semver.Compare(version, "v"+strings.TrimPrefix(event.Introduced, "v")) >= 0 {
+					vulnerable = true
+				}
+			case event.Fixed != "":
+				if semver.Compare(version, "v"+strings.TrimPrefix(event.Fixed, "v")) >= 0 {
+					vulnerable = false
+				}
+			case event.LastAffected != "":
+				if semver.Compare(version, "v"+strings.TrimPrefix(event.LastAffected, "v")) > 0 {
+					vulnerable = false
+				}
+			}
+		}
+		if vulnerable {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) query(ctx context.Context, query queryRequest) (*queryResponse, error) {
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OSV query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/v1/query", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query OSV: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OSV response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OSV returned %s", resp.Status)
+	}
+
+	var result queryResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode OSV response: %w", err)
+	}
+	return &result, nil
+}
+
+// cvssScore returns the highest CVSS v3 base score of an advisory, or 0
+// when it carries no CVSS v3 vector.
+func cvssScore(vuln vulnerability) float64 {
+	var best float64
+	for _, s := range vuln.Severity {
+		if s.Type != "CVSS_V3" {
+			continue
+		}
+		if score, err := graph.CVSSv3Score(s.Score); err == nil && score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+func description(vuln vulnerability) string {
+	if vuln.Summary != "" {
+		return vuln.Summary
+	}
+	if details, _, _ := strings.Cut(strings.TrimSpace(vuln.Details), "\n"); details != "" {
+		return details
+	}
+	return vuln.ID
+}
+
+// fixedIn returns the lowest fixed version above the current one listed
+// for the module, or "" if the advisory has no fix.
+func fixedIn(vuln vulnerability, modulePath, version string) string {
+	var fixed string
+	for _, aff := range vuln.Affected {
+		if aff.Package.Name != modulePath {
+			continue
+		}
+		for _, r := range aff.Ranges {
+			if r.Type != "SEMVER" {
+				continue
+			}
+			for _, event := range r.Events {
+				if event.Fixed == "" {
+					continue
+				}
+				v := "v" + strings.TrimPrefix(event.Fixed, "v")
+				if semver.Compare(v, version) <= 0 {
+					continue
+				}
+				if fixed == "" || semver.Compare(v, fixed) < 0 {
+					fixed = v
+				}
+			}
+		}
+	}
+	return fixed
+}
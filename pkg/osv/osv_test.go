@@ -0,0 +1,84 @@
+package osv
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestQueryMergesAliases(t *testing.T) {
+	goRecord := vulnerability{
+		ID:      "GO-2024-0001",
+		Aliases: []string{"CVE-2024-0001", "GHSA-aaaa-bbbb-cccc"},
+		Summary: "Remote code execution in example.com/m",
+	}
+	ghsaRecord := vulnerability{
+		ID:               "GHSA-aaaa-bbbb-cccc",
+		Aliases:          []string{"CVE-2024-0001", "GO-2024-0001"},
+		Summary:          "RCE in example.com/m",
+		Severity:         []severityScore{{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}},
+		DatabaseSpecific: databaseSpecific{Severity: "CRITICAL"},
+	}
+	otherRecord := vulnerability{
+		ID:               "GHSA-dddd-eeee-ffff",
+		Summary:          "Unrelated issue",
+		DatabaseSpecific: databaseSpecific{Severity: "MODERATE"},
+	}
+
+	tests := []struct {
+		name   string
+		vulns  []vulnerability
+		wantID string
+	}{
+		{"GO record first", []vulnerability{goRecord, otherRecord, ghsaRecord}, "GO-2024-0001"},
+		{"GHSA record first", []vulnerability{ghsaRecord, goRecord, otherRecord}, "GHSA-aaaa-bbbb-cccc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/query" {
+					http.NotFound(w, r)
+					return
+				}
+				var req queryRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				// Serve the records over two pages.
+				resp := queryResponse{Vulns: tt.vulns[:1], NextPageToken: "next"}
+				if req.PageToken == "next" {
+					resp = queryResponse{Vulns: tt.vulns[1:]}
+				}
+				json.NewEncoder(w).Encode(resp)
+			}))
+			defer server.Close()
+
+			client := &Client{BaseURL: server.URL, HTTPClient: server.Client()}
+			issues, err := client.Query(context.Background(), "example.com/m", "v1.0.0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(issues) != 2 {
+				t.Fatalf("got %d issues, want 2: %+v", len(issues), issues)
+			}
+
+			merged := issues[0]
+			if issues[1].ID == tt.wantID {
+				merged = issues[1]
+			}
+			if merged.ID != tt.wantID {
+				t.Errorf("merged issue ID = %q, want %q", merged.ID, tt.wantID)
+			}
+			if merged.Severity != "CRITICAL" {
+				t.Errorf("merged issue severity = %q, want CRITICAL from the GHSA alias", merged.Severity)
+			}
+			if merged.CVSSScore != 9.8 {
+				t.Errorf("merged issue CVSS score = %v, want 9.8", merged.CVSSScore)
+			}
+		})
+	}
+}
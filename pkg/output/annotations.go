@@ -0,0 +1,234 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"goviz/pkg/graph"
+)
+
+// Annotation is a finding attached to the go.mod line of the module it is
+// about, for the annotation formats of CI systems other than GitHub.
+type Annotation struct {
+	Check    string
+	Severity string
+	Message  string
+	Path     string
+	// Line is the require directive of the module; 0 when go.mod does not
+	// list it.
+	Line int
+	// Fingerprint identifies the finding across runs, so CI systems can
+	// tell new findings from ones already reported.
+	Fingerprint string
+}
+
+// SecurityAnnotations returns an annotation per security issue.
+// goModURI is the go.mod location relative to the repository root.
+func SecurityAnnotations(depGraph *graph.EnhancedDependencyGraph, goModURI string) []Annotation {
+	var annotations []Annotation
+	for _, finding := range SecurityFindings(depGraph) {
+		message := fmt.Sprintf("%s@%s: %s", finding.Module, finding.Version, finding.Description)
+		if finding.FixedIn != "" {
+			message += ". Fixed in: " + finding.FixedIn
+		}
+		annotations = append(annotations, Annotation{
+			Check:       finding.ID,
+			Severity:    finding.Severity,
+			Message:     message,
+			Path:        goModURI,
+			Line:        definedAtLine(depGraph, finding.Module),
+			Fingerprint: fingerprint(goModURI, finding.Key()),
+		})
+	}
+	return annotations
+}
+
+// LicenseAnnotations returns an annotation per dependency whose license is
+// unknown (MEDIUM) and per dependency on each side of a license conflict
+// (HIGH). goModURI is the go.mod location relative to the repository root.
+func LicenseAnnotations(depGraph *graph.EnhancedDependencyGraph, goModURI string) []Annotation {
+	var annotations []Annotation
+	for _, node := range depGraph.SortedNodes("name") {
+		if node.Name == depGraph.Root.Name || (node.License != "" && node.License != "Unknown") {
+			continue
+		}
+		annotations = append(annotations, Annotation{
+			Check:       "unknown-license",
+			Severity:    "MEDIUM",
+			Message:     fmt.Sprintf("%s@%s: license could not be determined; review it before distribution", node.Name, node.Version),
+			Path:        goModURI,
+			Line:        node.DefinedAtLine,
+			Fingerprint: fingerprint(goModURI, node.Name+"|unknown-license"),
+		})
+	}
+
+	for _, conflict := range depGraph.LicenseConflicts {
+		for side, modules := range conflict.Modules {
+			license, other := conflict.Licenses[side], conflict.Licenses[1-side]
+			for _, name := range modules {
+				// The main module is on a side with --project-license;
+				// the dependencies on the other side carry the finding.
+				if name == depGraph.Root.Name {
+					continue
+				}
+				annotations = append(annotations, Annotation{
+					Check:       "license-conflict",
+					Severity:    "HIGH",
+					Message:     fmt.Sprintf("%s: %s is incompatible with %s: %s", name, license, other, conflict.Reason),
+					Path:        goModURI,
+					Line:        definedAtLine(depGraph, name),
+					Fingerprint: fingerprint(goModURI, name+"|license-conflict|"+license+"|"+other),
+				})
+			}
+		}
+	}
+	return annotations
+}
+
+func definedAtLine(depGraph *graph.EnhancedDependencyGraph, name string) int {
+	if node, exists := depGraph.EnhancedNodes[name]; exists {
+		return node.DefinedAtLine
+	}
+	return 0
+}
+
+func fingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// GitLabIssue is an entry of a GitLab Code Quality report.
+type GitLabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    GitLabLocation `json:"location"`
+}
+
+type GitLabLocation struct {
+	Path  string      `json:"path"`
+	Lines GitLabLines `json:"lines"`
+}
+
+type GitLabLines struct {
+	Begin int `json:"begin"`
+}
+
+// GenerateGitLabCodeQuality writes the annotations as a GitLab Code Quality
+// report, for the codequality artifact of a job. Findings for modules go.mod
+// does not list are placed on its first line.
+func GenerateGitLabCodeQuality(annotations []Annotation, outputFile string) error {
+	issues := make([]GitLabIssue, 0, len(annotations))
+	for _, annotation := range annotations {
+		issues = append(issues, GitLabIssue{
+			Description: annotation.Message,
+			CheckName:   annotation.Check,
+			Fingerprint: annotation.Fingerprint,
+			Severity:    gitlabSeverity(annotation.Severity),
+			Location: GitLabLocation{
+				Path:  annotation.Path,
+				Lines: GitLabLines{Begin: max(annotation.Line, 1)},
+			},
+		})
+	}
+
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal Code Quality report: %w", err)
+	}
+
+	if outputFile == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if err := os.WriteFile(outputFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write Code Quality report: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "GitLab Code Quality report generated: %s\n", outputFile)
+	return nil
+}
+
+func gitlabSeverity(severity string) string {
+	switch severity {
+	case "CRITICAL":
+		return "critical"
+	case "HIGH":
+		return "major"
+	case "MEDIUM":
+		return "minor"
+	default:
+		return "info"
+	}
+}
+
+// GenerateAzureDevOps writes the annotations as Azure Pipelines
+// task.logissue logging commands, which the agent turns into errors and
+// warnings on the run and its pull request when they are printed to
+// stdout.
+func GenerateAzureDevOps(annotations []Annotation, outputFile string) error {
+	sorted := append([]Annotation(nil), annotations...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return graph.SeverityRank(sorted[i].Severity) > graph.SeverityRank(sorted[j].Severity)
+	})
+
+	var out io.Writer = os.Stdout
+	var file *os.File
+	if outputFile != "" {
+		var err error
+		file, err = os.Create(outputFile)
+		if err != nil {
+			return fmt.Errorf("failed to create Azure DevOps output file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	for _, annotation := range sorted {
+		issueType := "warning"
+		if sarifLevel(annotation.Severity) == "error" {
+			issueType = "error"
+		}
+		properties := []string{
+			"type=" + issueType,
+			"sourcepath=" + azureProperty(annotation.Path),
+		}
+		if annotation.Line > 0 {
+			properties = append(properties, "linenumber="+strconv.Itoa(annotation.Line))
+		}
+		properties = append(properties, "code="+azureProperty(annotation.Check))
+
+		if _, err := fmt.Fprintf(out, "##vso[task.logissue %s;]%s\n", strings.Join(properties, ";"), azureMessage(annotation.Message)); err != nil {
+			return fmt.Errorf("failed to write Azure DevOps output: %w", err)
+		}
+	}
+
+	if file == nil {
+		return nil
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write Azure DevOps output file: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Azure DevOps logging commands generated: %s\n", outputFile)
+	return nil
+}
+
+// azureMessage escapes the message of a logging command so it stays on
+// one line.
+func azureMessage(s string) string {
+	return strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// azureProperty escapes a logging command property value.
+func azureProperty(s string) string {
+	return strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A", ";", "%3B", "]", "%5D").Replace(s)
+}
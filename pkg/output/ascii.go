@@ -2,13 +2,66 @@ package output
 
 import (
 	"fmt"
+	"os"
+	"runtime"
 	"sort"
 	"strings"
 
 	"goviz/pkg/graph"
 )
 
-func GenerateASCIITree(depGraph *graph.DependencyGraph) error {
+// TreeStyle holds the connectors used to draw tree branches.
+type TreeStyle struct {
+	Branch   string
+	Last     string
+	Vertical string
+
+	// Chain joins the modules of a collapsed single-child chain.
+	Chain string
+}
+
+var (
+	// UnicodeTree draws branches with box-drawing characters.
+	UnicodeTree = TreeStyle{Branch: "├── ", Last: "└── ", Vertical: "│   ", Chain: " → "}
+
+	// ASCIITree draws branches with plain ASCII for terminals and log
+	// systems that garble box-drawing characters.
+	ASCIITree = TreeStyle{Branch: "|-- ", Last: "`-- ", Vertical: "|   ", Chain: " -> "}
+)
+
+// DetectTreeStyle returns UnicodeTree unless the environment is unlikely to
+// display it: a locale naming a charset other than UTF-8, or a Windows
+// console other than Windows Terminal.
+func DetectTreeStyle() TreeStyle {
+	if runtime.GOOS == "windows" && os.Getenv("WT_SESSION") == "" {
+		return ASCIITree
+	}
+
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		locale := os.Getenv(key)
+		if locale == "" {
+			continue
+		}
+		_, charset, found := strings.Cut(locale, ".")
+		charset, _, _ = strings.Cut(charset, "@")
+		charset = strings.ToLower(strings.ReplaceAll(charset, "-", ""))
+		if found && charset != "utf8" {
+			return ASCIITree
+		}
+		break
+	}
+
+	return UnicodeTree
+}
+
+// DefaultMaxTreeDepth is the depth at which GenerateASCIITree stops
+// descending unless told otherwise.
+const DefaultMaxTreeDepth = 20
+
+// GenerateASCIITree prints the dependency tree of depGraph. Branches deeper
+// than maxDepth levels are cut off with a marker and a warning on stderr,
+// so a pathological graph cannot flood the terminal; 0 means no limit.
+func GenerateASCIITree(depGraph *graph.DependencyGraph, style TreeStyle, maxDepth int) error {
 	fmt.Printf("Dependency Graph for: %s\n", depGraph.ModuleName)
 
 	if depGraph.ModuleGoVersion != "" {
@@ -23,7 +76,7 @@ func GenerateASCIITree(depGraph *graph.DependencyGraph) error {
 
 	directDeps := depGraph.GetDirectDependencies()
 	if len(directDeps) == 0 {
-		fmt.Println("└── (no dependencies)")
+		fmt.Printf("%s(no dependencies)\n", style.Last)
 		return nil
 	}
 
@@ -31,9 +84,13 @@ func GenerateASCIITree(depGraph *graph.DependencyGraph) error {
 		return directDeps[i].Name < directDeps[j].Name
 	})
 
+	truncated := 0
 	for i, dep := range directDeps {
 		isLast := i == len(directDeps)-1
-		printNode(dep, "", isLast)
+		printNode(dep, "", isLast, style, 1, maxDepth, &truncated)
+	}
+	if truncated > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️  Tree truncated at depth %d (%d branches cut); raise --max-depth to see more\n", maxDepth, truncated)
 	}
 
 	allDeps := depGraph.GetAllDependencies()
@@ -53,35 +110,116 @@ func GenerateASCIITree(depGraph *graph.DependencyGraph) error {
 
 		for i, dep := range indirectDeps {
 			isLast := i == len(indirectDeps)-1
-			var prefix string
+			prefix := style.Branch
 			if isLast {
-				prefix = "└── "
-			} else {
-				prefix = "├── "
+				prefix = style.Last
 			}
-			fmt.Printf("%s%s (%s)\n", prefix, dep.Name, dep.Version)
+			fmt.Printf("%s%s\n", prefix, describeNode(dep))
 		}
 	}
 
 	return nil
 }
 
-func printNode(node *graph.Node, prefix string, isLast bool) {
+func printNode(node *graph.Node, prefix string, isLast bool, style TreeStyle, depth, maxDepth int, truncated *int) {
+	var connector, childPrefix string
+
+	if isLast {
+		connector = style.Last
+		childPrefix = prefix + "    "
+	} else {
+		connector = style.Branch
+		childPrefix = prefix + style.Vertical
+	}
+
+	fmt.Printf("%s%s%s\n", prefix, connector, describeNode(node))
+
+	if len(node.Children) == 0 {
+		return
+	}
+	if maxDepth > 0 && depth >= maxDepth {
+		fmt.Printf("%s%s... (%d more, truncated at depth %d)\n", childPrefix, style.Last, len(node.Children), maxDepth)
+		*truncated++
+		return
+	}
+
+	for i, child := range node.Children {
+		isChildLast := i == len(node.Children)-1
+		printNode(child, childPrefix, isChildLast, style, depth+1, maxDepth, truncated)
+	}
+}
+
+// GenerateCollapsedTree prints the dependency tree of depGraph along its
+// requirement edges, with every run of modules that each require a single
+// module, such as A → B → C → D, on one line; the tree only branches where
+// a module has several requirements. Modules already shown are marked
+// instead of expanded again. Depth is limited as in GenerateASCIITree,
+// counting every module of a chain.
+func GenerateCollapsedTree(depGraph *graph.EnhancedDependencyGraph, style TreeStyle, maxDepth int) error {
+	fmt.Printf("Dependency Graph for: %s\n", depGraph.ModuleName)
+
+	if depGraph.ModuleGoVersion != "" {
+		fmt.Printf("Go Version: %s\n", depGraph.ModuleGoVersion)
+	}
+
+	direct, indirect := depGraph.GetDependencyCount()
+	fmt.Printf("Dependencies: %d direct, %d indirect\n", direct, indirect)
+	fmt.Println()
+
+	fmt.Printf("%s (main)\n", depGraph.Root.Name)
+
+	root := BuildDependencyTree(depGraph)
+	if len(root.Children) == 0 {
+		fmt.Printf("%s(no dependencies)\n", style.Last)
+		return nil
+	}
+
+	truncated := 0
+	for i, child := range root.Children {
+		isLast := i == len(root.Children)-1
+		printChain(child, "", isLast, style, 1, maxDepth, &truncated)
+	}
+	if truncated > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️  Tree truncated at depth %d (%d branches cut); raise --max-depth to see more\n", maxDepth, truncated)
+	}
+	return nil
+}
+
+// printChain is the printNode of GenerateCollapsedTree: it follows node
+// down while each module has exactly one child, prints the run on one line
+// and branches at the first module with several children.
+func printChain(node *TreeNode, prefix string, isLast bool, style TreeStyle, depth, maxDepth int, truncated *int) {
 	var connector, childPrefix string
 
 	if isLast {
-		connector = "└── "
+		connector = style.Last
 		childPrefix = prefix + "    "
 	} else {
-		connector = "├── "
-		childPrefix = prefix + "│   "
+		connector = style.Branch
+		childPrefix = prefix + style.Vertical
+	}
+
+	chain := []string{describeTreeNode(node)}
+	for len(node.Children) == 1 && (maxDepth == 0 || depth < maxDepth) {
+		node = node.Children[0]
+		depth++
+		chain = append(chain, describeTreeNode(node))
 	}
 
-	fmt.Printf("%s%s%s (%s)\n", prefix, connector, node.Name, node.Version)
+	fmt.Printf("%s%s%s\n", prefix, connector, strings.Join(chain, style.Chain))
+
+	if len(node.Children) == 0 {
+		return
+	}
+	if maxDepth > 0 && depth >= maxDepth {
+		fmt.Printf("%s%s... (%d more, truncated at depth %d)\n", childPrefix, style.Last, len(node.Children), maxDepth)
+		*truncated++
+		return
+	}
 
 	for i, child := range node.Children {
 		isChildLast := i == len(node.Children)-1
-		printNode(child, childPrefix, isChildLast)
+		printChain(child, childPrefix, isChildLast, style, depth+1, maxDepth, truncated)
 	}
 }
 
@@ -108,7 +246,7 @@ func GenerateASCIITreeCompact(depGraph *graph.DependencyGraph) error {
 		})
 
 		for _, dep := range directDeps {
-			fmt.Printf("  • %s (%s)\n", dep.Name, dep.Version)
+			fmt.Printf("  • %s\n", describeNode(dep))
 		}
 		fmt.Println()
 	}
@@ -121,11 +259,7 @@ func GenerateASCIITreeCompact(depGraph *graph.DependencyGraph) error {
 
 		grouped := make(map[string][]*graph.Node)
 		for _, dep := range indirectDeps {
-			parts := strings.Split(dep.Name, "/")
-			key := parts[0]
-			if len(parts) > 1 {
-				key = strings.Join(parts[:2], "/")
-			}
+			key := graph.OrgPrefix(dep.Name)
 			grouped[key] = append(grouped[key], dep)
 		}
 
@@ -138,11 +272,11 @@ func GenerateASCIITreeCompact(depGraph *graph.DependencyGraph) error {
 		for _, key := range keys {
 			deps := grouped[key]
 			if len(deps) == 1 {
-				fmt.Printf("  • %s (%s)\n", deps[0].Name, deps[0].Version)
+				fmt.Printf("  • %s\n", describeNode(deps[0]))
 			} else {
 				fmt.Printf("  • %s/... (%d packages)\n", key, len(deps))
 				for _, dep := range deps {
-					fmt.Printf("    - %s (%s)\n", dep.Name, dep.Version)
+					fmt.Printf("    - %s\n", describeNode(dep))
 				}
 			}
 		}
@@ -150,3 +284,29 @@ func GenerateASCIITreeCompact(depGraph *graph.DependencyGraph) error {
 
 	return nil
 }
+
+// describeNode formats a module as "path (version)", followed by its go.mod
+// comment if it has one.
+func describeNode(node *graph.Node) string {
+	s := fmt.Sprintf("%s (%s)", node.Name, node.Version)
+	if node.Comment != "" {
+		s += " // " + node.Comment
+	}
+	return s
+}
+
+// describeTreeNode formats a tree module like describeNode, marking modules
+// expanded elsewhere in the tree and requirements that close a cycle.
+func describeTreeNode(node *TreeNode) string {
+	s := fmt.Sprintf("%s (%s)", node.Name, node.Version)
+	if node.Comment != "" {
+		s += " // " + node.Comment
+	}
+	switch {
+	case node.Cycle:
+		s += " (cycle)"
+	case node.Deduped:
+		s += " (*)"
+	}
+	return s
+}
@@ -0,0 +1,105 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"goviz/pkg/graph"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CompareReport lists how the requirements of a module change between two
+// of its versions.
+type CompareReport struct {
+	Metadata ReportMetadata       `json:"metadata" yaml:"metadata"`
+	Module   string               `json:"module" yaml:"module"`
+	From     ComparedVersion      `json:"from" yaml:"from"`
+	To       ComparedVersion      `json:"to" yaml:"to"`
+	Resolved bool                 `json:"resolved" yaml:"resolved"`
+	Added    []graph.ModuleChange `json:"added" yaml:"added"`
+	Removed  []graph.ModuleChange `json:"removed" yaml:"removed"`
+	Changed  []graph.ModuleChange `json:"changed" yaml:"changed"`
+
+	// Downgraded lists the changes to an older version, which are not
+	// repeated in Changed.
+	Downgraded []graph.ModuleChange `json:"downgraded" yaml:"downgraded"`
+}
+
+// ComparedVersion is one side of a CompareReport.
+type ComparedVersion struct {
+	Version      string `json:"version" yaml:"version"`
+	GoVersion    string `json:"go_version,omitempty" yaml:"go_version,omitempty"`
+	Dependencies int    `json:"dependencies" yaml:"dependencies"`
+}
+
+// NewCompareReport splits changes into added, removed, changed and
+// downgraded modules.
+func NewCompareReport(modulePath string, from, to ComparedVersion, resolved bool, changes []graph.ModuleChange, opts ReportOptions) CompareReport {
+	report := CompareReport{
+		Metadata: newReportMetadata(opts),
+		Module:   modulePath,
+		From:     from,
+		To:       to,
+		Resolved: resolved,
+		Added:    make([]graph.ModuleChange, 0),
+		Removed:  make([]graph.ModuleChange, 0),
+		Changed:  make([]graph.ModuleChange, 0),
+
+		Downgraded: make([]graph.ModuleChange, 0),
+	}
+
+	for _, change := range changes {
+		switch {
+		case change.OldVersion == "":
+			report.Added = append(report.Added, change)
+		case change.NewVersion == "":
+			report.Removed = append(report.Removed, change)
+		case change.Downgrade:
+			report.Downgraded = append(report.Downgraded, change)
+		default:
+			report.Changed = append(report.Changed, change)
+		}
+	}
+
+	return report
+}
+
+func GenerateCompareJSON(report CompareReport, outputFile string) error {
+	jsonData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	if outputFile == "" {
+		fmt.Print(string(jsonData))
+		return nil
+	}
+
+	if err := os.WriteFile(outputFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write JSON file: %w", err)
+	}
+
+	fmt.Printf("JSON comparison generated: %s\n", outputFile)
+	return nil
+}
+
+func GenerateCompareYAML(report CompareReport, outputFile string) error {
+	yamlData, err := yaml.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+
+	if outputFile == "" {
+		fmt.Print(string(yamlData))
+		return nil
+	}
+
+	if err := os.WriteFile(outputFile, yamlData, 0644); err != nil {
+		return fmt.Errorf("failed to write YAML file: %w", err)
+	}
+
+	fmt.Printf("YAML comparison generated: %s\n", outputFile)
+	return nil
+}
@@ -0,0 +1,50 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"goviz/pkg/graph"
+)
+
+// DependencyCounts are the go.mod requirement counts printed by
+// analyze --counts.
+type DependencyCounts struct {
+	Module   string `json:"module"`
+	Direct   int    `json:"direct"`
+	Indirect int    `json:"indirect"`
+	Total    int    `json:"total"`
+}
+
+// GenerateCounts writes the requirement counts of depGraph as one line of
+// text or compact JSON.
+func GenerateCounts(depGraph *graph.DependencyGraph, format, outputFile string) error {
+	direct, indirect := depGraph.GetDependencyCount()
+	counts := DependencyCounts{
+		Module:   depGraph.ModuleName,
+		Direct:   direct,
+		Indirect: indirect,
+		Total:    direct + indirect,
+	}
+
+	var line string
+	if format == "json" {
+		data, err := json.Marshal(counts)
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		line = string(data)
+	} else {
+		line = fmt.Sprintf("%s: %d direct, %d indirect, %d total", counts.Module, counts.Direct, counts.Indirect, counts.Total)
+	}
+
+	if outputFile == "" {
+		fmt.Println(line)
+		return nil
+	}
+	if err := os.WriteFile(outputFile, []byte(line+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write counts: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"goviz/pkg/graph"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Recommendation kinds, so automation can pick the actions it handles.
+const (
+	RecommendUpgrade       = "upgrade"
+	RecommendMajorUpgrade  = "major_upgrade"
+	RecommendPinRelease    = "pin_release"
+	RecommendUnavailable   = "unavailable_version"
+	RecommendRemove        = "remove"
+	RecommendRequireDirect = "require_direct"
+	RecommendGoDirective   = "go_directive"
+	RecommendVerifyHash    = "verify_hash"
+)
+
+// DoctorReport is the JSON and YAML form of the doctor health report.
+type DoctorReport struct {
+	Metadata        ReportMetadata   `json:"metadata" yaml:"metadata"`
+	Module          string           `json:"module" yaml:"module"`
+	Dependencies    int              `json:"dependencies" yaml:"dependencies"`
+	Health          DoctorHealth     `json:"health" yaml:"health"`
+	Recommendations []Recommendation `json:"recommendations" yaml:"recommendations"`
+	Deprecated      []Deprecation    `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Overlaps        []graph.Overlap  `json:"overlaps,omitempty" yaml:"overlaps,omitempty"`
+	Provenance      *Provenance      `json:"provenance,omitempty" yaml:"provenance,omitempty"`
+	Warnings        []graph.Warning  `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// DoctorHealth is the health overview of a DoctorReport.
+type DoctorHealth struct {
+	Score          float64 `json:"score" yaml:"score"`
+	WellMaintained int     `json:"well_maintained" yaml:"well_maintained"`
+	Outdated       int     `json:"outdated" yaml:"outdated"`
+	Stale          int     `json:"stale" yaml:"stale"`
+}
+
+// Deprecation is a dependency deprecated upstream, with its notice.
+type Deprecation struct {
+	Module  string `json:"module" yaml:"module"`
+	Version string `json:"version" yaml:"version"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// Provenance is the checksum database coverage of the dependencies, set
+// when doctor ran with --provenance. MismatchModules and MissingModules
+// list module@version.
+type Provenance struct {
+	Database                 string `json:"database" yaml:"database"`
+	graph.ProvenanceCoverage `yaml:",inline"`
+	MismatchModules          []string `json:"mismatch_modules,omitempty" yaml:"mismatch_modules,omitempty"`
+	MissingModules           []string `json:"missing_modules,omitempty" yaml:"missing_modules,omitempty"`
+}
+
+// Recommendation is one action the doctor suggests. Command can be run as
+// is from the module root; Rationale explains why it is suggested.
+type Recommendation struct {
+	Kind             string `json:"kind" yaml:"kind"`
+	Module           string `json:"module,omitempty" yaml:"module,omitempty"`
+	CurrentVersion   string `json:"current_version,omitempty" yaml:"current_version,omitempty"`
+	SuggestedVersion string `json:"suggested_version,omitempty" yaml:"suggested_version,omitempty"`
+	Command          string `json:"command" yaml:"command"`
+	Rationale        string `json:"rationale" yaml:"rationale"`
+}
+
+func NewDoctorReport(depGraph *graph.EnhancedDependencyGraph, health DoctorHealth, recommendations []Recommendation, opts ReportOptions) DoctorReport {
+	if recommendations == nil {
+		recommendations = make([]Recommendation, 0)
+	}
+	var deprecated []Deprecation
+	for _, node := range depGraph.DeprecatedNodes() {
+		deprecated = append(deprecated, Deprecation{Module: node.Name, Version: node.Version, Message: node.Deprecated})
+	}
+	var provenance *Provenance
+	if depGraph.ProvenanceDatabase != "" {
+		provenance = &Provenance{Database: depGraph.ProvenanceDatabase, ProvenanceCoverage: depGraph.ProvenanceCoverage()}
+		for _, node := range depGraph.ProvenanceNodes(graph.ProvenanceMismatch) {
+			provenance.MismatchModules = append(provenance.MismatchModules, node.Name+"@"+node.Version)
+		}
+		for _, node := range depGraph.ProvenanceNodes(graph.ProvenanceMissing) {
+			provenance.MissingModules = append(provenance.MissingModules, node.Name+"@"+node.Version)
+		}
+	}
+	return DoctorReport{
+		Metadata:        newReportMetadata(opts),
+		Module:          depGraph.ModuleName,
+		Dependencies:    len(depGraph.AllNodes) - 1,
+		Health:          health,
+		Recommendations: recommendations,
+		Deprecated:      deprecated,
+		Overlaps:        depGraph.Overlaps,
+		Provenance:      provenance,
+		Warnings:        depGraph.SortedWarnings(),
+	}
+}
+
+func GenerateDoctorJSON(report DoctorReport, outputFile string) error {
+	jsonData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	if outputFile == "" {
+		fmt.Print(string(jsonData))
+		return nil
+	}
+
+	if err := os.WriteFile(outputFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write JSON file: %w", err)
+	}
+
+	fmt.Printf("JSON health report generated: %s\n", outputFile)
+	return nil
+}
+
+func GenerateDoctorYAML(report DoctorReport, outputFile string) error {
+	yamlData, err := yaml.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+
+	if outputFile == "" {
+		fmt.Print(string(yamlData))
+		return nil
+	}
+
+	if err := os.WriteFile(outputFile, yamlData, 0644); err != nil {
+		return fmt.Errorf("failed to write YAML file: %w", err)
+	}
+
+	fmt.Printf("YAML health report generated: %s\n", outputFile)
+	return nil
+}
@@ -10,7 +10,10 @@ import (
 	"github.com/awalterschulze/gographviz"
 )
 
-func GenerateDOT(depGraph *graph.DependencyGraph, outputFile string) error {
+// GenerateDOT writes the DOT graph of depGraph with the layout direction
+// and theme colors of opts.
+func GenerateDOT(depGraph *graph.DependencyGraph, outputFile string, opts DOTOptions) error {
+	theme := opts.theme()
 
 	graphAst, err := gographviz.ParseString(`digraph G {}`)
 	if err != nil {
@@ -29,33 +32,30 @@ func GenerateDOT(depGraph *graph.DependencyGraph, outputFile string) error {
 		return fmt.Errorf("failed to set graph direction: %w", err)
 	}
 
-	if err := graph.AddAttr("DependencyGraph", "rankdir", "TB"); err != nil {
+	if err := graph.AddAttr("DependencyGraph", "rankdir", opts.rankDir()); err != nil {
 		return fmt.Errorf("failed to add rankdir attribute: %w", err)
 	}
-	if err := graph.AddAttr("DependencyGraph", "node", "[shape=box,style=filled]"); err != nil {
-		return fmt.Errorf("failed to add node attribute: %w", err)
-	}
 
-	rootNodeName := sanitizeNodeName(depGraph.Root.Name)
+	rootNodeName := dotNodeID(depGraph.Root.Name)
 	if err := graph.AddNode("DependencyGraph", rootNodeName, map[string]string{
 		"label":     fmt.Sprintf("\"%s\\n(main)\"", depGraph.Root.Name),
-		"fillcolor": "lightblue",
+		"fillcolor": dotColor(theme.Main),
 		"style":     "filled",
 	}); err != nil {
 		return fmt.Errorf("failed to add root node: %w", err)
 	}
 
 	for _, node := range depGraph.GetAllDependencies() {
-		nodeName := sanitizeNodeName(node.Name)
-		color := "lightgray"
+		nodeName := dotNodeID(node.Name)
+		color := theme.Indirect
 		if node.Direct {
-			color = "lightgreen"
+			color = theme.Direct
 		}
 
 		label := fmt.Sprintf("\"%s\\n%s\"", node.Name, node.Version)
 		if err := graph.AddNode("DependencyGraph", nodeName, map[string]string{
 			"label":     label,
-			"fillcolor": color,
+			"fillcolor": dotColor(color),
 			"style":     "filled",
 		}); err != nil {
 			return fmt.Errorf("failed to add node %s: %w", node.Name, err)
@@ -79,10 +79,18 @@ func GenerateDOT(depGraph *graph.DependencyGraph, outputFile string) error {
 	return nil
 }
 
-func sanitizeNodeName(name string) string {
+// dotNodeID returns the DOT node ID of a module: its path, quoted. Using
+// the path itself keeps modules such as a/b and a-b apart.
+func dotNodeID(name string) string {
+	return "\"" + escapeDOT(name) + "\""
+}
 
-	sanitized := strings.ReplaceAll(name, "/", "_")
-	sanitized = strings.ReplaceAll(sanitized, ".", "_")
-	sanitized = strings.ReplaceAll(sanitized, "-", "_")
-	return fmt.Sprintf("\"%s\"", sanitized)
+// dotNodes maps the DOT node ID of every module in depGraph, without the
+// quotes, to its node.
+func dotNodes(depGraph *graph.EnhancedDependencyGraph) map[string]*graph.EnhancedNode {
+	nodes := make(map[string]*graph.EnhancedNode, len(depGraph.EnhancedNodes))
+	for name, node := range depGraph.EnhancedNodes {
+		nodes[strings.Trim(dotNodeID(name), `"`)] = node
+	}
+	return nodes
 }
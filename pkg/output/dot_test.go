@@ -0,0 +1,158 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"goviz/pkg/graph"
+	"goviz/pkg/parser"
+
+	"github.com/awalterschulze/gographviz"
+)
+
+func buildTestGraph(t *testing.T, goMod string) *graph.EnhancedDependencyGraph {
+	t.Helper()
+	modFile, err := parser.ParseGoModReader(strings.NewReader(goMod), "go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	depGraph, err := graph.BuildEnhancedDependencyGraph(modFile, filepath.Join(t.TempDir(), "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return depGraph
+}
+
+func TestEnhanceDOTContentMatchesExactNode(t *testing.T) {
+	depGraph := buildTestGraph(t, `module example.com/app
+
+go 1.24
+
+require (
+	github.com/a/b v1.0.0
+	github.com/a/b/v2 v2.0.0
+)
+`)
+	depGraph.EnhancedNodes["github.com/a/b"].License = "MIT"
+	depGraph.EnhancedNodes["github.com/a/b/v2"].License = "Apache-2.0"
+
+	dotFile := filepath.Join(t.TempDir(), "graph.dot")
+	if err := GenerateDOT(depGraph.DependencyGraph, dotFile, DOTOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(dotFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range strings.Split(enhanceDOTContent(string(content), depGraph, DOTOptions{}), "\n") {
+		switch {
+		case strings.Contains(line, `github.com/a/b\nv1.0.0`):
+			if !strings.Contains(line, `\nMIT"`) {
+				t.Errorf("github.com/a/b has the wrong license: %s", line)
+			}
+		case strings.Contains(line, `github.com/a/b/v2\nv2.0.0`):
+			if !strings.Contains(line, `\nApache-2.0"`) {
+				t.Errorf("github.com/a/b/v2 has the wrong license: %s", line)
+			}
+		}
+	}
+}
+
+func TestStreamingDOTMatchesGographviz(t *testing.T) {
+	var goMod strings.Builder
+	goMod.WriteString("module example.com/app\n\ngo 1.24\n\nrequire (\n")
+	goMod.WriteString("\tgithub.com/a/b v1.0.0\n\tgithub.com/a-b v1.0.0\n\tgithub.com/a.b v1.0.0 // indirect\n")
+	for i := 0; i < StreamingDOTThreshold; i++ {
+		comment := ""
+		if i%3 == 0 {
+			comment = " // indirect"
+		}
+		fmt.Fprintf(&goMod, "\texample.com/mod%04d v1.%d.0%s\n", i, i%7, comment)
+	}
+	goMod.WriteString(")\n")
+	depGraph := buildTestGraph(t, goMod.String())
+	if len(depGraph.AllNodes) <= StreamingDOTThreshold {
+		t.Fatalf("got %d nodes, want more than %d", len(depGraph.AllNodes), StreamingDOTThreshold)
+	}
+	depGraph.EnhancedNodes["github.com/a/b"].License = "MIT"
+	depGraph.EnhancedNodes["github.com/a/b"].RepoURL = "https://github.com/a/b"
+	depGraph.EnhancedNodes["github.com/a-b"].License = "BSD-3-Clause"
+	depGraph.EnhancedNodes["github.com/a-b"].SecurityIssues = []graph.SecurityIssue{{ID: "GO-2024-0001"}}
+
+	dir := t.TempDir()
+	streamed := filepath.Join(dir, "streamed.dot")
+	if err := GenerateEnhancedDOT(depGraph, streamed, DOTOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	built := filepath.Join(dir, "built.dot")
+	if err := GenerateDOT(depGraph.DependencyGraph, built, DOTOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(built)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(built, []byte(enhanceDOTContent(string(content), depGraph, DOTOptions{})), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	want := readDOTGraph(t, built)
+	got := readDOTGraph(t, streamed)
+	if len(got.nodes) != len(depGraph.AllNodes)+4 {
+		t.Errorf("streamed %d nodes, want %d modules and 4 legend entries", len(got.nodes), len(depGraph.AllNodes))
+	}
+	for id, attrs := range want.nodes {
+		if !reflect.DeepEqual(got.nodes[id], attrs) {
+			t.Errorf("node %s: streamed %v, want %v", id, got.nodes[id], attrs)
+		}
+	}
+	for id := range got.nodes {
+		if _, ok := want.nodes[id]; !ok {
+			t.Errorf("streamed unexpected node %s", id)
+		}
+	}
+	if !reflect.DeepEqual(got.edges, want.edges) {
+		t.Errorf("streamed %d edges, want %d", len(got.edges), len(want.edges))
+	}
+}
+
+type dotGraph struct {
+	nodes map[string]map[string]string
+	edges map[[2]string]bool
+}
+
+// readDOTGraph parses a DOT file into the attributes of its nodes and its
+// edges, so that graphs can be compared regardless of formatting.
+func readDOTGraph(t *testing.T, dotFile string) dotGraph {
+	t.Helper()
+	content, err := os.ReadFile(dotFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ast, err := gographviz.ParseString(string(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed := gographviz.NewGraph()
+	if err := gographviz.Analyse(ast, parsed); err != nil {
+		t.Fatal(err)
+	}
+
+	g := dotGraph{nodes: make(map[string]map[string]string), edges: make(map[[2]string]bool)}
+	for _, node := range parsed.Nodes.Nodes {
+		attrs := make(map[string]string)
+		for key, value := range node.Attrs {
+			attrs[string(key)] = value
+		}
+		g.nodes[node.Name] = attrs
+	}
+	for _, edge := range parsed.Edges.Edges {
+		g.edges[[2]string{edge.Src, edge.Dst}] = true
+	}
+	return g
+}
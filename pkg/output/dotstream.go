@@ -0,0 +1,227 @@
+package output
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"goviz/pkg/graph"
+)
+
+// StreamingDOTThreshold is the node count above which DOT output is written
+// directly to the destination instead of being built in memory by gographviz.
+const StreamingDOTThreshold = 1000
+
+// DOTOptions controls DOT, PNG and SVG output.
+type DOTOptions struct {
+	// Highlight marks the modules and edges on the paths to a target
+	// module; all other modules are dimmed.
+	Highlight *graph.PathSet
+	// PathOnly omits the modules and edges not on a highlighted path.
+	PathOnly bool
+	// RankDir is the Graphviz layout direction, one of RankDirs; empty
+	// means top to bottom.
+	RankDir string
+	// Theme names one of DOTThemes and NodeShape one of NodeShapes; empty
+	// means the light theme with boxes.
+	Theme     string
+	NodeShape string
+}
+
+// RankDirs are the layout directions Graphviz accepts for rankdir: top to
+// bottom, left to right, bottom to top and right to left.
+var RankDirs = []string{"TB", "LR", "BT", "RL"}
+
+func (o DOTOptions) rankDir() string {
+	if o.RankDir == "" {
+		return "TB"
+	}
+	return o.RankDir
+}
+
+const (
+	highlightColor = "crimson"
+	dimmedColor    = "gray60"
+)
+
+func writeStreamingDOT(w io.Writer, depGraph *graph.EnhancedDependencyGraph, opts DOTOptions) error {
+	bw := bufio.NewWriter(w)
+	paths := opts.Highlight
+	onPath := func(name string) bool {
+		return paths == nil || paths.Nodes[name]
+	}
+
+	theme := opts.theme()
+	fmt.Fprintln(bw, "digraph DependencyGraph {")
+	for _, line := range dotHeader(opts) {
+		fmt.Fprintln(bw, "    "+line)
+	}
+	fmt.Fprintf(bw, "    rankdir=%s;\n", opts.rankDir())
+	for _, line := range dotLegend(theme) {
+		fmt.Fprintln(bw, "    "+line)
+	}
+	if paths != nil {
+		fmt.Fprintf(bw, "        legend_path [label=\"Path to %s\", fillcolor=%s, style=filled, color=%s, penwidth=3];\n", escapeDOT(paths.Target), dotColor(theme.Background), highlightColor)
+	}
+	fmt.Fprintln(bw, "    }")
+
+	rootNodeName := dotNodeID(depGraph.Root.Name)
+	fmt.Fprintf(bw, "    %s [label=\"%s\\n(main)\", fillcolor=%s%s];\n", rootNodeName, escapeDOT(depGraph.Root.Name), dotColor(theme.Main), highlightAttrs(paths, depGraph.Root.Name))
+
+	deps := depGraph.GetAllDependencies()
+	sort.Slice(deps, func(i, j int) bool {
+		return deps[i].Name < deps[j].Name
+	})
+
+	for _, node := range deps {
+		if opts.PathOnly && !onPath(node.Name) {
+			continue
+		}
+		fmt.Fprintf(bw, "    %s [label=\"%s\", fillcolor=%s%s%s];\n",
+			dotNodeID(node.Name), streamingNodeLabel(node, depGraph), dotColor(streamingNodeColor(node, depGraph, theme)), streamingNodeURL(node, depGraph), highlightAttrs(paths, node.Name))
+	}
+
+	for _, node := range deps {
+		if !node.Direct || (paths != nil && paths.Edges[[2]string{depGraph.Root.Name, node.Name}]) {
+			continue
+		}
+		switch {
+		case paths == nil:
+			fmt.Fprintf(bw, "    %s -> %s;\n", rootNodeName, dotNodeID(node.Name))
+		case !opts.PathOnly:
+			fmt.Fprintf(bw, "    %s -> %s [color=%s];\n", rootNodeName, dotNodeID(node.Name), dimmedColor)
+		}
+	}
+
+	if paths != nil {
+		var edges [][2]string
+		for edge := range paths.Edges {
+			edges = append(edges, edge)
+		}
+		sort.Slice(edges, func(i, j int) bool {
+			if edges[i][0] != edges[j][0] {
+				return edges[i][0] < edges[j][0]
+			}
+			return edges[i][1] < edges[j][1]
+		})
+		for _, edge := range edges {
+			fmt.Fprintf(bw, "    %s -> %s [color=%s, penwidth=2];\n", dotNodeID(edge[0]), dotNodeID(edge[1]), highlightColor)
+		}
+	}
+
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
+
+// highlightAttrs returns the extra node attributes for a highlighted graph:
+// a thick border on the path, dimmed text elsewhere.
+func highlightAttrs(paths *graph.PathSet, name string) string {
+	switch {
+	case paths == nil:
+		return ""
+	case paths.Nodes[name]:
+		return fmt.Sprintf(", color=%s, penwidth=3", highlightColor)
+	default:
+		return fmt.Sprintf(", color=%s, fontcolor=%s", dimmedColor, dimmedColor)
+	}
+}
+
+func generateStreamingDOTFile(depGraph *graph.EnhancedDependencyGraph, outputFile string, opts DOTOptions) error {
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create DOT file: %w", err)
+	}
+
+	if err := writeStreamingDOT(file, depGraph, opts); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write DOT file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write DOT file: %w", err)
+	}
+
+	if opts.Highlight != nil {
+		fmt.Printf("DOT file generated: %s (highlighting %d modules on the path to %s)\n", outputFile, len(opts.Highlight.Nodes), opts.Highlight.Target)
+	} else {
+		fmt.Printf("DOT file generated: %s (streamed, %d nodes)\n", outputFile, len(depGraph.AllNodes))
+	}
+	fmt.Printf("To visualize: dot -Tpng %s -o depgraph.png\n", outputFile)
+
+	return nil
+}
+
+func streamingNodeLabel(node *graph.Node, depGraph *graph.EnhancedDependencyGraph) string {
+	label := escapeDOT(node.Name) + "\\n" + escapeDOT(node.Version)
+
+	enhancedNode, exists := depGraph.EnhancedNodes[node.Name]
+	if !exists || enhancedNode.License == "" {
+		return label
+	}
+
+	label += "\\n" + escapeDOT(enhancedNode.License)
+	if len(enhancedNode.SecurityIssues) > 0 {
+		label += "\\n⚠ Security Issues"
+	}
+	if len(enhancedNode.Conflicts) > 0 {
+		label += "\\n⚡ Version Conflicts"
+	}
+	return label
+}
+
+func streamingNodeURL(node *graph.Node, depGraph *graph.EnhancedDependencyGraph) string {
+	if enhancedNode, exists := depGraph.EnhancedNodes[node.Name]; exists && enhancedNode.RepoURL != "" {
+		return fmt.Sprintf(", URL=\"%s\"", escapeDOT(enhancedNode.RepoURL))
+	}
+	return ""
+}
+
+func streamingNodeColor(node *graph.Node, depGraph *graph.EnhancedDependencyGraph, theme DOTTheme) string {
+	hasIssues := false
+	if enhancedNode, exists := depGraph.EnhancedNodes[node.Name]; exists {
+		hasIssues = len(enhancedNode.SecurityIssues) > 0
+	}
+
+	switch {
+	case node.Direct && hasIssues:
+		return theme.DirectIssue
+	case node.Direct:
+		return theme.Direct
+	case hasIssues:
+		return theme.IndirectIssue
+	default:
+		return theme.Indirect
+	}
+}
+
+// dotHeader returns the graph, node and edge defaults of a themed graph.
+func dotHeader(opts DOTOptions) []string {
+	theme := opts.theme()
+	return []string{
+		fmt.Sprintf("graph [fontname=\"Arial\", fontsize=12, bgcolor=%s, fontcolor=%s];", dotColor(theme.Background), dotColor(theme.Font)),
+		fmt.Sprintf("node [fontname=\"Arial\", fontsize=10, shape=%s, style=filled, color=%s, fontcolor=%s];", opts.nodeShape(), dotColor(theme.Border), dotColor(theme.Font)),
+		fmt.Sprintf("edge [fontname=\"Arial\", fontsize=8, color=%s, fontcolor=%s];", dotColor(theme.Edge), dotColor(theme.Font)),
+	}
+}
+
+// dotLegend opens the legend cluster of a themed graph; the caller may add
+// entries before closing it.
+func dotLegend(theme DOTTheme) []string {
+	return []string{
+		"subgraph cluster_legend {",
+		"    label=\"Legend\";",
+		"    style=filled;",
+		fmt.Sprintf("    color=%s;", dotColor(theme.Legend)),
+		fmt.Sprintf("    legend_main [label=\"Main Module\", fillcolor=%s, style=filled];", dotColor(theme.Main)),
+		fmt.Sprintf("    legend_direct [label=\"Direct Dependency\", fillcolor=%s, style=filled];", dotColor(theme.Direct)),
+		fmt.Sprintf("    legend_indirect [label=\"Indirect Dependency\", fillcolor=%s, style=filled];", dotColor(theme.Indirect)),
+		fmt.Sprintf("    legend_security [label=\"Security Issue\", fillcolor=%s, style=filled];", dotColor(theme.DirectIssue)),
+	}
+}
+
+func escapeDOT(s string) string {
+	return strings.ReplaceAll(s, "\"", "\\\"")
+}
@@ -0,0 +1,114 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"goviz/pkg/graph"
+)
+
+// JSONLMetadata is the first line of a JSON Lines report. Every following
+// line is a JSONLDependency.
+type JSONLMetadata struct {
+	Type       string          `json:"type"`
+	Metadata   ReportMetadata  `json:"metadata"`
+	Module     ModuleInfo      `json:"module"`
+	Statistics map[string]any  `json:"statistics"`
+	Warnings   []graph.Warning `json:"warnings,omitempty"`
+}
+
+type JSONLDependency struct {
+	Type       string `json:"type"`
+	MainModule string `json:"main_module"`
+	DependencyInfo
+}
+
+// WriteJSONL writes a dependency report as JSON Lines (NDJSON): a metadata
+// line followed by one line per dependency. Dependencies are encoded as
+// they are visited, so the full report is never held in memory.
+func WriteJSONL(w io.Writer, depGraph *graph.EnhancedDependencyGraph, projectPath string, opts ReportOptions) error {
+	bw := bufio.NewWriter(w)
+	if err := encodeJSONL(json.NewEncoder(bw), depGraph, projectPath, opts); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func encodeJSONL(enc *json.Encoder, depGraph *graph.EnhancedDependencyGraph, projectPath string, opts ReportOptions) error {
+	err := enc.Encode(JSONLMetadata{
+		Type:     "metadata",
+		Metadata: newReportMetadata(opts),
+		Module: ModuleInfo{
+			Name:      depGraph.ModuleName,
+			GoVersion: depGraph.ModuleGoVersion,
+			Path:      projectPath,
+		},
+		Statistics: depGraph.GetStatistics(),
+		Warnings:   depGraph.SortedWarnings(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	err = eachDependency(depGraph, opts, func(dep DependencyInfo) error {
+		return enc.Encode(JSONLDependency{Type: "dependency", MainModule: depGraph.ModuleName, DependencyInfo: dep})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode dependency: %w", err)
+	}
+
+	return nil
+}
+
+func GenerateJSONL(depGraph *graph.EnhancedDependencyGraph, outputFile, projectPath string, opts ReportOptions) error {
+	return writeJSONLFile(outputFile, func(enc *json.Encoder) error {
+		return encodeJSONL(enc, depGraph, projectPath, opts)
+	})
+}
+
+// GenerateMultiModuleJSONL writes the reports of several modules one after
+// another, each starting with its own metadata line.
+func GenerateMultiModuleJSONL(graphs []*graph.EnhancedDependencyGraph, projectPaths []string, outputFile string, opts ReportOptions) error {
+	return writeJSONLFile(outputFile, func(enc *json.Encoder) error {
+		for i, depGraph := range graphs {
+			if err := encodeJSONL(enc, depGraph, projectPaths[i], opts); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func writeJSONLFile(outputFile string, encode func(*json.Encoder) error) error {
+	if outputFile == "" {
+		bw := bufio.NewWriter(os.Stdout)
+		if err := encode(json.NewEncoder(bw)); err != nil {
+			return err
+		}
+		return bw.Flush()
+	}
+
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create JSONL file: %w", err)
+	}
+
+	bw := bufio.NewWriter(file)
+	if err := encode(json.NewEncoder(bw)); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write JSONL file: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write JSONL file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write JSONL file: %w", err)
+	}
+
+	fmt.Printf("JSONL report generated: %s\n", outputFile)
+	return nil
+}
@@ -0,0 +1,138 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"goviz/pkg/graph"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LicenseReport is the license compliance report written by the licenses
+// command's json and yaml formats.
+type LicenseReport struct {
+	Metadata        ReportMetadata          `json:"metadata" yaml:"metadata"`
+	Module          ModuleInfo              `json:"module" yaml:"module"`
+	LicensesSummary map[string]int          `json:"licenses_summary" yaml:"licenses_summary"`
+	Licenses        []LicenseEntry          `json:"licenses" yaml:"licenses"`
+	Obligations     graph.ObligationSummary `json:"obligations" yaml:"obligations"`
+	Conflicts       []graph.LicenseConflict `json:"license_conflicts,omitempty" yaml:"license_conflicts,omitempty"`
+	Orgs            []graph.OrgLicenses     `json:"orgs" yaml:"orgs"`
+	Dependencies    []LicenseInfo           `json:"dependencies" yaml:"dependencies"`
+}
+
+// LicenseInfo is a dependency's license and the obligations it carries;
+// Obligations is omitted when the license is not classified.
+type LicenseInfo struct {
+	Name        string   `json:"name" yaml:"name"`
+	Version     string   `json:"version" yaml:"version"`
+	Direct      bool     `json:"direct" yaml:"direct"`
+	License     string   `json:"license" yaml:"license"`
+	Obligations []string `json:"obligations,omitempty" yaml:"obligations,omitempty"`
+}
+
+// LicenseEntry is a distinct license of the LicensesSummary annotated with
+// its SPDX License List data; Name and Reference are omitted for licenses
+// outside the embedded list, such as Unknown.
+type LicenseEntry struct {
+	ID           string `json:"id" yaml:"id"`
+	Name         string `json:"name,omitempty" yaml:"name,omitempty"`
+	OSIApproved  bool   `json:"osi_approved" yaml:"osi_approved"`
+	Reference    string `json:"reference,omitempty" yaml:"reference,omitempty"`
+	Dependencies int    `json:"dependencies" yaml:"dependencies"`
+}
+
+// licenseInventory returns the licenses of summary sorted by ID.
+func licenseInventory(summary map[string]int) []LicenseEntry {
+	entries := make([]LicenseEntry, 0, len(summary))
+	for id, count := range summary {
+		entry := LicenseEntry{ID: id, Dependencies: count}
+		if license, ok := graph.LookupSPDXLicense(id); ok {
+			entry.Name, entry.OSIApproved, entry.Reference = license.Name, license.OSIApproved, license.Reference
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+	return entries
+}
+
+func buildLicenseReport(depGraph *graph.EnhancedDependencyGraph, projectPath string, opts ReportOptions) LicenseReport {
+	report := LicenseReport{
+		Metadata: newReportMetadata(opts),
+		Module: ModuleInfo{
+			Name:      depGraph.ModuleName,
+			GoVersion: depGraph.ModuleGoVersion,
+			Path:      projectPath,
+		},
+		LicensesSummary: depGraph.LicensesSummary,
+		Licenses:        licenseInventory(depGraph.LicensesSummary),
+		Obligations:     depGraph.ObligationsSummary(),
+		Conflicts:       depGraph.LicenseConflicts,
+		Orgs:            depGraph.LicensesByOrg(),
+		Dependencies:    make([]LicenseInfo, 0, len(depGraph.EnhancedNodes)),
+	}
+
+	for name, node := range depGraph.EnhancedNodes {
+		if name == depGraph.Root.Name {
+			continue
+		}
+		info := LicenseInfo{
+			Name:    node.Name,
+			Version: node.Version,
+			Direct:  node.Direct,
+			License: node.License,
+		}
+		if obligations, ok := graph.ModuleObligations(node.License); ok {
+			info.Obligations = obligations.Names()
+		}
+		report.Dependencies = append(report.Dependencies, info)
+	}
+	sort.Slice(report.Dependencies, func(i, j int) bool {
+		return report.Dependencies[i].Name < report.Dependencies[j].Name
+	})
+
+	return report
+}
+
+func GenerateLicenseJSON(depGraph *graph.EnhancedDependencyGraph, outputFile, projectPath string, opts ReportOptions) error {
+	jsonData, err := json.MarshalIndent(buildLicenseReport(depGraph, projectPath, opts), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	if outputFile == "" {
+		fmt.Print(string(jsonData))
+		return nil
+	}
+
+	if err := os.WriteFile(outputFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write JSON file: %w", err)
+	}
+
+	fmt.Printf("JSON license report generated: %s\n", outputFile)
+	return nil
+}
+
+func GenerateLicenseYAML(depGraph *graph.EnhancedDependencyGraph, outputFile, projectPath string, opts ReportOptions) error {
+	yamlData, err := yaml.Marshal(buildLicenseReport(depGraph, projectPath, opts))
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+
+	if outputFile == "" {
+		fmt.Print(string(yamlData))
+		return nil
+	}
+
+	if err := os.WriteFile(outputFile, yamlData, 0644); err != nil {
+		return fmt.Errorf("failed to write YAML file: %w", err)
+	}
+
+	fmt.Printf("YAML license report generated: %s\n", outputFile)
+	return nil
+}
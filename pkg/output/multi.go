@@ -0,0 +1,70 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"goviz/pkg/graph"
+
+	"gopkg.in/yaml.v3"
+)
+
+type MultiModuleReport struct {
+	Metadata             ReportMetadata              `json:"metadata" yaml:"metadata"`
+	Summary              graph.MultiModuleSummary    `json:"summary" yaml:"summary"`
+	CrossModuleConflicts []graph.CrossModuleConflict `json:"cross_module_conflicts,omitempty" yaml:"cross_module_conflicts,omitempty"`
+	Modules              []DependencyReport          `json:"modules" yaml:"modules"`
+}
+
+func buildMultiModuleReport(graphs []*graph.EnhancedDependencyGraph, projectPaths []string, opts ReportOptions) MultiModuleReport {
+	report := MultiModuleReport{
+		Metadata:             newReportMetadata(opts),
+		Summary:              graph.SummarizeModules(graphs),
+		CrossModuleConflicts: graph.CrossModuleConflicts(graphs),
+	}
+
+	for i, depGraph := range graphs {
+		report.Modules = append(report.Modules, buildDependencyReport(depGraph, projectPaths[i], opts))
+	}
+
+	return report
+}
+
+func GenerateMultiModuleJSON(graphs []*graph.EnhancedDependencyGraph, projectPaths []string, outputFile string, opts ReportOptions) error {
+	jsonData, err := json.MarshalIndent(buildMultiModuleReport(graphs, projectPaths, opts), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	if outputFile == "" {
+		fmt.Print(string(jsonData))
+		return nil
+	}
+
+	if err := os.WriteFile(outputFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write JSON file: %w", err)
+	}
+
+	fmt.Printf("JSON report generated: %s\n", outputFile)
+	return nil
+}
+
+func GenerateMultiModuleYAML(graphs []*graph.EnhancedDependencyGraph, projectPaths []string, outputFile string, opts ReportOptions) error {
+	yamlData, err := yaml.Marshal(buildMultiModuleReport(graphs, projectPaths, opts))
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+
+	if outputFile == "" {
+		fmt.Print(string(yamlData))
+		return nil
+	}
+
+	if err := os.WriteFile(outputFile, yamlData, 0644); err != nil {
+		return fmt.Errorf("failed to write YAML file: %w", err)
+	}
+
+	fmt.Printf("YAML report generated: %s\n", outputFile)
+	return nil
+}
@@ -0,0 +1,65 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"goviz/pkg/graph"
+)
+
+const noticeRule = "================================================================================"
+
+// GenerateNotice writes an attribution file for distributing depGraph's
+// main module: a header followed by one section per dependency with its
+// version, license, copyright notices and license text. License texts must
+// have been loaded with LoadLicenseTexts.
+func GenerateNotice(depGraph *graph.EnhancedDependencyGraph, outputFile string) error {
+	var nodes []*graph.EnhancedNode
+	for name, node := range depGraph.EnhancedNodes {
+		if name != depGraph.Root.Name {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "NOTICE\n\n")
+	fmt.Fprintf(&buf, "%s includes the following third-party software.\n", depGraph.ModuleName)
+	fmt.Fprintf(&buf, "Generated by goviz on %s.\n", time.Now().Format("2006-01-02"))
+
+	for _, node := range nodes {
+		license := node.License
+		if license == "" {
+			license = "Unknown"
+		}
+
+		fmt.Fprintf(&buf, "\n%s\n%s %s\n", noticeRule, node.Name, node.Version)
+		fmt.Fprintf(&buf, "License: %s\n", license)
+
+		text := node.LicenseText
+		if text == graph.LicenseTextUnavailable {
+			text = ""
+		}
+		for _, notice := range graph.CopyrightNotices(text) {
+			fmt.Fprintf(&buf, "%s\n", notice)
+		}
+		fmt.Fprintf(&buf, "%s\n\n", noticeRule)
+
+		if text == "" {
+			fmt.Fprintf(&buf, "License text not found in the module cache.\n")
+			continue
+		}
+		fmt.Fprintf(&buf, "%s\n", strings.TrimRight(text, "\n"))
+	}
+
+	if err := os.WriteFile(outputFile, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write NOTICE file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"goviz/pkg/graph"
+	"goviz/pkg/parser"
+)
+
+// pipelineResult is what a run of the analysis pipeline produces for one
+// project.
+type pipelineResult struct {
+	report string
+	dot    string
+}
+
+func runPipeline(t *testing.T, dir string, opts ReportOptions) pipelineResult {
+	t.Helper()
+
+	modFile, err := parser.ParseGoMod(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		t.Error(err)
+		return pipelineResult{}
+	}
+	depGraph, err := graph.BuildEnhancedDependencyGraph(modFile, filepath.Join(dir, "go.sum"))
+	if err != nil {
+		t.Error(err)
+		return pipelineResult{}
+	}
+	depGraph.DetectVersionConflicts()
+	if err := depGraph.AnalyzeLicenses(); err != nil {
+		t.Error(err)
+		return pipelineResult{}
+	}
+	if err := depGraph.CheckSecurity(); err != nil {
+		t.Error(err)
+		return pipelineResult{}
+	}
+
+	report := buildDependencyReport(depGraph, dir, opts)
+	report.Metadata.GeneratedAt = time.Time{}
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Error(err)
+		return pipelineResult{}
+	}
+
+	dotFile, err := writeTempDOT(depGraph, DOTOptions{})
+	if err != nil {
+		t.Error(err)
+		return pipelineResult{}
+	}
+	defer os.Remove(dotFile)
+	dot, err := os.ReadFile(dotFile)
+	if err != nil {
+		t.Error(err)
+		return pipelineResult{}
+	}
+
+	return pipelineResult{report: string(data), dot: string(dot)}
+}
+
+func TestPipelineConcurrent(t *testing.T) {
+	fixtures := map[string]struct {
+		module  string
+		imports string
+		foreign string
+	}{
+		"testdata/alpha": {module: "example.com/alpha", imports: "github.com/spf13/cobra", foreign: "github.com/fatih/color"},
+		"testdata/beta":  {module: "example.com/beta", imports: "github.com/fatih/color", foreign: "github.com/spf13/cobra"},
+	}
+
+	want := make(map[string]pipelineResult)
+	for dir, fixture := range fixtures {
+		result := runPipeline(t, dir, ReportOptions{})
+		for _, output := range []string{result.report, result.dot} {
+			if !strings.Contains(output, fixture.module) || !strings.Contains(output, fixture.imports) {
+				t.Fatalf("%s: output lacks %s or %s:\n%s", dir, fixture.module, fixture.imports, output)
+			}
+			if strings.Contains(output, fixture.foreign) {
+				t.Fatalf("%s: output mentions %s of the other fixture:\n%s", dir, fixture.foreign, output)
+			}
+		}
+		want[dir] = result
+	}
+
+	const runs = 8
+	var wg sync.WaitGroup
+	for range runs {
+		for dir := range fixtures {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				got := runPipeline(t, dir, ReportOptions{})
+				if got.report != want[dir].report {
+					t.Errorf("%s: concurrent report differs from sequential run:\n got: %s\nwant: %s", dir, got.report, want[dir].report)
+				}
+				if got.dot != want[dir].dot {
+					t.Errorf("%s: concurrent DOT differs from sequential run:\n got: %s\nwant: %s", dir, got.dot, want[dir].dot)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+}
@@ -4,23 +4,26 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"goviz/pkg/graph"
+
+	"github.com/awalterschulze/gographviz"
 )
 
-func GeneratePNG(depGraph *graph.EnhancedDependencyGraph, outputFile string) error {
+func GeneratePNG(depGraph *graph.EnhancedDependencyGraph, outputFile string, opts DOTOptions) error {
 
-	if err := checkGraphvizInstalled(); err != nil {
+	if err := CheckGraphvizInstalled(); err != nil {
 		return err
 	}
 
-	tempDotFile := "temp_depgraph.dot"
-	defer os.Remove(tempDotFile)
-
-	if err := GenerateEnhancedDOT(depGraph, tempDotFile); err != nil {
-		return fmt.Errorf("failed to generate DOT file: %w", err)
+	tempDotFile, err := writeTempDOT(depGraph, opts)
+	if err != nil {
+		return err
 	}
+	defer os.Remove(tempDotFile)
 
 	if outputFile == "" {
 		outputFile = "depgraph.png"
@@ -38,9 +41,36 @@ func GeneratePNG(depGraph *graph.EnhancedDependencyGraph, outputFile string) err
 	return nil
 }
 
-func GenerateEnhancedDOT(depGraph *graph.EnhancedDependencyGraph, outputFile string) error {
+// writeTempDOT writes the validated DOT graph of depGraph to a new file in
+// the temporary directory for Graphviz to render. Each call gets its own
+// file, so several graphs can be rendered at once.
+func writeTempDOT(depGraph *graph.EnhancedDependencyGraph, opts DOTOptions) (string, error) {
+	tmp, err := os.CreateTemp("", "goviz-*.dot")
+	if err != nil {
+		return "", fmt.Errorf("failed to create DOT file: %w", err)
+	}
+	tmp.Close()
 
-	if err := GenerateDOT(depGraph.DependencyGraph, outputFile); err != nil {
+	if err := GenerateEnhancedDOT(depGraph, tmp.Name(), opts); err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to generate DOT file: %w", err)
+	}
+	if err := validateDOTFile(tmp.Name()); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// GenerateEnhancedDOT writes the DOT graph of depGraph. Large graphs and
+// highlighted paths are written directly rather than through gographviz.
+func GenerateEnhancedDOT(depGraph *graph.EnhancedDependencyGraph, outputFile string, opts DOTOptions) error {
+
+	if len(depGraph.AllNodes) > StreamingDOTThreshold || opts.Highlight != nil {
+		return generateStreamingDOTFile(depGraph, outputFile, opts)
+	}
+
+	if err := GenerateDOT(depGraph.DependencyGraph, outputFile, opts); err != nil {
 		return err
 	}
 
@@ -49,7 +79,7 @@ func GenerateEnhancedDOT(depGraph *graph.EnhancedDependencyGraph, outputFile str
 		return fmt.Errorf("failed to read DOT file: %w", err)
 	}
 
-	enhancedContent := enhanceDOTContent(string(content), depGraph)
+	enhancedContent := enhanceDOTContent(string(content), depGraph, opts)
 
 	if err := os.WriteFile(outputFile, []byte(enhancedContent), 0644); err != nil {
 		return fmt.Errorf("failed to write enhanced DOT file: %w", err)
@@ -58,33 +88,29 @@ func GenerateEnhancedDOT(depGraph *graph.EnhancedDependencyGraph, outputFile str
 	return nil
 }
 
-func enhanceDOTContent(content string, depGraph *graph.EnhancedDependencyGraph) string {
+func enhanceDOTContent(content string, depGraph *graph.EnhancedDependencyGraph, opts DOTOptions) string {
 	lines := strings.Split(content, "\n")
 	var enhancedLines []string
+	nodes := dotNodes(depGraph)
 
 	for _, line := range lines {
 		if strings.Contains(line, "digraph DependencyGraph") {
 			enhancedLines = append(enhancedLines, line)
 
-			enhancedLines = append(enhancedLines, "    graph [fontname=\"Arial\", fontsize=12];")
-			enhancedLines = append(enhancedLines, "    node [fontname=\"Arial\", fontsize=10, shape=box];")
-			enhancedLines = append(enhancedLines, "    edge [fontname=\"Arial\", fontsize=8];")
-		} else if strings.Contains(line, "rankdir=TB") {
+			for _, header := range dotHeader(opts) {
+				enhancedLines = append(enhancedLines, "    "+header)
+			}
+		} else if strings.Contains(line, "rankdir=") {
 			enhancedLines = append(enhancedLines, line)
 
-			enhancedLines = append(enhancedLines, "    subgraph cluster_legend {")
-			enhancedLines = append(enhancedLines, "        label=\"Legend\";")
-			enhancedLines = append(enhancedLines, "        style=filled;")
-			enhancedLines = append(enhancedLines, "        color=lightgrey;")
-			enhancedLines = append(enhancedLines, "        legend_main [label=\"Main Module\", fillcolor=lightblue, style=filled];")
-			enhancedLines = append(enhancedLines, "        legend_direct [label=\"Direct Dependency\", fillcolor=lightgreen, style=filled];")
-			enhancedLines = append(enhancedLines, "        legend_indirect [label=\"Indirect Dependency\", fillcolor=lightgray, style=filled];")
-			enhancedLines = append(enhancedLines, "        legend_security [label=\"Security Issue\", fillcolor=red, style=filled];")
+			for _, legend := range dotLegend(opts.theme()) {
+				enhancedLines = append(enhancedLines, "    "+legend)
+			}
 			enhancedLines = append(enhancedLines, "    }")
 		} else {
 
 			if strings.Contains(line, "[ fillcolor=") && strings.Contains(line, "label=") {
-				enhancedLine := enhanceNodeDefinition(line, depGraph)
+				enhancedLine := enhanceNodeDefinition(line, nodes, opts.theme())
 				enhancedLines = append(enhancedLines, enhancedLine)
 			} else {
 				enhancedLines = append(enhancedLines, line)
@@ -95,31 +121,29 @@ func enhanceDOTContent(content string, depGraph *graph.EnhancedDependencyGraph)
 	return strings.Join(enhancedLines, "\n")
 }
 
-func enhanceNodeDefinition(line string, depGraph *graph.EnhancedDependencyGraph) string {
+// enhanceNodeDefinition adds the license, security and repository details
+// of a module to its node line; nodes maps DOT node IDs to modules as
+// returned by dotNodes.
+func enhanceNodeDefinition(line string, nodes map[string]*graph.EnhancedNode, theme DOTTheme) string {
 
 	parts := strings.Fields(line)
 	if len(parts) == 0 {
 		return line
 	}
 
-	nodeName := strings.Trim(parts[0], "\"")
-
-	var enhancedNode *graph.EnhancedNode
-	for name, node := range depGraph.EnhancedNodes {
-		sanitizedName := sanitizeNodeName(name)
-		if strings.Contains(sanitizedName, nodeName) || strings.Contains(nodeName, strings.ReplaceAll(name, "/", "_")) {
-			enhancedNode = node
-			break
-		}
-	}
-
-	if enhancedNode == nil {
+	enhancedNode, ok := nodes[strings.Trim(parts[0], "\"")]
+	if !ok {
 		return line
 	}
 
 	if len(enhancedNode.SecurityIssues) > 0 {
-		line = strings.ReplaceAll(line, "fillcolor=lightgreen", "fillcolor=red")
-		line = strings.ReplaceAll(line, "fillcolor=lightgray", "fillcolor=orange")
+		line = strings.ReplaceAll(line, "fillcolor="+dotColor(theme.Direct), "fillcolor="+dotColor(theme.DirectIssue))
+		line = strings.ReplaceAll(line, "fillcolor="+dotColor(theme.Indirect), "fillcolor="+dotColor(theme.IndirectIssue))
+	}
+
+	// Graphviz turns URL into a link in SVG output.
+	if enhancedNode.RepoURL != "" {
+		line = strings.Replace(line, "label=", fmt.Sprintf("URL=\"%s\", label=", escapeDOT(enhancedNode.RepoURL)), 1)
 	}
 
 	if enhancedNode.License != "" {
@@ -142,7 +166,42 @@ func enhanceNodeDefinition(line string, depGraph *graph.EnhancedDependencyGraph)
 	return line
 }
 
-func checkGraphvizInstalled() error {
+var dotErrorPosition = regexp.MustCompile(`line=(\d+), column=\d+\)(?:, (expected .*))?`)
+
+// validateDOTFile parses the generated DOT before it is handed to Graphviz,
+// so that a malformed file is reported with the offending line instead of
+// Graphviz's raw error output.
+func validateDOTFile(dotFile string) error {
+	content, err := os.ReadFile(dotFile)
+	if err != nil {
+		return fmt.Errorf("failed to read DOT file: %w", err)
+	}
+
+	if _, err := gographviz.ParseString(string(content)); err != nil {
+		match := dotErrorPosition.FindStringSubmatch(err.Error())
+		if match == nil {
+			return fmt.Errorf("generated DOT is invalid: %w", err)
+		}
+
+		lineNumber, _ := strconv.Atoi(match[1])
+		lines := strings.Split(string(content), "\n")
+		line := ""
+		if lineNumber > 0 && lineNumber <= len(lines) {
+			line = strings.TrimSpace(lines[lineNumber-1])
+		}
+		msg := fmt.Sprintf("generated DOT is invalid at line %d: %s", lineNumber, line)
+		if match[2] != "" {
+			msg += " (" + strings.TrimSpace(match[2]) + ")"
+		}
+		return fmt.Errorf("%s", msg)
+	}
+
+	return nil
+}
+
+// CheckGraphvizInstalled reports an error with install instructions when the
+// Graphviz dot command, needed for png and svg output, is unavailable.
+func CheckGraphvizInstalled() error {
 	cmd := exec.Command("dot", "-V")
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("Graphviz not found. Please install Graphviz:\n" +
@@ -153,17 +212,16 @@ func checkGraphvizInstalled() error {
 	return nil
 }
 
-func GenerateSVG(depGraph *graph.EnhancedDependencyGraph, outputFile string) error {
-	if err := checkGraphvizInstalled(); err != nil {
+func GenerateSVG(depGraph *graph.EnhancedDependencyGraph, outputFile string, opts DOTOptions) error {
+	if err := CheckGraphvizInstalled(); err != nil {
 		return err
 	}
 
-	tempDotFile := "temp_depgraph.dot"
-	defer os.Remove(tempDotFile)
-
-	if err := GenerateEnhancedDOT(depGraph, tempDotFile); err != nil {
-		return fmt.Errorf("failed to generate DOT file: %w", err)
+	tempDotFile, err := writeTempDOT(depGraph, opts)
+	if err != nil {
+		return err
 	}
+	defer os.Remove(tempDotFile)
 
 	if outputFile == "" {
 		outputFile = "depgraph.svg"
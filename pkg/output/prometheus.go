@@ -0,0 +1,175 @@
+package output
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"goviz/pkg/graph"
+)
+
+type promSample struct {
+	labels []string // alternating names and values
+	value  float64
+}
+
+type promFamily struct {
+	name    string
+	help    string
+	samples func(depGraph *graph.EnhancedDependencyGraph) []promSample
+}
+
+// promSeverities are the severity label values of goviz_security_issues,
+// which are always written so the series exist even when zero.
+var promSeverities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"}
+
+var promFamilies = []promFamily{
+	{"goviz_dependencies_total", "Modules in the dependency graph, excluding the main module.", func(g *graph.EnhancedDependencyGraph) []promSample {
+		return []promSample{{value: float64(len(g.AllNodes) - 1)}}
+	}},
+	{"goviz_direct_dependencies", "Modules required directly in go.mod.", func(g *graph.EnhancedDependencyGraph) []promSample {
+		direct, _ := g.GetDependencyCount()
+		return []promSample{{value: float64(direct)}}
+	}},
+	{"goviz_indirect_dependencies", "Modules required as // indirect in go.mod.", func(g *graph.EnhancedDependencyGraph) []promSample {
+		_, indirect := g.GetDependencyCount()
+		return []promSample{{value: float64(indirect)}}
+	}},
+	{"goviz_version_conflicts", "Version conflicts between requirements.", func(g *graph.EnhancedDependencyGraph) []promSample {
+		return []promSample{{value: float64(len(g.Conflicts))}}
+	}},
+	{"goviz_security_issues", "Security issues by severity.", func(g *graph.EnhancedDependencyGraph) []promSample {
+		counts := make(map[string]int)
+		for _, issue := range g.SecurityIssues {
+			counts[issue.Severity]++
+		}
+		var samples []promSample
+		for _, severity := range promSeverities {
+			samples = append(samples, promSample{labels: []string{"severity", strings.ToLower(severity)}, value: float64(counts[severity])})
+		}
+		return samples
+	}},
+	{"goviz_licenses", "Dependencies by detected license.", func(g *graph.EnhancedDependencyGraph) []promSample {
+		var samples []promSample
+		for _, license := range sortedKeys(g.LicensesSummary) {
+			samples = append(samples, promSample{labels: []string{"license", license}, value: float64(g.LicensesSummary[license])})
+		}
+		return samples
+	}},
+	{"goviz_risk_score", "Dependency risk score from 0 (none) to 100.", func(g *graph.EnhancedDependencyGraph) []promSample {
+		return []promSample{{value: float64(g.RiskScore().Score)}}
+	}},
+	{"goviz_warnings", "Warnings that make the analysis incomplete.", func(g *graph.EnhancedDependencyGraph) []promSample {
+		return []promSample{{value: float64(len(g.Warnings))}}
+	}},
+	{"goviz_dependency_info", "Version and license of each dependency; always 1.", func(g *graph.EnhancedDependencyGraph) []promSample {
+		var samples []promSample
+		for _, node := range g.SortedNodes("name") {
+			samples = append(samples, promSample{labels: []string{"dependency", node.Name, "version", node.Version, "license", node.License}, value: 1})
+		}
+		return samples
+	}},
+	{"goviz_dependency_outdated", "Whether a dependency is outdated (1) or not (0).", func(g *graph.EnhancedDependencyGraph) []promSample {
+		var samples []promSample
+		for _, node := range g.SortedNodes("name") {
+			samples = append(samples, promSample{labels: []string{"dependency", node.Name}, value: promBool(node.IsOutdated)})
+		}
+		return samples
+	}},
+	{"goviz_dependency_security_issues", "Security issues of each dependency.", func(g *graph.EnhancedDependencyGraph) []promSample {
+		var samples []promSample
+		for _, node := range g.SortedNodes("name") {
+			samples = append(samples, promSample{labels: []string{"dependency", node.Name}, value: float64(len(node.SecurityIssues))})
+		}
+		return samples
+	}},
+}
+
+// WritePrometheus writes the analysis of one or more modules as gauges in
+// the Prometheus text exposition format. Every sample carries the main
+// module in the module label; per-dependency gauges add a dependency label.
+func WritePrometheus(w io.Writer, graphs []*graph.EnhancedDependencyGraph) error {
+	bw := bufio.NewWriter(w)
+
+	for _, family := range promFamilies {
+		fmt.Fprintf(bw, "# HELP %s %s\n", family.name, family.help)
+		fmt.Fprintf(bw, "# TYPE %s gauge\n", family.name)
+		for _, depGraph := range graphs {
+			for _, sample := range family.samples(depGraph) {
+				fmt.Fprintf(bw, "%s{%s} %g\n", family.name, promLabels(append([]string{"module", depGraph.ModuleName}, sample.labels...)), sample.value)
+			}
+		}
+	}
+
+	fmt.Fprintf(bw, "# HELP goviz_report_timestamp_seconds When the analysis ran, as a Unix timestamp.\n")
+	fmt.Fprintf(bw, "# TYPE goviz_report_timestamp_seconds gauge\n")
+	fmt.Fprintf(bw, "goviz_report_timestamp_seconds %d\n", time.Now().Unix())
+
+	return bw.Flush()
+}
+
+// GeneratePrometheus writes the metrics to stdout or to outputFile. The
+// file is replaced atomically, so the node_exporter textfile collector
+// never reads it half-written.
+func GeneratePrometheus(graphs []*graph.EnhancedDependencyGraph, outputFile string) error {
+	if outputFile == "" {
+		return WritePrometheus(os.Stdout, graphs)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(outputFile), "."+filepath.Base(outputFile)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create metrics file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := WritePrometheus(tmp, graphs); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), outputFile); err != nil {
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+
+	fmt.Printf("Prometheus metrics generated: %s\n", outputFile)
+	return nil
+}
+
+func promLabels(pairs []string) string {
+	var b strings.Builder
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", pairs[i], promEscaper.Replace(pairs[i+1]))
+	}
+	return b.String()
+}
+
+var promEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func promBool(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,194 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"goviz/pkg/graph"
+)
+
+type SARIFReport struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+type SARIFRule struct {
+	ID                   string          `json:"id"`
+	ShortDescription     SARIFMessage    `json:"shortDescription"`
+	Help                 SARIFMessage    `json:"help"`
+	DefaultConfiguration SARIFRuleConfig `json:"defaultConfiguration"`
+	Properties           map[string]any  `json:"properties,omitempty"`
+}
+
+type SARIFRuleConfig struct {
+	Level string `json:"level"`
+}
+
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations"`
+}
+
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type SARIFRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// GenerateSARIF writes a SARIF 2.1.0 report with one result per security
+// issue. goModURI is the go.mod location relative to the repository root.
+func GenerateSARIF(depGraph *graph.EnhancedDependencyGraph, outputFile, goModURI string) error {
+	var names []string
+	for name, node := range depGraph.EnhancedNodes {
+		if name != depGraph.Root.Name && len(node.SecurityIssues) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	rules := make(map[string]SARIFRule)
+	results := make([]SARIFResult, 0)
+
+	for _, name := range names {
+		node := depGraph.EnhancedNodes[name]
+		for _, issue := range node.SecurityIssues {
+			level := sarifLevel(issue.Severity)
+			if _, exists := rules[issue.ID]; !exists {
+				rules[issue.ID] = SARIFRule{
+					ID:                   issue.ID,
+					ShortDescription:     SARIFMessage{Text: issue.Description},
+					Help:                 SARIFMessage{Text: sarifHelp(issue)},
+					DefaultConfiguration: SARIFRuleConfig{Level: level},
+					Properties: map[string]any{
+						"security-severity": sarifSecuritySeverity(issue),
+						"tags":              []string{"security", "dependency"},
+					},
+				}
+			}
+
+			location := SARIFPhysicalLocation{
+				ArtifactLocation: SARIFArtifactLocation{URI: goModURI},
+			}
+			if node.DefinedAtLine > 0 {
+				location.Region = &SARIFRegion{StartLine: node.DefinedAtLine}
+			}
+
+			results = append(results, SARIFResult{
+				RuleID:    issue.ID,
+				Level:     level,
+				Message:   SARIFMessage{Text: fmt.Sprintf("%s@%s: %s", node.Name, node.Version, issue.Description)},
+				Locations: []SARIFLocation{{PhysicalLocation: location}},
+			})
+		}
+	}
+
+	ruleList := make([]SARIFRule, 0, len(rules))
+	for _, rule := range rules {
+		ruleList = append(ruleList, rule)
+	}
+	sort.Slice(ruleList, func(i, j int) bool {
+		return ruleList[i].ID < ruleList[j].ID
+	})
+
+	report := SARIFReport{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []SARIFRun{{
+			Tool: SARIFTool{Driver: SARIFDriver{
+				Name:           "goviz",
+				Version:        "v0.1.0",
+				InformationURI: "https://github.com/mehmetymw/goviz",
+				Rules:          ruleList,
+			}},
+			Results: results,
+		}},
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal SARIF: %w", err)
+	}
+
+	if outputFile == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if err := os.WriteFile(outputFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write SARIF file: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "SARIF report generated: %s\n", outputFile)
+	return nil
+}
+
+func sarifLevel(severity string) string {
+	switch severity {
+	case "CRITICAL", "HIGH":
+		return "error"
+	case "MEDIUM":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+func sarifSecuritySeverity(issue graph.SecurityIssue) string {
+	if issue.CVSSScore > 0 {
+		return strconv.FormatFloat(issue.CVSSScore, 'f', 1, 64)
+	}
+	switch issue.Severity {
+	case "CRITICAL":
+		return "9.5"
+	case "HIGH":
+		return "8.0"
+	case "MEDIUM":
+		return "5.5"
+	default:
+		return "2.0"
+	}
+}
+
+func sarifHelp(issue graph.SecurityIssue) string {
+	if issue.FixedIn == "" {
+		return issue.Description
+	}
+	return fmt.Sprintf("%s. Fixed in: %s", issue.Description, issue.FixedIn)
+}
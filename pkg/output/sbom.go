@@ -0,0 +1,420 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"goviz/pkg/graph"
+)
+
+type CycloneDXBOM struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber,omitempty"`
+	Version      int                  `json:"version"`
+	Metadata     CycloneDXMetadata    `json:"metadata"`
+	Components   []CycloneDXComponent `json:"components"`
+	Dependencies []CycloneDXDepend    `json:"dependencies,omitempty"`
+}
+
+type CycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     []CycloneDXTool    `json:"tools,omitempty"`
+	Component CycloneDXComponent `json:"component"`
+}
+
+type CycloneDXTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type CycloneDXComponent struct {
+	Type               string                       `json:"type"`
+	BOMRef             string                       `json:"bom-ref"`
+	Name               string                       `json:"name"`
+	Version            string                       `json:"version,omitempty"`
+	Scope              string                       `json:"scope,omitempty"`
+	PURL               string                       `json:"purl,omitempty"`
+	Licenses           []CycloneDXLicense           `json:"licenses,omitempty"`
+	ExternalReferences []CycloneDXExternalReference `json:"externalReferences,omitempty"`
+	Properties         []CycloneDXProperty          `json:"properties,omitempty"`
+}
+
+type CycloneDXExternalReference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type CycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type CycloneDXLicense struct {
+	License    *CycloneDXLicenseChoice `json:"license,omitempty"`
+	Expression string                  `json:"expression,omitempty"`
+}
+
+type CycloneDXLicenseChoice struct {
+	ID   string               `json:"id,omitempty"`
+	Name string               `json:"name,omitempty"`
+	Text *CycloneDXAttachment `json:"text,omitempty"`
+}
+
+type CycloneDXAttachment struct {
+	ContentType string `json:"contentType"`
+	Content     string `json:"content"`
+}
+
+type CycloneDXDepend struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+type SPDXDocument struct {
+	SPDXVersion             string                 `json:"spdxVersion"`
+	DataLicense             string                 `json:"dataLicense"`
+	SPDXID                  string                 `json:"SPDXID"`
+	Name                    string                 `json:"name"`
+	DocumentNamespace       string                 `json:"documentNamespace"`
+	CreationInfo            SPDXCreationInfo       `json:"creationInfo"`
+	DocumentDescribes       []string               `json:"documentDescribes"`
+	Packages                []SPDXPackage          `json:"packages"`
+	Relationships           []SPDXRelationship     `json:"relationships"`
+	ExtractedLicensingInfos []SPDXExtractedLicense `json:"hasExtractedLicensingInfos,omitempty"`
+}
+
+type SPDXExtractedLicense struct {
+	LicenseID     string `json:"licenseId"`
+	ExtractedText string `json:"extractedText"`
+	Name          string `json:"name,omitempty"`
+	Comment       string `json:"comment,omitempty"`
+}
+
+type SPDXCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type SPDXPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	Homepage         string            `json:"homepage,omitempty"`
+	ExternalRefs     []SPDXExternalRef `json:"externalRefs"`
+	Comment          string            `json:"comment,omitempty"`
+}
+
+type SPDXExternalRef struct {
+	Category string `json:"referenceCategory"`
+	Type     string `json:"referenceType"`
+	Locator  string `json:"referenceLocator"`
+}
+
+type SPDXRelationship struct {
+	Element string `json:"spdxElementId"`
+	Type    string `json:"relationshipType"`
+	Related string `json:"relatedSpdxElement"`
+}
+
+// goSumHashName names the go.sum "h1:" hash of a module in SBOMs. The hash
+// is a dirhash over the module's files, not a digest of any single artifact,
+// so it is recorded verbatim rather than as an SBOM checksum.
+const goSumHashName = "go.sum h1"
+
+// GenerateCycloneDX writes a CycloneDX SBOM of depGraph, naming goviz at
+// toolVersion as the tool that produced it.
+func GenerateCycloneDX(depGraph *graph.EnhancedDependencyGraph, toolVersion, outputFile string) error {
+	nodes, err := sbomNodes(depGraph)
+	if err != nil {
+		return err
+	}
+
+	root := depGraph.EnhancedNodes[depGraph.Root.Name]
+	rootRef := PackageURL(depGraph.ModuleName, "")
+	bom := CycloneDXBOM{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata: CycloneDXMetadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Tools:     []CycloneDXTool{{Name: "goviz", Version: toolVersion}},
+			Component: CycloneDXComponent{
+				Type:               "application",
+				BOMRef:             rootRef,
+				Name:               depGraph.ModuleName,
+				PURL:               rootRef,
+				Licenses:           cycloneDXLicenses(root),
+				ExternalReferences: cycloneDXExternalReferences(root),
+			},
+		},
+		Components: make([]CycloneDXComponent, 0, len(nodes)),
+	}
+
+	rootDepends := CycloneDXDepend{Ref: rootRef}
+	for _, node := range nodes {
+		purl := PackageURL(node.Name, node.Version)
+		component := CycloneDXComponent{
+			Type:               "library",
+			BOMRef:             purl,
+			Name:               node.Name,
+			Version:            node.Version,
+			Scope:              "required",
+			PURL:               purl,
+			Licenses:           cycloneDXLicenses(node),
+			ExternalReferences: cycloneDXExternalReferences(node),
+		}
+		if node.Hash != "" {
+			component.Properties = []CycloneDXProperty{{Name: goSumHashName, Value: node.Hash}}
+		}
+		bom.Components = append(bom.Components, component)
+
+		if node.Direct {
+			rootDepends.DependsOn = append(rootDepends.DependsOn, purl)
+		}
+	}
+	bom.Dependencies = []CycloneDXDepend{rootDepends}
+
+	data, err := json.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal CycloneDX: %w", err)
+	}
+
+	return writeSBOM(data, outputFile, "CycloneDX")
+}
+
+// GenerateSPDX writes an SPDX SBOM of depGraph, naming goviz at toolVersion
+// as the tool that created it.
+func GenerateSPDX(depGraph *graph.EnhancedDependencyGraph, toolVersion, outputFile string) error {
+	nodes, err := sbomNodes(depGraph)
+	if err != nil {
+		return err
+	}
+
+	names := []string{depGraph.ModuleName}
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	ids := spdxIDs(names)
+
+	root := depGraph.EnhancedNodes[depGraph.Root.Name]
+	rootID := "SPDXRef-Package-" + ids[depGraph.ModuleName]
+	now := time.Now().UTC()
+
+	doc := SPDXDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              depGraph.ModuleName,
+		DocumentNamespace: fmt.Sprintf("https://spdx.org/spdxdocs/goviz/%s-%d", depGraph.ModuleName, now.Unix()),
+		CreationInfo: SPDXCreationInfo{
+			Created:  now.Format(time.RFC3339),
+			Creators: []string{"Tool: goviz-" + toolVersion},
+		},
+		DocumentDescribes: []string{rootID},
+		Relationships: []SPDXRelationship{
+			{Element: "SPDXRef-DOCUMENT", Type: "DESCRIBES", Related: rootID},
+		},
+	}
+
+	doc.Packages = append(doc.Packages, SPDXPackage{
+		Name:             depGraph.ModuleName,
+		SPDXID:           rootID,
+		VersionInfo:      root.Version,
+		DownloadLocation: "NOASSERTION",
+		LicenseConcluded: spdxLicense(root),
+		LicenseDeclared:  spdxLicense(root),
+		CopyrightText:    "NOASSERTION",
+		Homepage:         root.RepoURL,
+		ExternalRefs: []SPDXExternalRef{
+			{Category: "PACKAGE-MANAGER", Type: "purl", Locator: PackageURL(depGraph.ModuleName, "")},
+		},
+	})
+
+	for _, node := range nodes {
+		id := "SPDXRef-Package-" + ids[node.Name]
+		pkg := SPDXPackage{
+			Name:             node.Name,
+			SPDXID:           id,
+			VersionInfo:      node.Version,
+			DownloadLocation: fmt.Sprintf("https://proxy.golang.org/%s/@v/%s.zip", node.Name, node.Version),
+			LicenseConcluded: spdxLicense(node),
+			LicenseDeclared:  spdxLicense(node),
+			CopyrightText:    "NOASSERTION",
+			Homepage:         node.RepoURL,
+			ExternalRefs: []SPDXExternalRef{
+				{Category: "PACKAGE-MANAGER", Type: "purl", Locator: PackageURL(node.Name, node.Version)},
+			},
+		}
+		if node.Hash != "" {
+			pkg.Comment = goSumHashName + ": " + node.Hash
+		}
+		if node.LicenseText != "" {
+			ref := "LicenseRef-" + ids[node.Name]
+			doc.ExtractedLicensingInfos = append(doc.ExtractedLicensingInfos, SPDXExtractedLicense{
+				LicenseID:     ref,
+				ExtractedText: node.LicenseText,
+				Name:          spdxLicense(node),
+				Comment:       fmt.Sprintf("License file of %s@%s", node.Name, node.Version),
+			})
+			if pkg.LicenseDeclared == "NOASSERTION" && node.LicenseText != graph.LicenseTextUnavailable {
+				pkg.LicenseDeclared = ref
+			}
+		}
+		doc.Packages = append(doc.Packages, pkg)
+
+		doc.Relationships = append(doc.Relationships, SPDXRelationship{Element: rootID, Type: "DEPENDS_ON", Related: id})
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal SPDX: %w", err)
+	}
+
+	return writeSBOM(data, outputFile, "SPDX")
+}
+
+func PackageURL(modulePath, version string) string {
+	purl := "pkg:golang/" + modulePath
+	if version != "" {
+		purl += "@" + version
+	}
+	return purl
+}
+
+func sbomNodes(depGraph *graph.EnhancedDependencyGraph) ([]*graph.EnhancedNode, error) {
+	var nodes []*graph.EnhancedNode
+	var incomplete []string
+
+	for name, node := range depGraph.EnhancedNodes {
+		if name == depGraph.Root.Name {
+			continue
+		}
+		if node.Name == "" || node.Version == "" {
+			incomplete = append(incomplete, name)
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(incomplete) > 0 {
+		sort.Strings(incomplete)
+		return nil, fmt.Errorf("SBOM incomplete: missing purl or version for %s", strings.Join(incomplete, ", "))
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	return nodes, nil
+}
+
+func cycloneDXLicenses(node *graph.EnhancedNode) []CycloneDXLicense {
+	if node == nil {
+		return nil
+	}
+
+	var choice CycloneDXLicenseChoice
+	if node.License != "" && node.License != "Unknown" {
+		if len(graph.LicenseIDs(node.License)) > 1 {
+			// Compound licenses must be given as an expression, which
+			// cannot carry license text.
+			return []CycloneDXLicense{{Expression: node.License}}
+		}
+		choice.ID = node.License
+	}
+	if node.LicenseText != "" {
+		if choice.ID == "" {
+			choice.Name = "Unknown"
+		}
+		choice.Text = &CycloneDXAttachment{ContentType: "text/plain", Content: node.LicenseText}
+	}
+
+	if choice.ID == "" && choice.Text == nil {
+		return nil
+	}
+	return []CycloneDXLicense{{License: &choice}}
+}
+
+func cycloneDXExternalReferences(node *graph.EnhancedNode) []CycloneDXExternalReference {
+	if node.RepoURL == "" {
+		return nil
+	}
+	return []CycloneDXExternalReference{{Type: "vcs", URL: node.RepoURL}}
+}
+
+func spdxLicense(node *graph.EnhancedNode) string {
+	if node == nil || node.License == "" || node.License == "Unknown" {
+		return "NOASSERTION"
+	}
+	return node.License
+}
+
+// spdxIDs returns the identifier of every module path for use in SPDX
+// element and license IDs, unique within the document. Characters SPDX
+// does not allow become "-"; module paths that would end up with the same
+// identifier, such as a/b and a-b, get a suffix derived from their hash.
+func spdxIDs(modulePaths []string) map[string]string {
+	bases := make(map[string]int)
+	for _, modulePath := range modulePaths {
+		bases[spdxIDString(modulePath)]++
+	}
+
+	ids := make(map[string]string, len(modulePaths))
+	taken := make(map[string]bool, len(modulePaths))
+	for _, modulePath := range modulePaths {
+		base := spdxIDString(modulePath)
+		id := base
+		if bases[base] > 1 {
+			sum := sha256.Sum256([]byte(modulePath))
+			digest := hex.EncodeToString(sum[:])
+			for n := 8; ; n += 8 {
+				id = base + "-" + digest[:n]
+				if !taken[id] || n == len(digest) {
+					break
+				}
+			}
+		}
+		ids[modulePath] = id
+		taken[id] = true
+	}
+	return ids
+}
+
+// spdxIDString replaces characters not allowed in SPDX identifiers.
+func spdxIDString(modulePath string) string {
+	var b strings.Builder
+	for _, r := range modulePath {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+func writeSBOM(data []byte, outputFile, kind string) error {
+	if outputFile == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if err := os.WriteFile(outputFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s SBOM: %w", kind, err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%s SBOM generated: %s\n", kind, outputFile)
+	return nil
+}
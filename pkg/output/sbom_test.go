@@ -0,0 +1,174 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"goviz/pkg/graph"
+	"goviz/pkg/parser"
+)
+
+var spdxIDPattern = regexp.MustCompile(`^[A-Za-z0-9.-]+$`)
+
+func TestSPDXIDs(t *testing.T) {
+	paths := []string{
+		"example.com/app",
+		"github.com/a/b-c",
+		"github.com/a-b/c",
+		"github.com/a/b/c",
+		"github.com/a_b/c",
+		"gopkg.in/yaml.v3",
+	}
+	ids := spdxIDs(paths)
+
+	seen := make(map[string]string)
+	for _, path := range paths {
+		id := ids[path]
+		if !spdxIDPattern.MatchString(id) {
+			t.Errorf("id of %s = %q, which has characters SPDX does not allow", path, id)
+		}
+		if other, ok := seen[id]; ok {
+			t.Errorf("%s and %s share the id %q", other, path, id)
+		}
+		seen[id] = path
+	}
+
+	for path, want := range map[string]string{
+		"example.com/app":  "example.com-app",
+		"gopkg.in/yaml.v3": "gopkg.in-yaml.v3",
+	} {
+		if ids[path] != want {
+			t.Errorf("id of %s = %q, want %q", path, ids[path], want)
+		}
+	}
+	if id := ids["github.com/a/b-c"]; !strings.HasPrefix(id, "github.com-a-b-c-") {
+		t.Errorf("id of github.com/a/b-c = %q, want its readable form with a hash suffix", id)
+	}
+}
+
+func TestGenerateSPDXUniqueIDs(t *testing.T) {
+	modFile, err := parser.ParseGoModReader(strings.NewReader(`module example.com/app
+
+go 1.24
+
+require (
+	github.com/a/b-c v1.0.0
+	github.com/a-b/c v1.0.0
+	github.com/a/b/c v1.0.0
+)
+`), "go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	depGraph, err := graph.BuildEnhancedDependencyGraph(modFile, filepath.Join(dir, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outputFile := filepath.Join(dir, "sbom.spdx.json")
+	if err := GenerateSPDX(depGraph, "v1.2.3", outputFile); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc SPDXDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := map[string]bool{doc.SPDXID: true}
+	for _, pkg := range doc.Packages {
+		if ids[pkg.SPDXID] {
+			t.Errorf("duplicate SPDXID %q for %s", pkg.SPDXID, pkg.Name)
+		}
+		ids[pkg.SPDXID] = true
+	}
+	if len(doc.Packages) != 4 {
+		t.Errorf("got %d packages, want 4", len(doc.Packages))
+	}
+	for _, relationship := range doc.Relationships {
+		if !ids[relationship.Element] || !ids[relationship.Related] {
+			t.Errorf("relationship %+v refers to an unknown element", relationship)
+		}
+	}
+}
+
+func TestGenerateSBOMToolVersionAndGoSumHash(t *testing.T) {
+	const hash = "h1:3Z9GsnYvcBN5ECK5F+jTOh2w+Vb1ELy3bPj/ICUfqDE="
+	modFile, err := parser.ParseGoModReader(strings.NewReader(`module example.com/app
+
+go 1.24
+
+require github.com/a/b v1.0.0
+`), "go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	goSumPath := filepath.Join(dir, "go.sum")
+	if err := os.WriteFile(goSumPath, []byte("github.com/a/b v1.0.0 "+hash+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	depGraph, err := graph.BuildEnhancedDependencyGraph(modFile, goSumPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cdxFile := filepath.Join(dir, "sbom.cdx.json")
+	if err := GenerateCycloneDX(depGraph, "v1.2.3", cdxFile); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(cdxFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `"hashes"`) {
+		t.Error("CycloneDX SBOM records the go.sum hash as a file hash")
+	}
+	var bom CycloneDXBOM
+	if err := json.Unmarshal(data, &bom); err != nil {
+		t.Fatal(err)
+	}
+	if tools := bom.Metadata.Tools; len(tools) != 1 || tools[0].Version != "v1.2.3" {
+		t.Errorf("CycloneDX tools = %+v, want goviz v1.2.3", tools)
+	}
+	if len(bom.Components) != 1 {
+		t.Fatalf("got %d components, want 1", len(bom.Components))
+	}
+	want := []CycloneDXProperty{{Name: "go.sum h1", Value: hash}}
+	if got := bom.Components[0].Properties; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("CycloneDX properties = %+v, want %+v", got, want)
+	}
+
+	spdxFile := filepath.Join(dir, "sbom.spdx.json")
+	if err := GenerateSPDX(depGraph, "v1.2.3", spdxFile); err != nil {
+		t.Fatal(err)
+	}
+	data, err = os.ReadFile(spdxFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `"checksums"`) {
+		t.Error("SPDX SBOM records the go.sum hash as a checksum")
+	}
+	var doc SPDXDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if creators := doc.CreationInfo.Creators; len(creators) != 1 || creators[0] != "Tool: goviz-v1.2.3" {
+		t.Errorf("SPDX creators = %q, want Tool: goviz-v1.2.3", creators)
+	}
+	if len(doc.Packages) != 2 {
+		t.Fatalf("got %d packages, want 2", len(doc.Packages))
+	}
+	if got := doc.Packages[1].Comment; got != "go.sum h1: "+hash {
+		t.Errorf("SPDX package comment = %q, want the go.sum h1 hash", got)
+	}
+}
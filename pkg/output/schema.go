@@ -0,0 +1,186 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SchemaVersion is the version of the structured report format, recorded in
+// the metadata of every report. It changes when fields are removed or
+// change meaning; added fields keep the version.
+const SchemaVersion = "1"
+
+// ReportSchemas maps the report names accepted by GenerateSchema to the
+// report types, for the schema command.
+var ReportSchemas = map[string]any{
+	"analyze":  DependencyReport{},
+	"multi":    MultiModuleReport{},
+	"tree":     TreeReport{},
+	"security": SecurityReport{},
+	"licenses": LicenseReport{},
+	"compare":  CompareReport{},
+	"doctor":   DoctorReport{},
+}
+
+// ReportSchemaNames returns the names of ReportSchemas, sorted.
+func ReportSchemaNames() []string {
+	names := make([]string, 0, len(ReportSchemas))
+	for name := range ReportSchemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Schema returns the JSON Schema of the report named name. It is derived
+// from the json struct tags of the report type, so it always matches what
+// the json format writes: omitempty fields are optional and nil slices and
+// maps of the other fields are allowed as null.
+func Schema(name string) (map[string]any, error) {
+	report, ok := ReportSchemas[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown report %q (supported: %s)", name, strings.Join(ReportSchemaNames(), ", "))
+	}
+
+	s := &schemaBuilder{defs: map[string]any{}}
+	root := s.structSchema(reflect.TypeOf(report))
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = fmt.Sprintf("goviz %s report", name)
+	if len(s.defs) > 0 {
+		root["$defs"] = s.defs
+	}
+	return root, nil
+}
+
+// GenerateSchema writes the JSON Schema of the report named name.
+func GenerateSchema(name, outputFile string) error {
+	schema, err := Schema(name)
+	if err != nil {
+		return err
+	}
+
+	jsonData, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON schema: %w", err)
+	}
+
+	if outputFile == "" {
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	if err := os.WriteFile(outputFile, append(jsonData, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write JSON schema: %w", err)
+	}
+
+	fmt.Printf("JSON schema generated: %s\n", outputFile)
+	return nil
+}
+
+type schemaBuilder struct {
+	defs map[string]any
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// typeSchema returns the schema of a field of type t. Named structs other
+// than the report itself go to $defs, which also handles recursive types
+// such as TreeNode.
+func (s *schemaBuilder) typeSchema(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		return map[string]any{"anyOf": []any{s.typeSchema(t.Elem()), map[string]any{"type": "null"}}}
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		name := t.Name()
+		if _, ok := s.defs[name]; !ok {
+			s.defs[name] = nil
+			s.defs[name] = s.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + name}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": s.typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": s.typeSchema(t.Elem())}
+	case reflect.Struct:
+		return s.structSchema(t)
+	default:
+		return map[string]any{}
+	}
+}
+
+func (s *schemaBuilder) structSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	s.addFields(t, properties, &required)
+
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		schema["required"] = required
+	}
+	// Pin the schema version so a validator rejects reports of another one.
+	if t == reflect.TypeOf(ReportMetadata{}) {
+		properties["schema_version"] = map[string]any{"type": "string", "const": SchemaVersion}
+	}
+	return schema
+}
+
+// addFields adds the properties of the exported fields of t the way
+// encoding/json marshals them, flattening embedded structs.
+func (s *schemaBuilder) addFields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			s.addFields(field.Type, properties, required)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := s.typeSchema(field.Type)
+		omitempty := strings.Contains(options, "omitempty")
+		if !omitempty {
+			*required = append(*required, name)
+			switch field.Type.Kind() {
+			case reflect.Slice, reflect.Map:
+				schema = map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
+			}
+		}
+		properties[name] = schema
+	}
+}
@@ -0,0 +1,169 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"goviz/pkg/graph"
+
+	"gopkg.in/yaml.v3"
+)
+
+type SecurityReport struct {
+	Metadata ReportMetadata    `json:"metadata" yaml:"metadata"`
+	Module   ModuleInfo        `json:"module" yaml:"module"`
+	Summary  map[string]int    `json:"summary" yaml:"summary"`
+	Findings []SecurityFinding `json:"findings" yaml:"findings"`
+
+	NotScanned []NotScannedModule `json:"not_scanned,omitempty" yaml:"not_scanned,omitempty"`
+}
+
+// NotScannedModule is a private module that remote vulnerability providers
+// were not queried for.
+type NotScannedModule struct {
+	Module    string   `json:"module" yaml:"module"`
+	Version   string   `json:"version" yaml:"version"`
+	Reason    string   `json:"reason" yaml:"reason"`
+	Providers []string `json:"providers" yaml:"providers"`
+}
+
+type SecurityFinding struct {
+	Module      string  `json:"module" yaml:"module"`
+	Version     string  `json:"version" yaml:"version"`
+	ID          string  `json:"id" yaml:"id"`
+	Severity    string  `json:"severity" yaml:"severity"`
+	CVSSScore   float64 `json:"cvss_score,omitempty" yaml:"cvss_score,omitempty"`
+	Description string  `json:"description" yaml:"description"`
+	FixedIn     string  `json:"fixed_in,omitempty" yaml:"fixed_in,omitempty"`
+	Provider    string  `json:"provider,omitempty" yaml:"provider,omitempty"`
+}
+
+// Key identifies a finding across runs. The version is deliberately left
+// out so a baseline entry is only cleared once the issue is fixed.
+func (f SecurityFinding) Key() string {
+	return f.Module + "|" + f.ID
+}
+
+// SecurityFindings lists every per-module security issue, sorted by module
+// and issue ID.
+func SecurityFindings(depGraph *graph.EnhancedDependencyGraph) []SecurityFinding {
+	findings := make([]SecurityFinding, 0)
+
+	for name, node := range depGraph.EnhancedNodes {
+		if name == depGraph.Root.Name {
+			continue
+		}
+		for _, issue := range node.SecurityIssues {
+			findings = append(findings, SecurityFinding{
+				Module:      node.Name,
+				Version:     node.Version,
+				ID:          issue.ID,
+				Severity:    issue.Severity,
+				CVSSScore:   issue.CVSSScore,
+				Description: issue.Description,
+				FixedIn:     issue.FixedIn,
+				Provider:    issue.Provider,
+			})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Module != findings[j].Module {
+			return findings[i].Module < findings[j].Module
+		}
+		return findings[i].ID < findings[j].ID
+	})
+
+	return findings
+}
+
+func BuildSecurityReport(depGraph *graph.EnhancedDependencyGraph, projectPath string, opts ReportOptions) SecurityReport {
+	findings := SecurityFindings(depGraph)
+
+	summary := make(map[string]int)
+	for _, finding := range findings {
+		summary[finding.Severity]++
+	}
+
+	var notScanned []NotScannedModule
+	for _, node := range depGraph.NotScannedNodes() {
+		notScanned = append(notScanned, NotScannedModule{
+			Module:    node.Name,
+			Version:   node.Version,
+			Reason:    "private, not scanned",
+			Providers: node.NotScannedBy,
+		})
+	}
+
+	return SecurityReport{
+		Metadata: newReportMetadata(opts),
+		Module: ModuleInfo{
+			Name:      depGraph.ModuleName,
+			GoVersion: depGraph.ModuleGoVersion,
+			Path:      projectPath,
+		},
+		Summary:    summary,
+		Findings:   findings,
+		NotScanned: notScanned,
+	}
+}
+
+func GenerateSecurityJSON(depGraph *graph.EnhancedDependencyGraph, outputFile, projectPath string, opts ReportOptions) error {
+	jsonData, err := json.MarshalIndent(BuildSecurityReport(depGraph, projectPath, opts), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	if outputFile == "" {
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	if err := os.WriteFile(outputFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write JSON file: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "JSON security report generated: %s\n", outputFile)
+	return nil
+}
+
+func GenerateSecurityYAML(depGraph *graph.EnhancedDependencyGraph, outputFile, projectPath string, opts ReportOptions) error {
+	yamlData, err := yaml.Marshal(BuildSecurityReport(depGraph, projectPath, opts))
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+
+	if outputFile == "" {
+		fmt.Print(string(yamlData))
+		return nil
+	}
+
+	if err := os.WriteFile(outputFile, yamlData, 0644); err != nil {
+		return fmt.Errorf("failed to write YAML file: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "YAML security report generated: %s\n", outputFile)
+	return nil
+}
+
+// LoadSecurityBaseline reads a JSON security report and returns the keys of
+// the findings it contains.
+func LoadSecurityBaseline(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var report SecurityReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+
+	known := make(map[string]bool, len(report.Findings))
+	for _, finding := range report.Findings {
+		known[finding.Key()] = true
+	}
+	return known, nil
+}
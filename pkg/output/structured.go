@@ -1,3 +1,7 @@
+// Package output renders dependency graphs and reports. Generators keep no
+// state between calls and write only to the files they are given, or to
+// per-call temporary files, so reports for separate graphs can be produced
+// concurrently.
 package output
 
 import (
@@ -12,19 +16,37 @@ import (
 )
 
 type DependencyReport struct {
-	Metadata        ReportMetadata          `json:"metadata" yaml:"metadata"`
-	Module          ModuleInfo              `json:"module" yaml:"module"`
-	Statistics      map[string]any          `json:"statistics" yaml:"statistics"`
-	Dependencies    []DependencyInfo        `json:"dependencies" yaml:"dependencies"`
-	Conflicts       []graph.VersionConflict `json:"conflicts,omitempty" yaml:"conflicts,omitempty"`
-	SecurityIssues  []graph.SecurityIssue   `json:"security_issues,omitempty" yaml:"security_issues,omitempty"`
-	LicensesSummary map[string]int          `json:"licenses_summary" yaml:"licenses_summary"`
+	Metadata        ReportMetadata              `json:"metadata" yaml:"metadata"`
+	Module          ModuleInfo                  `json:"module" yaml:"module"`
+	Statistics      map[string]any              `json:"statistics" yaml:"statistics"`
+	Risk            graph.RiskScore             `json:"risk" yaml:"risk"`
+	Changes         []graph.ModuleChange        `json:"changes,omitempty" yaml:"changes,omitempty"`
+	Footprints      []graph.DependencyFootprint `json:"footprints,omitempty" yaml:"footprints,omitempty"`
+	Excluded        []string                    `json:"excluded,omitempty" yaml:"excluded,omitempty"`
+	Dependencies    []DependencyInfo            `json:"dependencies" yaml:"dependencies"`
+	Conflicts       []graph.VersionConflict     `json:"conflicts,omitempty" yaml:"conflicts,omitempty"`
+	SecurityIssues  []graph.SecurityIssue       `json:"security_issues,omitempty" yaml:"security_issues,omitempty"`
+	LicensesSummary map[string]int              `json:"licenses_summary" yaml:"licenses_summary"`
+	Godebug         []graph.GodebugSetting      `json:"godebug,omitempty" yaml:"godebug,omitempty"`
+	GroupBy         string                      `json:"group_by,omitempty" yaml:"group_by,omitempty"`
+	Groups          []graph.DependencyGroup     `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Warnings        []graph.Warning             `json:"warnings,omitempty" yaml:"warnings,omitempty"`
 }
 
 type ReportMetadata struct {
-	GeneratedAt time.Time `json:"generated_at" yaml:"generated_at"`
-	Tool        string    `json:"tool" yaml:"tool"`
-	Version     string    `json:"version" yaml:"version"`
+	GeneratedAt   time.Time   `json:"generated_at" yaml:"generated_at"`
+	Tool          string      `json:"tool" yaml:"tool"`
+	Version       string      `json:"version" yaml:"version"`
+	SchemaVersion string      `json:"schema_version" yaml:"schema_version"`
+	Invocation    *Invocation `json:"invocation,omitempty" yaml:"invocation,omitempty"`
+}
+
+// Invocation records how a report was produced so stored reports can be
+// compared and reproduced.
+type Invocation struct {
+	Command string            `json:"command" yaml:"command"`
+	Args    []string          `json:"args,omitempty" yaml:"args,omitempty"`
+	Flags   map[string]string `json:"flags,omitempty" yaml:"flags,omitempty"`
 }
 
 type ModuleInfo struct {
@@ -33,20 +55,58 @@ type ModuleInfo struct {
 	Path      string `json:"path" yaml:"path"`
 }
 
+// ReportOptions controls what dependency reports include.
+type ReportOptions struct {
+	// IncludeRoot lists the main module itself as the first dependency,
+	// marked with Root.
+	IncludeRoot bool
+
+	// Invocation is recorded in the report metadata when set.
+	Invocation *Invocation
+
+	// Sort orders the dependency list (name, version, severity or risk); the
+	// default is by module path.
+	Sort string
+
+	// GroupBy adds the dependencies grouped by license, org or severity.
+	GroupBy string
+}
+
+func newReportMetadata(opts ReportOptions) ReportMetadata {
+	return ReportMetadata{
+		GeneratedAt:   time.Now(),
+		Tool:          "goviz",
+		Version:       "v0.1.0",
+		SchemaVersion: SchemaVersion,
+		Invocation:    opts.Invocation,
+	}
+}
+
 type DependencyInfo struct {
 	Name            string                  `json:"name" yaml:"name"`
 	Version         string                  `json:"version" yaml:"version"`
+	Root            bool                    `json:"root,omitempty" yaml:"root,omitempty"`
+	SelectedVersion string                  `json:"selected_version,omitempty" yaml:"selected_version,omitempty"`
 	Direct          bool                    `json:"direct" yaml:"direct"`
+	DefinedAtLine   int                     `json:"defined_at_line,omitempty" yaml:"defined_at_line,omitempty"`
+	Comment         string                  `json:"comment,omitempty" yaml:"comment,omitempty"`
 	Hash            string                  `json:"hash,omitempty" yaml:"hash,omitempty"`
 	License         string                  `json:"license,omitempty" yaml:"license,omitempty"`
+	RepoURL         string                  `json:"repo_url,omitempty" yaml:"repo_url,omitempty"`
 	Conflicts       []graph.VersionConflict `json:"conflicts,omitempty" yaml:"conflicts,omitempty"`
 	SecurityIssues  []graph.SecurityIssue   `json:"security_issues,omitempty" yaml:"security_issues,omitempty"`
 	IsOutdated      bool                    `json:"is_outdated,omitempty" yaml:"is_outdated,omitempty"`
 	UpdateAvailable string                  `json:"update_available,omitempty" yaml:"update_available,omitempty"`
+	Deprecated      string                  `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	IsPseudoVersion bool                    `json:"is_pseudo_version,omitempty" yaml:"is_pseudo_version,omitempty"`
+	TestOnly        bool                    `json:"test_only,omitempty" yaml:"test_only,omitempty"`
+	Private         bool                    `json:"private,omitempty" yaml:"private,omitempty"`
+	NotScannedBy    []string                `json:"not_scanned_by,omitempty" yaml:"not_scanned_by,omitempty"`
+	CommitTime      *time.Time              `json:"commit_time,omitempty" yaml:"commit_time,omitempty"`
 }
 
-func GenerateJSON(depGraph *graph.EnhancedDependencyGraph, outputFile, projectPath string) error {
-	report := buildDependencyReport(depGraph, projectPath)
+func GenerateJSON(depGraph *graph.EnhancedDependencyGraph, outputFile, projectPath string, opts ReportOptions) error {
+	report := buildDependencyReport(depGraph, projectPath, opts)
 
 	jsonData, err := json.MarshalIndent(report, "", "  ")
 	if err != nil {
@@ -66,8 +126,8 @@ func GenerateJSON(depGraph *graph.EnhancedDependencyGraph, outputFile, projectPa
 	return nil
 }
 
-func GenerateYAML(depGraph *graph.EnhancedDependencyGraph, outputFile, projectPath string) error {
-	report := buildDependencyReport(depGraph, projectPath)
+func GenerateYAML(depGraph *graph.EnhancedDependencyGraph, outputFile, projectPath string, opts ReportOptions) error {
+	report := buildDependencyReport(depGraph, projectPath, opts)
 
 	yamlData, err := yaml.Marshal(report)
 	if err != nil {
@@ -87,43 +147,99 @@ func GenerateYAML(depGraph *graph.EnhancedDependencyGraph, outputFile, projectPa
 	return nil
 }
 
-func buildDependencyReport(depGraph *graph.EnhancedDependencyGraph, projectPath string) DependencyReport {
+func buildDependencyReport(depGraph *graph.EnhancedDependencyGraph, projectPath string, opts ReportOptions) DependencyReport {
 	var dependencies []DependencyInfo
+	eachDependency(depGraph, opts, func(dep DependencyInfo) error {
+		dependencies = append(dependencies, dep)
+		return nil
+	})
+
+	var groups []graph.DependencyGroup
+	if opts.GroupBy != "" {
+		groups, _ = depGraph.GroupDependencies(opts.GroupBy)
+	}
+
+	return DependencyReport{
+		Metadata: newReportMetadata(opts),
+		Module: ModuleInfo{
+			Name:      depGraph.ModuleName,
+			GoVersion: depGraph.ModuleGoVersion,
+			Path:      projectPath,
+		},
+		Statistics:      depGraph.GetStatistics(),
+		Risk:            depGraph.RiskScore(),
+		Changes:         depGraph.Changes,
+		Footprints:      depGraph.DependencyFootprints(),
+		Excluded:        depGraph.Excluded,
+		Dependencies:    dependencies,
+		Conflicts:       depGraph.Conflicts,
+		SecurityIssues:  depGraph.SecurityIssues,
+		LicensesSummary: depGraph.LicensesSummary,
+		Godebug:         depGraph.GodebugSettings,
+		GroupBy:         opts.GroupBy,
+		Groups:          groups,
+		Warnings:        depGraph.SortedWarnings(),
+	}
+}
 
-	for name, enhancedNode := range depGraph.EnhancedNodes {
-		if name == depGraph.Root.Name {
-			continue
+// eachDependency calls fn with the report entry of every dependency, in the
+// order of opts.Sort, stopping at the first error.
+func eachDependency(depGraph *graph.EnhancedDependencyGraph, opts ReportOptions, fn func(DependencyInfo) error) error {
+	if root, exists := depGraph.EnhancedNodes[depGraph.Root.Name]; exists && opts.IncludeRoot {
+		err := fn(DependencyInfo{
+			Name:         depGraph.ModuleName,
+			Version:      root.Version,
+			Root:         true,
+			Hash:         root.Hash,
+			License:      root.License,
+			RepoURL:      root.RepoURL,
+			Private:      root.Private,
+			NotScannedBy: root.NotScannedBy,
+		})
+		if err != nil {
+			return err
 		}
+	}
+
+	var selected map[string]string
+	if depGraph.Requirements != nil {
+		selected = depGraph.SelectedVersions()
+	}
 
+	sortKey := opts.Sort
+	if sortKey == "" {
+		sortKey = "name"
+	}
+
+	for _, enhancedNode := range depGraph.SortedNodes(sortKey) {
 		dep := DependencyInfo{
 			Name:            enhancedNode.Name,
 			Version:         enhancedNode.Version,
+			SelectedVersion: selected[enhancedNode.Name],
 			Direct:          enhancedNode.Direct,
+			DefinedAtLine:   enhancedNode.DefinedAtLine,
+			Comment:         enhancedNode.Comment,
 			Hash:            enhancedNode.Hash,
 			License:         enhancedNode.License,
+			RepoURL:         enhancedNode.RepoURL,
 			Conflicts:       enhancedNode.Conflicts,
 			SecurityIssues:  enhancedNode.SecurityIssues,
 			IsOutdated:      enhancedNode.IsOutdated,
 			UpdateAvailable: enhancedNode.UpdateAvailable,
+			Deprecated:      enhancedNode.Deprecated,
+			IsPseudoVersion: enhancedNode.IsPseudoVersion,
+			TestOnly:        enhancedNode.TestOnly,
+			Private:         enhancedNode.Private,
+			NotScannedBy:    enhancedNode.NotScannedBy,
+		}
+		if !enhancedNode.PseudoVersionTime.IsZero() {
+			commitTime := enhancedNode.PseudoVersionTime
+			dep.CommitTime = &commitTime
+		}
+		if err := fn(dep); err != nil {
+			return err
 		}
-		dependencies = append(dependencies, dep)
 	}
 
-	return DependencyReport{
-		Metadata: ReportMetadata{
-			GeneratedAt: time.Now(),
-			Tool:        "goviz",
-			Version:     "v0.1.0",
-		},
-		Module: ModuleInfo{
-			Name:      depGraph.ModuleName,
-			GoVersion: depGraph.ModuleGoVersion,
-			Path:      projectPath,
-		},
-		Statistics:      depGraph.GetStatistics(),
-		Dependencies:    dependencies,
-		Conflicts:       depGraph.Conflicts,
-		SecurityIssues:  depGraph.SecurityIssues,
-		LicensesSummary: depGraph.LicensesSummary,
-	}
+	return nil
 }
@@ -0,0 +1,23 @@
+package output
+
+import (
+	"testing"
+
+	"goviz/pkg/graph"
+)
+
+func TestBuildDependencyReportStable(t *testing.T) {
+	for _, dir := range []string{"testdata/alpha", "testdata/beta"} {
+		for _, key := range graph.SortKeys {
+			for _, groupBy := range []string{"", "license", "severity"} {
+				opts := ReportOptions{IncludeRoot: true, Sort: key, GroupBy: groupBy}
+				want := runPipeline(t, dir, opts).report
+				for range 20 {
+					if got := runPipeline(t, dir, opts).report; got != want {
+						t.Fatalf("%s sorted by %s, grouped by %q: report differs between runs:\n got: %s\nwant: %s", dir, key, groupBy, got, want)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"goviz/pkg/graph"
+)
+
+// svgNodeGroup matches the group Graphviz writes for every node, whose
+// title is the DOT node ID.
+var svgNodeGroup = regexp.MustCompile(`<g id="([^"]*)" class="node">(\s*)<title>([^<]*)</title>`)
+
+var svgOpenTag = regexp.MustCompile(`<svg\b[^>]*>`)
+
+// EmbedSVGMetadata adds the analysis data to an SVG rendered by GenerateSVG,
+// so the picture doubles as a data source for web pages: the
+// DependencyReport of the json format as JSON inside <metadata
+// id="goviz-report">, and data-module, data-version, data-direct,
+// data-license, data-security-issues, data-max-severity and data-outdated
+// attributes on the group of every module node.
+func EmbedSVGMetadata(svgFile string, depGraph *graph.EnhancedDependencyGraph, projectPath string, opts ReportOptions) error {
+	content, err := os.ReadFile(svgFile)
+	if err != nil {
+		return fmt.Errorf("failed to read SVG file: %w", err)
+	}
+
+	report, err := json.Marshal(buildDependencyReport(depGraph, projectPath, opts))
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	svg := string(content)
+	openTag := svgOpenTag.FindStringIndex(svg)
+	if openTag == nil {
+		return fmt.Errorf("failed to embed metadata: no <svg> element in %s", svgFile)
+	}
+	// encoding/json escapes '>', so the report cannot end the CDATA section.
+	metadata := fmt.Sprintf("\n<metadata id=\"goviz-report\" data-format=\"application/json\"><![CDATA[%s]]></metadata>", report)
+	svg = svg[:openTag[1]] + metadata + svg[openTag[1]:]
+
+	nodes := dotNodes(depGraph)
+
+	svg = svgNodeGroup.ReplaceAllStringFunc(svg, func(group string) string {
+		match := svgNodeGroup.FindStringSubmatch(group)
+		node, ok := nodes[html.UnescapeString(match[3])]
+		if !ok {
+			return group
+		}
+		return fmt.Sprintf(`<g id="%s" class="node"%s>%s<title>%s</title>`, match[1], svgNodeAttrs(node), match[2], match[3])
+	})
+
+	if err := os.WriteFile(svgFile, []byte(svg), 0644); err != nil {
+		return fmt.Errorf("failed to write SVG file: %w", err)
+	}
+	return nil
+}
+
+func svgNodeAttrs(node *graph.EnhancedNode) string {
+	attrs := [][2]string{
+		{"data-module", node.Name},
+		{"data-version", node.Version},
+		{"data-direct", strconv.FormatBool(node.Direct)},
+		{"data-license", node.License},
+		{"data-security-issues", strconv.Itoa(len(node.SecurityIssues))},
+	}
+	if severity := graph.MaxSeverity(node.SecurityIssues); severity != "" {
+		attrs = append(attrs, [2]string{"data-max-severity", severity})
+	}
+	if node.IsOutdated {
+		attrs = append(attrs, [2]string{"data-outdated", node.UpdateAvailable})
+	}
+
+	var b strings.Builder
+	for _, attr := range attrs {
+		fmt.Fprintf(&b, ` %s="%s"`, attr[0], html.EscapeString(attr[1]))
+	}
+	return b.String()
+}
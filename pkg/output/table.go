@@ -0,0 +1,124 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"goviz/pkg/graph"
+
+	"github.com/fatih/color"
+)
+
+// TableOptions controls the aligned table written by WriteTable.
+type TableOptions struct {
+	// Sort is the row order (see graph.SortKeys).
+	Sort string
+	// ModuleWidth truncates module paths longer than this many characters;
+	// 0 disables truncation.
+	ModuleWidth int
+	// NoColor disables colors regardless of color.NoColor, e.g. when
+	// writing to a file.
+	NoColor bool
+	// Offset skips this many rows and Limit caps the rows written, 0 for
+	// no cap. A footer tells which rows were shown when a page is cut.
+	Offset int
+	Limit  int
+}
+
+// WriteTable writes one aligned row per dependency (module, version,
+// direct, license, issues). Only the last column is colored so that escape
+// sequences never affect the alignment.
+func WriteTable(w io.Writer, nodes []*graph.EnhancedNode, opts TableOptions) error {
+	graph.SortNodes(nodes, opts.Sort)
+
+	red := color.New(color.FgRed, color.Bold)
+	green := color.New(color.FgGreen)
+	yellow := color.New(color.FgYellow)
+	if opts.NoColor {
+		red.DisableColor()
+		green.DisableColor()
+		yellow.DisableColor()
+	}
+
+	total := len(nodes)
+	start, end := Page(total, opts.Offset, opts.Limit)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MODULE\tVERSION\tDIRECT\tLICENSE\tISSUES")
+
+	for _, node := range nodes[start:end] {
+		direct := "no"
+		if node.Direct {
+			direct = "yes"
+		}
+		license := node.License
+		if license == "" {
+			license = "Unknown"
+		}
+
+		issues := green.Sprint("-")
+		switch {
+		case len(node.SecurityIssues) > 0:
+			issues = red.Sprintf("%d (%s)", len(node.SecurityIssues), graph.MaxSeverity(node.SecurityIssues))
+		case len(node.NotScannedBy) > 0:
+			issues = yellow.Sprint("private, not scanned")
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", truncateModule(node.Name, opts.ModuleWidth), node.Version, direct, license, issues)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	footer := PageFooter(start, end, total, opts.Offset)
+	if footer == "" {
+		return nil
+	}
+	_, err := fmt.Fprintln(w, "\n"+footer)
+	return err
+}
+
+// Page returns the bounds of the rows out of total that are skipped by
+// offset and capped by limit, 0 for no cap.
+func Page(total, offset, limit int) (start, end int) {
+	start = min(offset, total)
+	end = total
+	if limit > 0 {
+		end = min(start+limit, total)
+	}
+	return start, end
+}
+
+// PageFooter tells which of total dependencies the page from start to end,
+// as returned by Page for offset, shows, or returns "" when it shows all
+// of them.
+func PageFooter(start, end, total, offset int) string {
+	if start == 0 && end == total {
+		return ""
+	}
+	if start == end {
+		return fmt.Sprintf("No dependencies at offset %d of %d", offset, total)
+	}
+	footer := fmt.Sprintf("Showing %d-%d of %d dependencies", start+1, end, total)
+	if end < total {
+		footer += fmt.Sprintf(" (next page: --offset %d)", end)
+	}
+	return footer
+}
+
+// truncateModule shortens long module paths in the middle, keeping the
+// host and the last path elements, which are the most distinctive parts.
+func truncateModule(name string, width int) string {
+	runes := []rune(name)
+	if width <= 0 || len(runes) <= width {
+		return name
+	}
+	if width < 3 {
+		return string(runes[:width])
+	}
+	head := (width - 1) / 2
+	tail := width - 1 - head
+	return string(runes[:head]) + "…" + string(runes[len(runes)-tail:])
+}
@@ -0,0 +1,125 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"goviz/pkg/graph"
+
+	"github.com/fatih/color"
+	"golang.org/x/mod/semver"
+)
+
+// ParseTemplate reads a text/template file for GenerateTemplate. Besides the
+// builtins, templates can use:
+//
+//	severityColor SEVERITY          the severity, colored like the text reports
+//	maxSeverity ISSUES              most severe severity among security issues
+//	sortBy KEY DEPENDENCIES         dependencies sorted by name, version,
+//	                                severity, risk or license
+//	join SEP LIST, lower S, upper S string helpers
+//	add A B                         integer sum
+func ParseTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs(false)).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// GenerateTemplate renders the DependencyReport of depGraph, the data of
+// the JSON and YAML reports, through tmpl. Colors are only used on stdout.
+func GenerateTemplate(tmpl *template.Template, depGraph *graph.EnhancedDependencyGraph, outputFile, projectPath string, opts ReportOptions) error {
+	report := buildDependencyReport(depGraph, projectPath, opts)
+
+	var buf bytes.Buffer
+	if err := tmpl.Funcs(templateFuncs(outputFile != "")).Execute(&buf, report); err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+
+	if outputFile == "" {
+		fmt.Print(buf.String())
+		return nil
+	}
+
+	if err := os.WriteFile(outputFile, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write template output: %w", err)
+	}
+
+	fmt.Printf("Template report generated: %s\n", outputFile)
+	return nil
+}
+
+func templateFuncs(noColor bool) template.FuncMap {
+	return template.FuncMap{
+		"severityColor": func(severity string) string {
+			var c *color.Color
+			switch severity {
+			case "CRITICAL", "HIGH":
+				c = color.New(color.FgRed, color.Bold)
+			case "MEDIUM":
+				c = color.New(color.FgYellow, color.Bold)
+			case "LOW":
+				c = color.New(color.FgGreen, color.Bold)
+			default:
+				return severity
+			}
+			if noColor {
+				c.DisableColor()
+			}
+			return c.Sprint(severity)
+		},
+		"maxSeverity": graph.MaxSeverity,
+		"sortBy":      sortDependencies,
+		"join":        strings.Join,
+		"lower":       strings.ToLower,
+		"upper":       strings.ToUpper,
+		"add":         func(a, b int) int { return a + b },
+	}
+}
+
+// sortDependencies returns a sorted copy of deps for the sortBy template
+// function.
+func sortDependencies(key string, deps []DependencyInfo) ([]DependencyInfo, error) {
+	switch key {
+	case "name", "version", "severity", "risk", "license":
+	default:
+		return nil, fmt.Errorf("sortBy: unsupported key %q (supported: name, version, severity, risk, license)", key)
+	}
+
+	sorted := append([]DependencyInfo(nil), deps...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		switch key {
+		case "version":
+			if c := semver.Compare(a.Version, b.Version); c != 0 {
+				return c < 0
+			}
+		case "severity", "risk":
+			ra := graph.SeverityRank(graph.MaxSeverity(a.SecurityIssues))
+			rb := graph.SeverityRank(graph.MaxSeverity(b.SecurityIssues))
+			if ra != rb {
+				return ra > rb
+			}
+			if key == "risk" && a.IsOutdated != b.IsOutdated {
+				return a.IsOutdated
+			}
+		case "license":
+			if a.License != b.License {
+				return a.License < b.License
+			}
+		}
+		return a.Name < b.Name
+	})
+	return sorted, nil
+}
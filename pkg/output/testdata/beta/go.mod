@@ -0,0 +1,14 @@
+module example.com/beta
+
+go 1.24
+
+require (
+	example.com/tool v1.1.0-rc.1
+	github.com/fatih/color v1.18.0
+	github.com/gorilla/websocket v1.4.2
+)
+
+require (
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+)
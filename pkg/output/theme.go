@@ -0,0 +1,87 @@
+package output
+
+import "fmt"
+
+// DOTTheme is the palette of the DOT, PNG and SVG graphs. Modules with
+// security issues keep a red or orange fill in every theme.
+type DOTTheme struct {
+	Background string
+	Font       string
+	Border     string
+	Edge       string
+	Legend     string
+
+	Main          string
+	Direct        string
+	Indirect      string
+	DirectIssue   string
+	IndirectIssue string
+}
+
+// DOTThemes are the themes accepted by DOTOptions.Theme. The dark theme
+// suits dark-mode slides, mono prints in grayscale apart from the security
+// colors.
+var DOTThemes = map[string]DOTTheme{
+	"light": {
+		Background: "white",
+		Font:       "black",
+		Border:     "black",
+		Edge:       "black",
+		Legend:     "lightgrey",
+
+		Main:          "lightblue",
+		Direct:        "lightgreen",
+		Indirect:      "lightgray",
+		DirectIssue:   "red",
+		IndirectIssue: "orange",
+	},
+	"dark": {
+		Background: "#1e1e1e",
+		Font:       "#f0f0f0",
+		Border:     "#9e9e9e",
+		Edge:       "#bdbdbd",
+		Legend:     "#2d2d2d",
+
+		Main:          "#1f4e79",
+		Direct:        "#2e6b30",
+		Indirect:      "#424242",
+		DirectIssue:   "#c62828",
+		IndirectIssue: "#b85c00",
+	},
+	"mono": {
+		Background: "white",
+		Font:       "black",
+		Border:     "black",
+		Edge:       "black",
+		Legend:     "gray95",
+
+		Main:          "gray70",
+		Direct:        "gray90",
+		Indirect:      "white",
+		DirectIssue:   "red",
+		IndirectIssue: "orange",
+	},
+}
+
+// NodeShapes are the Graphviz node shapes accepted by DOTOptions.NodeShape.
+var NodeShapes = []string{"box", "rect", "ellipse", "oval", "circle", "plain", "note", "tab", "folder", "component", "box3d", "hexagon", "octagon"}
+
+func (o DOTOptions) theme() DOTTheme {
+	if theme, ok := DOTThemes[o.Theme]; ok {
+		return theme
+	}
+	return DOTThemes["light"]
+}
+
+func (o DOTOptions) nodeShape() string {
+	if o.NodeShape == "" {
+		return "box"
+	}
+	return o.NodeShape
+}
+
+// dotColor quotes a color for a DOT attribute; "#rrggbb" values must be
+// quoted.
+func dotColor(color string) string {
+	return fmt.Sprintf("%q", color)
+}
@@ -0,0 +1,98 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"goviz/pkg/graph"
+)
+
+// TreeReport is the nested dependency tree written by the tree-json format.
+type TreeReport struct {
+	Metadata ReportMetadata `json:"metadata"`
+	Module   ModuleInfo     `json:"module"`
+	Tree     *TreeNode      `json:"tree"`
+}
+
+// TreeNode is a module in the dependency tree. A module reached along
+// several paths is only expanded the first time; later occurrences are
+// marked Deduped, and occurrences that would close a cycle are marked Cycle.
+type TreeNode struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	Direct         bool        `json:"direct,omitempty"`
+	License        string      `json:"license,omitempty"`
+	Comment        string      `json:"comment,omitempty"`
+	SecurityIssues int         `json:"security_issues,omitempty"`
+	Deduped        bool        `json:"deduped,omitempty"`
+	Cycle          bool        `json:"cycle,omitempty"`
+	Children       []*TreeNode `json:"children,omitempty"`
+}
+
+// BuildDependencyTree returns the tree of depGraph rooted at the main
+// module, following ChildNodes: real requirement edges once the
+// requirement graph is loaded, otherwise the main module with every
+// dependency as a direct child.
+func BuildDependencyTree(depGraph *graph.EnhancedDependencyGraph) *TreeNode {
+	root := depGraph.EnhancedNodes[depGraph.Root.Name]
+	return buildTreeNode(depGraph, root, map[string]bool{}, map[string]bool{})
+}
+
+func buildTreeNode(depGraph *graph.EnhancedDependencyGraph, node *graph.EnhancedNode, ancestors, expanded map[string]bool) *TreeNode {
+	treeNode := &TreeNode{
+		Name:           node.Name,
+		Version:        node.Version,
+		Direct:         node.Direct && node.Name != depGraph.Root.Name,
+		License:        node.License,
+		Comment:        node.Comment,
+		SecurityIssues: len(node.SecurityIssues),
+	}
+
+	switch {
+	case ancestors[node.Name]:
+		treeNode.Cycle = true
+		return treeNode
+	case expanded[node.Name]:
+		treeNode.Deduped = len(depGraph.ChildNodes(node.Name)) > 0
+		return treeNode
+	}
+	expanded[node.Name] = true
+
+	ancestors[node.Name] = true
+	for _, child := range depGraph.ChildNodes(node.Name) {
+		treeNode.Children = append(treeNode.Children, buildTreeNode(depGraph, child, ancestors, expanded))
+	}
+	delete(ancestors, node.Name)
+
+	return treeNode
+}
+
+func GenerateTreeJSON(depGraph *graph.EnhancedDependencyGraph, outputFile, projectPath string, opts ReportOptions) error {
+	report := TreeReport{
+		Metadata: newReportMetadata(opts),
+		Module: ModuleInfo{
+			Name:      depGraph.ModuleName,
+			GoVersion: depGraph.ModuleGoVersion,
+			Path:      projectPath,
+		},
+		Tree: BuildDependencyTree(depGraph),
+	}
+
+	jsonData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	if outputFile == "" {
+		fmt.Print(string(jsonData))
+		return nil
+	}
+
+	if err := os.WriteFile(outputFile, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write JSON file: %w", err)
+	}
+
+	fmt.Printf("JSON tree generated: %s\n", outputFile)
+	return nil
+}
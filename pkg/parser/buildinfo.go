@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"debug/buildinfo"
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// BinaryFiles are the go.mod and go.sum equivalent of the module build info
+// embedded in a Go binary.
+type BinaryFiles struct {
+	// GoVersion is the toolchain the binary was built with, e.g. go1.24.5.
+	GoVersion string
+	GoMod     []byte
+	GoSum     []byte
+}
+
+// ReadBinary extracts the module build info of a Go binary. Every module
+// linked into the binary becomes an indirect requirement of GoMod, since
+// the build info does not record which of them the main module required
+// directly. Module
+// replacements become replace directives and the recorded hashes go.sum
+// lines. The go directive is left out, since only the toolchain version is
+// recorded.
+func ReadBinary(filename string) (*BinaryFiles, error) {
+	info, err := buildinfo.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read build info from %s: %w", filename, err)
+	}
+
+	mainPath := info.Main.Path
+	if mainPath == "" {
+		mainPath = info.Path
+	}
+	if mainPath == "" {
+		return nil, fmt.Errorf("%s has no main module in its build info", filename)
+	}
+
+	modFile := new(modfile.File)
+	if err := modFile.AddModuleStmt(mainPath); err != nil {
+		return nil, fmt.Errorf("invalid main module %s: %w", mainPath, err)
+	}
+
+	var goSum strings.Builder
+	for _, dep := range info.Deps {
+		if err := module.Check(dep.Path, dep.Version); err != nil {
+			return nil, fmt.Errorf("invalid module %s@%s in build info: %w", dep.Path, dep.Version, err)
+		}
+		modFile.AddNewRequire(dep.Path, dep.Version, true)
+		if dep.Sum != "" {
+			fmt.Fprintf(&goSum, "%s %s %s\n", dep.Path, dep.Version, dep.Sum)
+		}
+
+		if dep.Replace == nil {
+			continue
+		}
+		if err := modFile.AddReplace(dep.Path, dep.Version, dep.Replace.Path, dep.Replace.Version); err != nil {
+			return nil, fmt.Errorf("invalid replacement of %s in build info: %w", dep.Path, err)
+		}
+		if dep.Replace.Sum != "" {
+			fmt.Fprintf(&goSum, "%s %s %s\n", dep.Replace.Path, dep.Replace.Version, dep.Replace.Sum)
+		}
+	}
+
+	modFile.Cleanup()
+	goMod, err := modFile.Format()
+	if err != nil {
+		return nil, fmt.Errorf("failed to format go.mod: %w", err)
+	}
+
+	return &BinaryFiles{
+		GoVersion: info.GoVersion,
+		GoMod:     goMod,
+		GoSum:     []byte(goSum.String()),
+	}, nil
+}
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FindModules walks root and returns every directory containing a go.mod
+// file. vendor and testdata directories, as well as hidden directories, are
+// skipped. A positive maxDepth limits how many directory levels below root
+// are visited.
+func FindModules(root string, maxDepth int) ([]string, error) {
+	var dirs []string
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != root && skipModuleDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			if maxDepth > 0 && depth(root, path) > maxDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.Name() == "go.mod" {
+			dirs = append(dirs, filepath.Dir(path))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+func skipModuleDir(name string) bool {
+	return name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+func depth(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(filepath.ToSlash(rel), "/") + 1
+}
@@ -2,24 +2,42 @@ package parser
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
 )
 
+// ErrGoSumNotFound is returned by ParseGoSum, together with an empty entry
+// map, when the go.sum file does not exist.
+var ErrGoSumNotFound = errors.New("go.sum not found")
+
+// GoSumEntry holds the go.sum hashes of a module version: Hash of the
+// module zip and GoModHash of its go.mod file. Modules whose packages are
+// not needed for the build only have GoModHash.
 type GoSumEntry struct {
 	ModulePath string
 	Version    string
 	Hash       string
+	GoModHash  string
 }
 
+// ParseGoSum returns the entries of go.sum keyed by GoSumKey. The zip and
+// go.mod lines of a module version are merged into one entry, in whatever
+// order they appear.
 func ParseGoSum(path string) (map[string]GoSumEntry, error) {
 	entries := make(map[string]GoSumEntry)
 
 	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, ErrGoSumNotFound
+	}
 	if err != nil {
-
-		return entries, nil
+		return nil, fmt.Errorf("failed to open go.sum: %w", err)
 	}
 	defer file.Close()
 
@@ -36,16 +54,19 @@ func ParseGoSum(path string) (map[string]GoSumEntry, error) {
 		}
 
 		modulePath := parts[0]
-		version := parts[1]
+		version, goMod := strings.CutSuffix(parts[1], "/go.mod")
 		hash := parts[2]
 
-		if !strings.HasSuffix(version, "/go.mod") {
-			entries[modulePath+"@"+version] = GoSumEntry{
-				ModulePath: modulePath,
-				Version:    version,
-				Hash:       hash,
-			}
+		key := GoSumKey(modulePath, version)
+		entry := entries[key]
+		entry.ModulePath = modulePath
+		entry.Version = version
+		if goMod {
+			entry.GoModHash = hash
+		} else {
+			entry.Hash = hash
 		}
+		entries[key] = entry
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -55,21 +76,111 @@ func ParseGoSum(path string) (map[string]GoSumEntry, error) {
 	return entries, nil
 }
 
+// GoSumKey returns the key of the entry of path at version in the map
+// returned by ParseGoSum. Versions are canonicalized, so v1.2 matches
+// v1.2.0; build metadata is dropped except for +incompatible, which is part
+// of the module version.
+func GoSumKey(path, version string) string {
+	if canonical := module.CanonicalVersion(version); canonical != "" {
+		version = canonical
+	}
+	return path + "@" + version
+}
+
+// LookupGoSum returns the go.sum entry of path at version. A hand-edited
+// go.mod or go.sum can disagree with the other on the +incompatible suffix
+// of a v2+ module without a major version suffix; both spellings name the
+// same code, so the entry of the other one is returned when there is no
+// exact match.
+func LookupGoSum(entries map[string]GoSumEntry, path, version string) (GoSumEntry, bool) {
+	if entry, ok := entries[GoSumKey(path, version)]; ok {
+		return entry, true
+	}
+
+	if trimmed, ok := strings.CutSuffix(version, "+incompatible"); ok {
+		version = trimmed
+	} else {
+		version += "+incompatible"
+	}
+	entry, ok := entries[GoSumKey(path, version)]
+	return entry, ok
+}
+
+// GetTransitiveDependencies returns the go.sum entry of every module that is
+// not in directDeps and whose code is part of the build, sorted by path.
+// When go.sum holds several versions of a module, the highest one is
+// returned, so the result does not depend on map iteration order.
 func GetTransitiveDependencies(goSumEntries map[string]GoSumEntry, directDeps []string) []GoSumEntry {
 	directDepMap := make(map[string]bool)
 	for _, dep := range directDeps {
 		directDepMap[dep] = true
 	}
 
-	var transitive []GoSumEntry
-	seenModules := make(map[string]bool)
-
+	highest := make(map[string]GoSumEntry)
 	for _, entry := range goSumEntries {
-		if !directDepMap[entry.ModulePath] && !seenModules[entry.ModulePath] {
-			transitive = append(transitive, entry)
-			seenModules[entry.ModulePath] = true
+		if entry.Hash == "" || directDepMap[entry.ModulePath] {
+			continue
+		}
+		if current, seen := highest[entry.ModulePath]; !seen || newerVersion(entry.Version, current.Version) {
+			highest[entry.ModulePath] = entry
 		}
 	}
 
+	transitive := make([]GoSumEntry, 0, len(highest))
+	for _, entry := range highest {
+		transitive = append(transitive, entry)
+	}
+	sort.Slice(transitive, func(i, j int) bool {
+		return transitive[i].ModulePath < transitive[j].ModulePath
+	})
 	return transitive
 }
+
+// newerVersion reports whether v sorts after w in semantic version order,
+// comparing the strings when they are equal in it (e.g. both invalid).
+func newerVersion(v, w string) bool {
+	if c := semver.Compare(v, w); c != 0 {
+		return c > 0
+	}
+	return v > w
+}
+
+// GoSumLine is an entry of go.sum: the hash of a module zip or, with GoMod
+// set, of its go.mod file.
+type GoSumLine struct {
+	ModulePath string
+	Version    string
+	GoMod      bool
+	Hash       string
+	Line       int
+}
+
+// ParseGoSumLines returns every well-formed entry of go.sum with its line
+// number. Unlike ParseGoSum, the zip and go.mod hashes of a module version
+// are returned as separate lines.
+func ParseGoSumLines(path string) ([]GoSumLine, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrGoSumNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open go.sum: %w", err)
+	}
+	defer file.Close()
+
+	var lines []GoSumLine
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		parts := strings.Fields(scanner.Text())
+		if len(parts) < 3 {
+			continue
+		}
+		version, goMod := strings.CutSuffix(parts[1], "/go.mod")
+		lines = append(lines, GoSumLine{ModulePath: parts[0], Version: version, GoMod: goMod, Hash: parts[2], Line: line})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading go.sum: %w", err)
+	}
+	return lines, nil
+}
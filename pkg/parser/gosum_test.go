@@ -0,0 +1,140 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGoSumKey(t *testing.T) {
+	tests := []struct {
+		path, version string
+		want          string
+	}{
+		{"example.com/m", "v1.2.0", "example.com/m@v1.2.0"},
+		{"example.com/m", "v1.2", "example.com/m@v1.2.0"},
+		{"example.com/m", "v1", "example.com/m@v1.0.0"},
+		{"example.com/m", "v1.2.0+meta", "example.com/m@v1.2.0"},
+		{"example.com/m", "v2.0.3+incompatible", "example.com/m@v2.0.3+incompatible"},
+		{"example.com/m", "v0.0.0-20230101000000-abcdef123456", "example.com/m@v0.0.0-20230101000000-abcdef123456"},
+		{"example.com/m", "latest", "example.com/m@latest"},
+	}
+
+	for _, tt := range tests {
+		if got := GoSumKey(tt.path, tt.version); got != tt.want {
+			t.Errorf("GoSumKey(%q, %q) = %q, want %q", tt.path, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestLookupGoSum(t *testing.T) {
+	entries := make(map[string]GoSumEntry)
+	for _, entry := range []GoSumEntry{
+		{ModulePath: "example.com/incompatible", Version: "v2.0.3+incompatible", Hash: "h1:incompatible"},
+		{ModulePath: "example.com/plain", Version: "v2.1.0", Hash: "h1:plain"},
+		{ModulePath: "example.com/short", Version: "v1.2", Hash: "h1:short"},
+		{ModulePath: "example.com/full", Version: "v1.3.0", Hash: "h1:full"},
+	} {
+		entries[GoSumKey(entry.ModulePath, entry.Version)] = entry
+	}
+
+	tests := []struct {
+		name, path, version string
+		want                string
+	}{
+		{"incompatible on both sides", "example.com/incompatible", "v2.0.3+incompatible", "h1:incompatible"},
+		{"incompatible only in go.sum", "example.com/incompatible", "v2.0.3", "h1:incompatible"},
+		{"incompatible only in go.mod", "example.com/plain", "v2.1.0+incompatible", "h1:plain"},
+		{"incompatible on neither side", "example.com/plain", "v2.1.0", "h1:plain"},
+		{"short version in go.sum", "example.com/short", "v1.2.0", "h1:short"},
+		{"short version on both sides", "example.com/short", "v1.2", "h1:short"},
+		{"short version in go.mod", "example.com/full", "v1.3", "h1:full"},
+		{"short version without incompatible in go.mod", "example.com/plain", "v2.1", "h1:plain"},
+		{"other version", "example.com/full", "v1.3.1", ""},
+		{"other module", "example.com/missing", "v1.3.0", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, ok := LookupGoSum(entries, tt.path, tt.version)
+			if ok != (tt.want != "") || entry.Hash != tt.want {
+				t.Errorf("LookupGoSum(%q, %q) = %q, %v; want %q", tt.path, tt.version, entry.Hash, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGoSumMergesLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		goSum string
+	}{
+		{"zip first", `example.com/m v1.2.0 h1:zip=
+example.com/m v1.2.0/go.mod h1:gomod=
+`},
+		{"go.mod first", `example.com/m v1.2.0/go.mod h1:gomod=
+example.com/m v1.2.0 h1:zip=
+`},
+		{"other lines in between", `example.com/m v1.2.0/go.mod h1:gomod=
+example.com/other v0.1.0 h1:other=
+example.com/other v0.1.0/go.mod h1:othergomod=
+example.com/m v1.2.0 h1:zip=
+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "go.sum")
+			if err := os.WriteFile(path, []byte(tt.goSum), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			entries, err := ParseGoSum(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := GoSumEntry{ModulePath: "example.com/m", Version: "v1.2.0", Hash: "h1:zip=", GoModHash: "h1:gomod="}
+			if got := entries[GoSumKey("example.com/m", "v1.2.0")]; got != want {
+				t.Errorf("entry = %+v, want %+v", got, want)
+			}
+
+			lines, err := ParseGoSumLines(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var zip, goMod int
+			for _, line := range lines {
+				if line.ModulePath != "example.com/m" {
+					continue
+				}
+				if line.GoMod {
+					goMod++
+				} else {
+					zip++
+				}
+			}
+			if zip != 1 || goMod != 1 {
+				t.Errorf("ParseGoSumLines returned %d zip and %d go.mod lines of example.com/m, want 1 of each", zip, goMod)
+			}
+		})
+	}
+}
+
+func TestParseGoSumGoModOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go.sum")
+	if err := os.WriteFile(path, []byte("example.com/m v1.2.0/go.mod h1:gomod=\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ParseGoSum(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := GoSumEntry{ModulePath: "example.com/m", Version: "v1.2.0", GoModHash: "h1:gomod="}
+	if got := entries[GoSumKey("example.com/m", "v1.2.0")]; got != want {
+		t.Errorf("entry = %+v, want %+v", got, want)
+	}
+	if transitive := GetTransitiveDependencies(entries, nil); len(transitive) != 0 {
+		t.Errorf("GetTransitiveDependencies = %+v, want none for a go.mod-only entry", transitive)
+	}
+}
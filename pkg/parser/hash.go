@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+// ErrNotInCache is returned by VerifyHash when the module cache holds no
+// download of the module version to hash.
+var ErrNotInCache = errors.New("not in the module cache")
+
+// HashAlgorithm returns the algorithm prefix of a go.sum hash, such as "h1"
+// for "h1:...", or "" when the hash has none.
+func HashAlgorithm(hash string) string {
+	algorithm, _, found := strings.Cut(hash, ":")
+	if !found {
+		return ""
+	}
+	return algorithm
+}
+
+// checkHash reports what is wrong with a go.sum hash, or "" when it is a
+// well-formed h1 hash: the base64 SHA-256 of the module file tree.
+func checkHash(hash string) string {
+	algorithm := HashAlgorithm(hash)
+	switch algorithm {
+	case "":
+		return fmt.Sprintf("hash %q has no algorithm prefix", hash)
+	case "h1":
+	default:
+		return fmt.Sprintf("unsupported hash algorithm %q", algorithm)
+	}
+
+	sum, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(hash, "h1:"))
+	if err != nil || len(sum) != 32 {
+		return fmt.Sprintf("malformed h1 hash %q", hash)
+	}
+	return ""
+}
+
+// VerifyHash recomputes the hash of a go.sum entry from the downloads in
+// the module cache, the module zip or, for go.mod entries, the .mod file,
+// and returns it for comparison with line.Hash.
+func VerifyHash(modCacheDir string, line GoSumLine) (string, error) {
+	if modCacheDir == "" {
+		return "", ErrNotInCache
+	}
+	escapedPath, err := module.EscapePath(line.ModulePath)
+	if err != nil {
+		return "", err
+	}
+	escapedVersion, err := module.EscapeVersion(line.Version)
+	if err != nil {
+		return "", err
+	}
+	base := filepath.Join(modCacheDir, "cache", "download", filepath.FromSlash(escapedPath), "@v", escapedVersion)
+
+	if line.GoMod {
+		modPath := base + ".mod"
+		if _, err := os.Stat(modPath); err != nil {
+			return "", ErrNotInCache
+		}
+		return dirhash.Hash1([]string{"go.mod"}, func(string) (io.ReadCloser, error) {
+			return os.Open(modPath)
+		})
+	}
+
+	zipPath := base + ".zip"
+	if _, err := os.Stat(zipPath); err != nil {
+		return "", ErrNotInCache
+	}
+	return dirhash.HashZip(zipPath, dirhash.Hash1)
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"golang.org/x/mod/modfile"
@@ -9,12 +10,25 @@ import (
 
 func ParseGoMod(path string) (*modfile.File, error) {
 
-	data, err := os.ReadFile(path)
+	file, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read go.mod file: %w", err)
 	}
+	defer file.Close()
 
-	modFile, err := modfile.Parse(path, data, nil)
+	return ParseGoModReader(file, path)
+}
+
+// ParseGoModReader parses go.mod content from r. The name is only used in
+// error messages, so it may be a placeholder such as "stdin".
+func ParseGoModReader(r io.Reader, name string) (*modfile.File, error) {
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod file: %w", err)
+	}
+
+	modFile, err := modfile.Parse(name, data, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse go.mod file: %w", err)
 	}
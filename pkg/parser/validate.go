@@ -0,0 +1,238 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// Issue is a problem found by ValidateGoMod or ValidateGoSum. Line is 0
+// when the problem is not tied to a line, e.g. a missing go.sum.
+type Issue struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (i Issue) String() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s", i.File, i.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", i.File, i.Line, i.Message)
+}
+
+// ValidateGoMod reports the errors modfile.Parse finds in a go.mod. In
+// strict mode it also reports what the parser tolerates: versions it
+// silently canonicalizes (v1.2 for v1.2.0), duplicate requirements, a
+// missing go directive, a toolchain older than the go version, requiring
+// the main module itself, replace and exclude directives without effect,
+// and replaces that only change the version of a required module. The parsed file is returned when it parses.
+func ValidateGoMod(name string, data []byte, strict bool) (*modfile.File, []Issue) {
+	file := baseName(name)
+
+	type rawVersion struct{ path, version string }
+	var nonCanonical []rawVersion
+	fix := func(path, version string) (string, error) {
+		canonical := module.CanonicalVersion(version)
+		if canonical == "" {
+			return "", &module.ModuleError{Path: path, Err: &module.InvalidVersionError{Version: version, Err: errors.New("must be of the form v1.2.3")}}
+		}
+		if canonical != version {
+			nonCanonical = append(nonCanonical, rawVersion{path, version})
+		}
+		return canonical, nil
+	}
+
+	modFile, err := modfile.Parse(name, data, fix)
+	if err != nil {
+		issues := parseIssues(file, err)
+		if strict {
+			if _, laxErr := modfile.ParseLax(name, data, nil); laxErr == nil {
+				// The go command reads the go.mod files of dependencies
+				// laxly, so consumers of the module never see these.
+				for i := range issues {
+					issues[i].Message += " (ignored when the module is a dependency)"
+				}
+			}
+		}
+		return nil, issues
+	}
+	if !strict {
+		return modFile, nil
+	}
+
+	var issues []Issue
+	add := func(line int, format string, args ...any) {
+		issues = append(issues, Issue{File: file, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, raw := range nonCanonical {
+		add(versionLine(modFile, raw.path), "version %s of %s is not canonical; use %s", raw.version, raw.path, module.CanonicalVersion(raw.version))
+	}
+
+	if modFile.Go == nil {
+		add(0, "no go directive; the go command assumes go 1.16 semantics")
+	} else if modFile.Toolchain != nil {
+		toolchain := strings.TrimPrefix(modFile.Toolchain.Name, "go")
+		if semver.Compare("v"+toolchain, "v"+modFile.Go.Version) < 0 {
+			add(modFile.Toolchain.Syntax.Start.Line, "toolchain %s is older than go %s and has no effect", modFile.Toolchain.Name, modFile.Go.Version)
+		}
+	}
+
+	required := make(map[string]*modfile.Require)
+	for _, req := range modFile.Require {
+		line := req.Syntax.Start.Line
+		if modFile.Module != nil && req.Mod.Path == modFile.Module.Mod.Path {
+			add(line, "the main module %s requires itself", req.Mod.Path)
+		}
+		if first, ok := required[req.Mod.Path]; ok {
+			add(line, "duplicate requirement for %s (also required at line %d); only the higher version is used", req.Mod.Path, first.Syntax.Start.Line)
+			continue
+		}
+		required[req.Mod.Path] = req
+	}
+
+	for _, replace := range modFile.Replace {
+		line := replace.Syntax.Start.Line
+		req := required[replace.Old.Path]
+		switch {
+		case replace.Old == replace.New:
+			add(line, "replace of %s with itself has no effect", replace.Old.Path)
+		case req == nil:
+			add(line, "replace of %s, which is not required directly; it only applies if another dependency requires it", replace.Old.Path)
+		case replace.Old.Version != "" && replace.Old.Version != req.Mod.Version:
+			add(line, "replace of %s@%s does not match the required version %s (line %d); it only applies if another dependency requires %s",
+				replace.Old.Path, replace.Old.Version, req.Mod.Version, req.Syntax.Start.Line, replace.Old.Version)
+		case replace.New.Path == replace.Old.Path:
+			add(line, "replace of %s@%s with version %s of the same module; require %s@%s instead (line %d)",
+				replace.Old.Path, req.Mod.Version, replace.New.Version, replace.New.Path, replace.New.Version, req.Syntax.Start.Line)
+		}
+	}
+
+	for _, exclude := range modFile.Exclude {
+		if required[exclude.Mod.Path] == nil {
+			add(exclude.Syntax.Start.Line, "exclude of %s@%s, which is not required directly; it only applies if another dependency requires it", exclude.Mod.Path, exclude.Mod.Version)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return modFile, issues
+}
+
+// ValidateGoSum checks that the go.sum at path is well-formed and holds the
+// go.mod checksum of every requirement of modFile, which the go command
+// needs before it can load the module graph.
+func ValidateGoSum(path string, modFile *modfile.File) []Issue {
+	file := baseName(path)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if len(modFile.Require) == 0 {
+			return nil
+		}
+		return []Issue{{File: file, Message: "missing; run 'go mod tidy' to create it"}}
+	}
+	if err != nil {
+		return []Issue{{File: file, Message: fmt.Sprintf("failed to read: %v", err)}}
+	}
+
+	var issues []Issue
+	sums := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		fields := strings.Fields(text)
+		switch {
+		case len(fields) != 3:
+			issues = append(issues, Issue{File: file, Line: line, Message: "malformed line, expected module, version and hash"})
+		case checkHash(fields[2]) != "":
+			issues = append(issues, Issue{File: file, Line: line, Message: fmt.Sprintf("%s for %s %s", checkHash(fields[2]), fields[0], fields[1])})
+		default:
+			sums[fields[0]+" "+fields[1]] = true
+		}
+	}
+
+	replacements := make(map[module.Version]module.Version)
+	for _, replace := range modFile.Replace {
+		replacements[replace.Old] = replace.New
+	}
+
+	for _, req := range modFile.Require {
+		mod := req.Mod
+		if replacement, ok := replacements[mod]; ok {
+			mod = replacement
+		} else if replacement, ok := replacements[module.Version{Path: mod.Path}]; ok {
+			mod = replacement
+		}
+		if mod.Version == "" {
+			// Replaced by a local directory, which has no checksum.
+			continue
+		}
+		if !sums[mod.Path+" "+mod.Version+"/go.mod"] {
+			issues = append(issues, Issue{File: file, Message: fmt.Sprintf("no checksum for %s@%s/go.mod (required at go.mod:%d); run 'go mod download %s'",
+				mod.Path, mod.Version, req.Syntax.Start.Line, mod.Path)})
+		}
+	}
+	return issues
+}
+
+// parseIssues splits a modfile error list into one issue per error.
+func parseIssues(file string, err error) []Issue {
+	var list modfile.ErrorList
+	if !errors.As(err, &list) {
+		return []Issue{{File: file, Message: err.Error()}}
+	}
+
+	issues := make([]Issue, 0, len(list))
+	for _, e := range list {
+		message := e.Err.Error()
+		if e.ModPath != "" {
+			message = e.ModPath + ": " + message
+		}
+		issues = append(issues, Issue{File: file, Line: e.Pos.Line, Message: message})
+	}
+	return issues
+}
+
+// versionLine returns the line of the first directive naming path.
+func versionLine(modFile *modfile.File, path string) int {
+	for _, req := range modFile.Require {
+		if req.Mod.Path == path {
+			return req.Syntax.Start.Line
+		}
+	}
+	for _, replace := range modFile.Replace {
+		if replace.Old.Path == path || replace.New.Path == path {
+			return replace.Syntax.Start.Line
+		}
+	}
+	for _, exclude := range modFile.Exclude {
+		if exclude.Mod.Path == path {
+			return exclude.Syntax.Start.Line
+		}
+	}
+	if modFile.Module != nil && path == modFile.Module.Mod.Path && len(modFile.Retract) > 0 {
+		return modFile.Retract[0].Syntax.Start.Line
+	}
+	return 0
+}
+
+func baseName(path string) string {
+	if i := strings.LastIndexAny(path, `/\`); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
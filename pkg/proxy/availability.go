@@ -0,0 +1,65 @@
+package proxy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// VersionAvailable reports whether the proxy still serves the version's
+// .info file. A 404 or 410 means the version was removed and fresh builds
+// that need it will fail; retracted versions remain available.
+func (c *Client) VersionAvailable(ctx context.Context, modulePath, version string) (bool, error) {
+	_, err := c.Info(ctx, modulePath, version)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, ErrNotFound):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// AvailabilityCache remembers on disk which module versions the proxy
+// served, so repeated checks skip the network for up to TTL. Versions found
+// missing are not cached and are asked again every time.
+type AvailabilityCache struct {
+	Client *Client
+	Dir    string
+	TTL    time.Duration
+}
+
+// NewAvailabilityCache returns a cache in the user cache directory, or nil
+// if that directory cannot be determined.
+func NewAvailabilityCache(client *Client, ttl time.Duration) *AvailabilityCache {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil
+	}
+	return &AvailabilityCache{Client: client, Dir: filepath.Join(dir, "goviz", "versions"), TTL: ttl}
+}
+
+func (a *AvailabilityCache) VersionAvailable(ctx context.Context, modulePath, version string) (bool, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s", a.Client.BaseURL, modulePath, version)
+	path := filepath.Join(a.Dir, hex.EncodeToString(h.Sum(nil)))
+
+	if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) <= a.TTL {
+		return true, nil
+	}
+
+	available, err := a.Client.VersionAvailable(ctx, modulePath, version)
+	if err == nil && available {
+		if os.MkdirAll(a.Dir, 0755) == nil && os.WriteFile(path, nil, 0644) == nil {
+			now := time.Now()
+			os.Chtimes(path, now, now)
+		}
+	}
+	return available, err
+}
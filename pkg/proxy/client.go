@@ -0,0 +1,268 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+const DefaultProxyURL = "https://proxy.golang.org"
+
+// ErrNotFound is returned when the proxy reports that a module or version
+// does not exist (HTTP 404 or 410).
+var ErrNotFound = errors.New("not found in module proxy")
+
+// ErrPrivate is returned for modules matching the client's Private patterns,
+// which are never requested from the proxy.
+var ErrPrivate = errors.New("private module, not requested from module proxy")
+
+type Info struct {
+	Version string    `json:"Version"`
+	Time    time.Time `json:"Time"`
+}
+
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+
+	// ModCacheDir is the local module cache (GOMODCACHE). When set, go.mod
+	// files already downloaded by the go command are read from it first.
+	ModCacheDir string
+
+	// Private is a comma-separated list of module path prefix globs, as in
+	// GONOPROXY and GOPRIVATE. Matching modules are only read from the
+	// module cache.
+	Private string
+}
+
+// NewClient returns a client for the first HTTP(S) entry in GOPROXY, falling
+// back to proxy.golang.org. Modules matching GONOPROXY, or GOPRIVATE when
+// it is unset, are not requested from the proxy.
+func NewClient() *Client {
+	private := os.Getenv("GONOPROXY")
+	if private == "" {
+		private = os.Getenv("GOPRIVATE")
+	}
+	return &Client{
+		BaseURL:     proxyFromEnv(os.Getenv("GOPROXY")),
+		HTTPClient:  &http.Client{Timeout: 15 * time.Second},
+		ModCacheDir: ModCacheDir(),
+		Private:     private,
+	}
+}
+
+// ModCacheDir returns the local module cache directory (GOMODCACHE), or ""
+// if it cannot be determined.
+func ModCacheDir() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	if gopath := os.Getenv("GOPATH"); gopath != "" {
+		return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, "go", "pkg", "mod")
+	}
+	return ""
+}
+
+func proxyFromEnv(goproxy string) string {
+	for _, entry := range strings.FieldsFunc(goproxy, func(r rune) bool { return r == ',' || r == '|' }) {
+		entry = strings.TrimSpace(entry)
+		if strings.HasPrefix(entry, "https://") || strings.HasPrefix(entry, "http://") {
+			return strings.TrimSuffix(entry, "/")
+		}
+	}
+	return DefaultProxyURL
+}
+
+// List returns the tagged versions of a module, sorted by semver.
+func (c *Client) List(ctx context.Context, modulePath string) ([]string, error) {
+	data, err := c.get(ctx, modulePath, "@v/list")
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if v := strings.TrimSpace(line); semver.IsValid(v) {
+			versions = append(versions, v)
+		}
+	}
+	semver.Sort(versions)
+
+	return versions, nil
+}
+
+func (c *Client) Latest(ctx context.Context, modulePath string) (*Info, error) {
+	data, err := c.get(ctx, modulePath, "@latest")
+	if err != nil {
+		return nil, err
+	}
+	return decodeInfo(data)
+}
+
+// LatestVersion returns the version the go command considers latest: the
+// highest tagged release, preferring stable versions, or the latest
+// pseudo-version of a module without tags.
+func (c *Client) LatestVersion(ctx context.Context, modulePath string) (string, error) {
+	versions, err := c.List(ctx, modulePath)
+	if err != nil {
+		return "", err
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		if semver.Prerelease(versions[i]) == "" {
+			return versions[i], nil
+		}
+	}
+	if len(versions) > 0 {
+		return versions[len(versions)-1], nil
+	}
+
+	info, err := c.Latest(ctx, modulePath)
+	if err != nil {
+		return "", err
+	}
+	return info.Version, nil
+}
+
+// LatestReleaseTime returns when the LatestVersion of a module was
+// published.
+func (c *Client) LatestReleaseTime(ctx context.Context, modulePath string) (time.Time, error) {
+	version, err := c.LatestVersion(ctx, modulePath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	info, err := c.Info(ctx, modulePath, version)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.Time, nil
+}
+
+func (c *Client) Info(ctx context.Context, modulePath, version string) (*Info, error) {
+	escaped, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %s: %w", version, err)
+	}
+	data, err := c.get(ctx, modulePath, "@v/"+escaped+".info")
+	if err != nil {
+		return nil, err
+	}
+	return decodeInfo(data)
+}
+
+// GoMod returns the go.mod file of a module version, preferring the local
+// module cache over the network.
+func (c *Client) GoMod(ctx context.Context, modulePath, version string) ([]byte, error) {
+	escaped, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %s: %w", version, err)
+	}
+
+	if data, err := c.readCache(modulePath, escaped+".mod"); err == nil {
+		return data, nil
+	}
+
+	return c.get(ctx, modulePath, "@v/"+escaped+".mod")
+}
+
+func (c *Client) readCache(modulePath, file string) ([]byte, error) {
+	if c.ModCacheDir == "" {
+		return nil, os.ErrNotExist
+	}
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(c.ModCacheDir, "cache", "download", filepath.FromSlash(escaped), "@v", file))
+}
+
+func (c *Client) get(ctx context.Context, modulePath, suffix string) ([]byte, error) {
+	if c.Private != "" && module.MatchPrefixPatterns(c.Private, modulePath) {
+		return nil, fmt.Errorf("%s: %w", modulePath, ErrPrivate)
+	}
+
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid module path %s: %w", modulePath, err)
+	}
+
+	url := fmt.Sprintf("%s/%s/%s", c.BaseURL, escaped, suffix)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query module proxy: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, fmt.Errorf("%s %s: %w", modulePath, suffix, ErrNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("module proxy returned %s for %s", resp.Status, url)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read proxy response: %w", err)
+	}
+
+	return data, nil
+}
+
+func decodeInfo(data []byte) (*Info, error) {
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to decode version info: %w", err)
+	}
+	return &info, nil
+}
+
+// LatestCompatible returns the highest version in versions that shares the
+// major version of current, so upgrading does not change the import path.
+// Pre-releases are only considered when current is itself a pre-release.
+func LatestCompatible(versions []string, current string) string {
+	major := semver.Major(current)
+	allowPrerelease := semver.Prerelease(current) != "" && !module.IsPseudoVersion(current)
+
+	var candidates []string
+	for _, v := range versions {
+		if semver.Major(v) != major {
+			continue
+		}
+		if semver.Prerelease(v) != "" && !allowPrerelease {
+			continue
+		}
+		candidates = append(candidates, v)
+	}
+
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return semver.Compare(candidates[i], candidates[j]) < 0
+	})
+
+	latest := candidates[len(candidates)-1]
+	if semver.Compare(latest, current) <= 0 {
+		return ""
+	}
+	return latest
+}
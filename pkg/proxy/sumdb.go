@@ -0,0 +1,230 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/sumdb"
+	"golang.org/x/mod/sumdb/note"
+)
+
+// ErrChecksumDBOff is returned by NewChecksumDB when GOSUMDB=off.
+var ErrChecksumDBOff = errors.New("checksum database disabled (GOSUMDB=off)")
+
+// sumGolangOrgKey is the verifier key of sum.golang.org built into the go
+// command.
+const sumGolangOrgKey = "sum.golang.org+033de0ae+Ac4zctda0e5eza+HJyk9SxEdh+s3Ux18htTTAD8OuAn8"
+
+// ChecksumDB looks up module versions in the checksum database named by
+// GOSUMDB. Every record is verified against the signed tree of the
+// database's transparency log, as the go command does, so a hash it returns
+// is the one the log committed to publicly.
+type ChecksumDB struct {
+	client *sumdb.Client
+	ops    *sumdbOps
+}
+
+// NewChecksumDB returns the checksum database named by gosumdb, a GOSUMDB
+// value, sum.golang.org when it is empty. Like the go command, it is
+// reached through the module proxy of client when the proxy mirrors it,
+// and directly otherwise. Modules matching the nosumdb patterns, a
+// GONOSUMDB value, are not looked up.
+func NewChecksumDB(client *Client, gosumdb, nosumdb string) (*ChecksumDB, error) {
+	gosumdb = strings.TrimSpace(gosumdb)
+	if gosumdb == "" {
+		gosumdb = "sum.golang.org"
+	}
+	if gosumdb == "off" {
+		return nil, ErrChecksumDBOff
+	}
+
+	key, directURL, _ := strings.Cut(gosumdb, " ")
+	switch key {
+	case "sum.golang.org":
+		key = sumGolangOrgKey
+	case "sum.golang.google.cn":
+		key = sumGolangOrgKey
+		if directURL == "" {
+			directURL = "https://sum.golang.google.cn"
+		}
+	}
+	verifier, err := note.NewVerifier(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GOSUMDB %q: %w", gosumdb, err)
+	}
+	if directURL == "" {
+		directURL = "https://" + verifier.Name()
+	}
+
+	ops := &sumdbOps{
+		name:       verifier.Name(),
+		key:        []byte(key),
+		proxyURL:   client.BaseURL,
+		directURL:  strings.TrimSuffix(directURL, "/"),
+		httpClient: client.HTTPClient,
+		cacheDir:   client.ModCacheDir,
+		config:     make(map[string][]byte),
+		notFound:   make(map[string]bool),
+	}
+	db := &ChecksumDB{client: sumdb.NewClient(ops), ops: ops}
+	db.client.SetGONOSUMDB(nosumdb)
+	return db, nil
+}
+
+// Name returns the name of the database, such as sum.golang.org.
+func (db *ChecksumDB) Name() string {
+	return db.ops.name
+}
+
+// RecordedHash returns the hash the database records for the module zip of
+// path@version, or "" when the database has no record of it. Modules
+// excluded by GONOSUMDB return ErrPrivate.
+func (db *ChecksumDB) RecordedHash(ctx context.Context, path, version string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	lines, err := db.client.Lookup(path, version)
+	if errors.Is(err, sumdb.ErrGONOSUMDB) {
+		return "", fmt.Errorf("%s: %w", path, ErrPrivate)
+	}
+	if err != nil {
+		if db.ops.isNotFound(path, version) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	for _, line := range lines {
+		if fields := strings.Fields(line); len(fields) == 3 && fields[1] == version {
+			return fields[2], nil
+		}
+	}
+	return "", nil
+}
+
+// sumdbOps implements sumdb.ClientOps. The signed tree is kept in memory;
+// records and tiles are read from the go command's download cache when
+// present and otherwise fetched, without writing to that cache.
+type sumdbOps struct {
+	name       string
+	key        []byte
+	proxyURL   string
+	directURL  string
+	httpClient *http.Client
+	cacheDir   string
+
+	baseOnce sync.Once
+	baseURL  string
+
+	mu       sync.Mutex
+	config   map[string][]byte
+	notFound map[string]bool
+}
+
+// base returns the URL the database is read from: the proxy when it
+// reports mirroring the database, the database itself otherwise.
+func (o *sumdbOps) base() string {
+	o.baseOnce.Do(func() {
+		o.baseURL = o.directURL
+		if o.proxyURL == "" {
+			return
+		}
+		mirror := o.proxyURL + "/sumdb/" + o.name
+		if _, err := o.fetch(mirror + "/supported"); err == nil {
+			o.baseURL = mirror
+		}
+	})
+	return o.baseURL
+}
+
+func (o *sumdbOps) fetch(url string) ([]byte, error) {
+	resp, err := o.httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query checksum database: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, fmt.Errorf("%s: %w", url, ErrNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("checksum database returned %s for %s", resp.Status, url)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checksum database response: %w", err)
+	}
+	return data, nil
+}
+
+func (o *sumdbOps) ReadRemote(path string) ([]byte, error) {
+	data, err := o.fetch(o.base() + path)
+	if errors.Is(err, ErrNotFound) && strings.HasPrefix(path, "/lookup/") {
+		o.mu.Lock()
+		o.notFound[path] = true
+		o.mu.Unlock()
+	}
+	return data, err
+}
+
+// isNotFound reports whether the lookup of path@version failed because the
+// database has no record of it.
+func (o *sumdbOps) isNotFound(path, version string) bool {
+	escapedPath, err := module.EscapePath(path)
+	if err != nil {
+		return false
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return false
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.notFound["/lookup/"+escapedPath+"@"+escapedVersion]
+}
+
+func (o *sumdbOps) ReadConfig(file string) ([]byte, error) {
+	if file == "key" {
+		return o.key, nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.config[file], nil
+}
+
+func (o *sumdbOps) WriteConfig(file string, old, new []byte) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !bytes.Equal(o.config[file], old) {
+		return sumdb.ErrWriteConflict
+	}
+	o.config[file] = new
+	return nil
+}
+
+func (o *sumdbOps) ReadCache(file string) ([]byte, error) {
+	if o.cacheDir == "" {
+		return nil, os.ErrNotExist
+	}
+	return os.ReadFile(filepath.Join(o.cacheDir, "cache", "download", "sumdb", filepath.FromSlash(file)))
+}
+
+func (o *sumdbOps) WriteCache(file string, data []byte) {}
+
+func (o *sumdbOps) Log(msg string) {}
+
+// SecurityError is called when the database contradicts its signed tree;
+// the failing Lookup returns sumdb.ErrSecurity, so there is nothing to add.
+func (o *sumdbOps) SecurityError(msg string) {}
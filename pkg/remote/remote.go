@@ -0,0 +1,251 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrUnsupportedHost is returned for repositories that are not hosted on
+// GitHub or GitLab, directly or through a go-import redirect.
+var ErrUnsupportedHost = errors.New("unsupported repository host (supported: github.com, gitlab.com)")
+
+// Repo locates a go.mod file in a remote git repository.
+type Repo struct {
+	Host string
+	Path string
+	Dir  string
+	Ref  string
+}
+
+func (r Repo) String() string {
+	s := r.Host + "/" + r.Path
+	if r.Dir != "" {
+		s += "/" + r.Dir
+	}
+	return s + "@" + r.Ref
+}
+
+// Files holds the module files downloaded from a repository. GoSum is nil
+// when the repository has no go.sum.
+type Files struct {
+	Repo  Repo
+	GoMod []byte
+	GoSum []byte
+}
+
+type Client struct {
+	HTTPClient *http.Client
+}
+
+func NewClient() *Client {
+	return &Client{HTTPClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Fetch downloads go.mod and go.sum for a spec of the form
+// host/owner/name[/dir][@ref]. The ref defaults to the default branch.
+// Import paths on other hosts (e.g. golang.org/x/mod) are resolved through
+// their go-import meta tag.
+func (c *Client) Fetch(ctx context.Context, spec string) (*Files, error) {
+	repo, err := c.Resolve(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	goMod, err := c.get(ctx, rawURL(repo, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch go.mod from %s: %w", repo, err)
+	}
+
+	goSum, err := c.get(ctx, rawURL(repo, "go.sum"))
+	if errors.Is(err, errNotFound) {
+		goSum = nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to fetch go.sum from %s: %w", repo, err)
+	}
+
+	return &Files{Repo: repo, GoMod: goMod, GoSum: goSum}, nil
+}
+
+// Resolve parses a repository spec. GitLab repositories are assumed to
+// live directly under a group (group/name); anything after that is a
+// subdirectory.
+func (c *Client) Resolve(ctx context.Context, spec string) (Repo, error) {
+	path, ref, _ := strings.Cut(spec, "@")
+	if ref == "" {
+		ref = "HEAD"
+	}
+	path = strings.TrimPrefix(path, "https://")
+	path = strings.TrimPrefix(path, "http://")
+	path = strings.Trim(strings.TrimSuffix(path, ".git"), "/")
+
+	if repo, ok := hostedRepo(path, ref); ok {
+		return repo, nil
+	}
+
+	host, _, _ := strings.Cut(path, "/")
+	if host == "github.com" || host == "gitlab.com" {
+		return Repo{}, fmt.Errorf("invalid repository %q: expected %s/owner/name[@ref]", spec, host)
+	}
+
+	prefix, repoURL, err := c.goImport(ctx, path)
+	if err != nil {
+		return Repo{}, err
+	}
+
+	repoPath := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(repoURL, "https://"), "http://"), ".git")
+	repo, ok := hostedRepo(repoPath, ref)
+	if !ok {
+		return Repo{}, fmt.Errorf("%s resolves to %s: %w", path, repoURL, ErrUnsupportedHost)
+	}
+	repo.Dir = strings.Trim(strings.TrimPrefix(path, prefix), "/")
+	return repo, nil
+}
+
+func hostedRepo(path, ref string) (Repo, bool) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 3 || (parts[0] != "github.com" && parts[0] != "gitlab.com") {
+		return Repo{}, false
+	}
+	return Repo{
+		Host: parts[0],
+		Path: parts[1] + "/" + parts[2],
+		Dir:  strings.Join(parts[3:], "/"),
+		Ref:  ref,
+	}, true
+}
+
+func rawURL(repo Repo, file string) string {
+	if repo.Dir != "" {
+		file = repo.Dir + "/" + file
+	}
+	if repo.Host == "gitlab.com" {
+		return fmt.Sprintf("https://gitlab.com/%s/-/raw/%s/%s", repo.Path, repo.Ref, file)
+	}
+	return fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s", repo.Path, repo.Ref, file)
+}
+
+var (
+	goImportPattern = regexp.MustCompile(`<meta\s+name="go-import"\s+content="([^"]+)"`)
+	goSourcePattern = regexp.MustCompile(`<meta\s+name="go-source"\s+content="([^"]+)"`)
+)
+
+// goImport resolves an import path to the root of its repository using the
+// go-import meta tag, as 'go get' does.
+func (c *Client) goImport(ctx context.Context, path string) (prefix, repoURL string, err error) {
+	body, err := c.get(ctx, "https://"+path+"?go-get=1")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	for _, fields := range metaTags(goImportPattern, string(body), path) {
+		if fields[1] == "git" {
+			return fields[0], fields[2], nil
+		}
+	}
+
+	return "", "", fmt.Errorf("%s: no git go-import meta tag found: %w", path, ErrUnsupportedHost)
+}
+
+// metaTags returns the fields of the meta tags matching pattern whose
+// import prefix covers path.
+func metaTags(pattern *regexp.Regexp, body, path string) [][]string {
+	var tags [][]string
+	for _, match := range pattern.FindAllStringSubmatch(body, -1) {
+		fields := strings.Fields(match[1])
+		if len(fields) < 3 {
+			continue
+		}
+		if path == fields[0] || strings.HasPrefix(path, fields[0]+"/") {
+			tags = append(tags, fields)
+		}
+	}
+	return tags
+}
+
+// RepoURL returns the browsable source repository of a module. Modules on
+// github.com, gitlab.com and bitbucket.org map directly to their
+// repository. Other import paths (e.g. gopkg.in or k8s.io) are resolved
+// through the home page of their go-source meta tag or, failing that, the
+// repository root of their go-import tag, following redirects as 'go get'
+// does.
+func (c *Client) RepoURL(ctx context.Context, modulePath string) (string, error) {
+	parts := strings.Split(modulePath, "/")
+	switch parts[0] {
+	case "github.com", "gitlab.com", "bitbucket.org":
+		if len(parts) < 3 {
+			return "", fmt.Errorf("invalid module path %q", modulePath)
+		}
+		return "https://" + strings.Join(parts[:3], "/"), nil
+	}
+
+	body, err := c.get(ctx, "https://"+modulePath+"?go-get=1")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", modulePath, err)
+	}
+
+	for _, fields := range metaTags(goSourcePattern, string(body), modulePath) {
+		if home := sourceHome(fields); home != "" {
+			return home, nil
+		}
+	}
+	// go-import: "prefix vcs repo-root"; "mod" roots are module proxies.
+	for _, fields := range metaTags(goImportPattern, string(body), modulePath) {
+		if fields[1] != "mod" && strings.HasPrefix(fields[2], "http") {
+			return strings.TrimSuffix(strings.TrimSuffix(fields[2], "/"), ".git"), nil
+		}
+	}
+
+	return "", fmt.Errorf("%s: no go-import or go-source meta tag found", modulePath)
+}
+
+// sourceHome returns the repository home page of a go-source meta tag
+// ("prefix home directory file"). When the home is "_", as on gopkg.in, it
+// is derived from the directory template, e.g.
+// https://github.com/go-yaml/yaml/tree/v3{/dir} gives
+// https://github.com/go-yaml/yaml.
+func sourceHome(fields []string) string {
+	if strings.HasPrefix(fields[1], "http") {
+		return strings.TrimSuffix(fields[1], "/")
+	}
+
+	dir, _, _ := strings.Cut(fields[2], "{")
+	if !strings.HasPrefix(dir, "http") {
+		return ""
+	}
+	for _, marker := range []string{"/-/tree/", "/tree/", "/src/"} {
+		if i := strings.Index(dir, marker); i >= 0 {
+			return dir[:i]
+		}
+	}
+	return strings.TrimSuffix(dir, "/")
+}
+
+var errNotFound = errors.New("not found")
+
+func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%s: %w", url, errNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
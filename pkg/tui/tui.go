@@ -0,0 +1,377 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"goviz/pkg/graph"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const helpLine = "↑/↓ move • enter expand • ←/→ collapse/expand • / filter • esc clear • q quit"
+
+// row is one visible line of the tree. Rows are identified by their path
+// from the root so that a module reachable along several requirement paths
+// can be expanded independently at each position.
+type row struct {
+	node        *graph.EnhancedNode
+	path        string
+	depth       int
+	hasChildren bool
+}
+
+type model struct {
+	depGraph *graph.EnhancedDependencyGraph
+	expanded map[string]bool
+	rows     []row
+	cursor   int
+	offset   int
+
+	filter    string
+	filtering bool
+
+	width  int
+	height int
+}
+
+// Run starts the interactive browser and blocks until the user quits.
+func Run(depGraph *graph.EnhancedDependencyGraph) error {
+	m := &model{
+		depGraph: depGraph,
+		expanded: map[string]bool{depGraph.Root.Name: true},
+		width:    100,
+		height:   30,
+	}
+	m.refresh()
+
+	if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {
+		return fmt.Errorf("failed to run TUI: %w", err)
+	}
+	return nil
+}
+
+func (m *model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		if msg.Width > 0 && msg.Height > 0 {
+			m.width, m.height = msg.Width, msg.Height
+			m.scroll()
+		}
+
+	case tea.KeyMsg:
+		if m.filtering {
+			return m, m.updateFilter(msg)
+		}
+
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "up", "k":
+			m.move(-1)
+		case "down", "j":
+			m.move(1)
+		case "pgup":
+			m.move(-m.treeHeight())
+		case "pgdown":
+			m.move(m.treeHeight())
+		case "home", "g":
+			m.move(-len(m.rows))
+		case "end", "G":
+			m.move(len(m.rows))
+		case "enter", " ":
+			m.toggle()
+		case "right", "l":
+			m.setExpanded(true)
+		case "left", "h":
+			m.setExpanded(false)
+		case "/":
+			m.filtering = true
+		case "esc":
+			m.filter = ""
+			m.refresh()
+		}
+	}
+
+	return m, nil
+}
+
+func (m *model) updateFilter(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return tea.Quit
+	case tea.KeyEnter:
+		m.filtering = false
+	case tea.KeyEsc:
+		m.filtering = false
+		m.filter = ""
+	case tea.KeyBackspace:
+		if m.filter != "" {
+			runes := []rune(m.filter)
+			m.filter = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.filter += string(msg.Runes)
+	default:
+		return nil
+	}
+
+	m.cursor = 0
+	m.refresh()
+	return nil
+}
+
+func (m *model) move(delta int) {
+	m.cursor += delta
+	if m.cursor >= len(m.rows) {
+		m.cursor = len(m.rows) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	m.scroll()
+}
+
+func (m *model) toggle() {
+	if m.cursor < len(m.rows) {
+		m.setExpanded(!m.expanded[m.rows[m.cursor].path])
+	}
+}
+
+func (m *model) setExpanded(expanded bool) {
+	if m.filter != "" || m.cursor >= len(m.rows) {
+		return
+	}
+	current := m.rows[m.cursor]
+	if !current.hasChildren {
+		return
+	}
+	m.expanded[current.path] = expanded
+	m.refresh()
+}
+
+// refresh rebuilds the visible rows: the expanded tree, or a flat list of
+// matching modules while a filter is set.
+func (m *model) refresh() {
+	m.rows = m.rows[:0]
+
+	if m.filter != "" {
+		needle := strings.ToLower(m.filter)
+		for _, node := range m.depGraph.SortedNodes("name") {
+			if strings.Contains(strings.ToLower(node.Name), needle) {
+				m.rows = append(m.rows, row{node: node, path: node.Name})
+			}
+		}
+	} else {
+		root := m.depGraph.EnhancedNodes[m.depGraph.Root.Name]
+		m.appendRows(root, root.Name, 0, map[string]bool{})
+	}
+
+	m.move(0)
+}
+
+// appendRows adds node and, if expanded, its children. Modules already on
+// the current path are shown but cannot be expanded, which keeps cycles in
+// the requirement graph finite.
+func (m *model) appendRows(node *graph.EnhancedNode, path string, depth int, ancestors map[string]bool) {
+	children := m.depGraph.ChildNodes(node.Name)
+	expandable := len(children) > 0 && !ancestors[node.Name]
+	m.rows = append(m.rows, row{node: node, path: path, depth: depth, hasChildren: expandable})
+
+	if !expandable || !m.expanded[path] {
+		return
+	}
+
+	ancestors[node.Name] = true
+	for _, child := range children {
+		m.appendRows(child, path+" > "+child.Name, depth+1, ancestors)
+	}
+	delete(ancestors, node.Name)
+}
+
+func (m *model) treeHeight() int {
+	// Title, separator, filter/help line.
+	if h := m.height - 3; h > 1 {
+		return h
+	}
+	return 1
+}
+
+func (m *model) scroll() {
+	height := m.treeHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+}
+
+func (m *model) View() string {
+	treeWidth := m.width * 3 / 5
+	detailWidth := m.width - treeWidth - 3
+	height := m.treeHeight()
+
+	tree := m.treeLines(treeWidth, height)
+	details := m.detailLines(detailWidth)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", truncate(fmt.Sprintf("goviz • %s • %d dependencies", m.depGraph.ModuleName, len(m.depGraph.EnhancedNodes)-1), m.width))
+	fmt.Fprintf(&b, "%s\n", strings.Repeat("─", m.width))
+
+	for i := 0; i < height; i++ {
+		var left, right string
+		if i < len(tree) {
+			left = tree[i]
+		}
+		if i < len(details) {
+			right = details[i]
+		}
+		fmt.Fprintf(&b, "%s │ %s\n", pad(left, treeWidth), truncate(right, detailWidth))
+	}
+
+	switch {
+	case m.filtering:
+		fmt.Fprintf(&b, "/%s█", m.filter)
+	case m.filter != "":
+		fmt.Fprintf(&b, "%s", truncate(fmt.Sprintf("filter: %s (%d matches) • esc clear • q quit", m.filter, len(m.rows)), m.width))
+	default:
+		fmt.Fprintf(&b, "%s", truncate(helpLine, m.width))
+	}
+
+	return b.String()
+}
+
+func (m *model) treeLines(width, height int) []string {
+	var lines []string
+	for i := m.offset; i < len(m.rows) && i < m.offset+height; i++ {
+		r := m.rows[i]
+
+		marker := "  "
+		if r.hasChildren {
+			marker = "▸ "
+			if m.expanded[r.path] {
+				marker = "▾ "
+			}
+		}
+
+		label := r.node.Name
+		if r.node.Version != "" && r.node.Name != m.depGraph.Root.Name {
+			label += "@" + r.node.Version
+		}
+		if len(r.node.SecurityIssues) > 0 {
+			label += " !"
+		}
+
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		lines = append(lines, truncate(cursor+strings.Repeat("  ", r.depth)+marker+label, width))
+	}
+	return lines
+}
+
+func (m *model) detailLines(width int) []string {
+	if m.cursor >= len(m.rows) {
+		return []string{"No matching modules"}
+	}
+	node := m.rows[m.cursor].node
+
+	lines := []string{node.Name, ""}
+	if node.Name == m.depGraph.Root.Name {
+		lines = append(lines, "Main module")
+		if m.depGraph.ModuleGoVersion != "" {
+			lines = append(lines, "Go version: "+m.depGraph.ModuleGoVersion)
+		}
+		return lines
+	}
+
+	kind := "indirect"
+	if node.Direct {
+		kind = "direct"
+	}
+	license := node.License
+	if license == "" {
+		license = "Unknown"
+	}
+
+	lines = append(lines,
+		"Version:  "+node.Version,
+		"Type:     "+kind,
+		"License:  "+license,
+	)
+	if node.IsPseudoVersion && !node.PseudoVersionTime.IsZero() {
+		lines = append(lines, "Commit:   "+node.PseudoVersionRev+" ("+node.PseudoVersionTime.Format("2006-01-02")+")")
+	}
+	if node.UpdateAvailable != "" {
+		lines = append(lines, "Update:   "+node.UpdateAvailable)
+	}
+	if node.DefinedAtLine > 0 {
+		lines = append(lines, fmt.Sprintf("go.mod:   line %d", node.DefinedAtLine))
+	}
+	if node.Comment != "" {
+		lines = append(lines, wrap("Comment:  "+node.Comment, width)...)
+	}
+	if node.TestOnly {
+		lines = append(lines, "Test-only dependency")
+	}
+
+	lines = append(lines, "")
+	if len(node.SecurityIssues) == 0 {
+		lines = append(lines, "No known security issues")
+	} else {
+		lines = append(lines, fmt.Sprintf("Security issues (%d):", len(node.SecurityIssues)))
+		for _, issue := range node.SecurityIssues {
+			lines = append(lines, fmt.Sprintf("• %s [%s]", issue.ID, issue.Severity))
+			lines = append(lines, wrap("  "+issue.Description, width)...)
+			if issue.FixedIn != "" {
+				lines = append(lines, "  Fixed in: "+issue.FixedIn)
+			}
+		}
+	}
+
+	for _, conflict := range node.Conflicts {
+		lines = append(lines, "", fmt.Sprintf("Conflict: %s vs %s", conflict.CurrentVersion, conflict.ConflictVersion))
+	}
+
+	return lines
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 {
+		return ""
+	}
+	if len(runes) <= width {
+		return s
+	}
+	if width == 1 {
+		return "…"
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+func pad(s string, width int) string {
+	if n := len([]rune(s)); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+func wrap(s string, width int) []string {
+	if width <= 0 {
+		return nil
+	}
+	var lines []string
+	runes := []rune(s)
+	for len(runes) > width {
+		lines = append(lines, string(runes[:width]))
+		runes = runes[width:]
+	}
+	return append(lines, string(runes))
+}